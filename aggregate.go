@@ -0,0 +1,92 @@
+package mson
+
+import (
+	"math"
+	"reflect"
+)
+
+func aggregateValue(value interface{}, verb string, target reflect.Type, fieldName string) (interface{}, error) {
+	elems, err := elements(value, fieldName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if verb == "count" {
+		return convertNumber(float64(len(elems)), target, fieldName)
+	}
+
+	var result float64
+
+	switch verb {
+	case "min":
+		result = math.Inf(1)
+	case "max":
+		result = math.Inf(-1)
+	}
+
+	for i, e := range elems {
+		n, ok := numberOf(e)
+
+		if !ok {
+			return nil, errorf(ErrTypeMismatch, "mson: element %d of field %s is not a number", i, fieldName)
+		}
+
+		switch verb {
+		case "sum", "avg":
+			result += n
+		case "min":
+			result = math.Min(result, n)
+		case "max":
+			result = math.Max(result, n)
+		}
+	}
+
+	if len(elems) == 0 {
+		result = 0
+	} else if verb == "avg" {
+		result /= float64(len(elems))
+	}
+
+	return convertNumber(result, target, fieldName)
+}
+
+// numberOf returns the value of an element holding a number of any kind.
+func numberOf(e interface{}) (float64, bool) {
+	if n, ok := e.(float64); ok {
+		return n, true
+	}
+
+	v := reflect.ValueOf(e)
+
+	switch {
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	case v.CanFloat():
+		return v.Float(), true
+	}
+
+	return 0, false
+}
+
+// convertNumber converts an aggregate to the numeric kind of target, leaving
+// it a float64 for non-numeric targets. Like assignNumber, it fails instead
+// of truncating fractions or wrapping around on overflow.
+func convertNumber(n float64, target reflect.Type, fieldName string) (interface{}, error) {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		v := reflect.New(target).Elem()
+
+		if _, err := assignNumber(v, reflect.ValueOf(n), fieldName); err != nil {
+			return nil, err
+		}
+
+		return v.Interface(), nil
+	}
+
+	return n, nil
+}
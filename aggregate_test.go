@@ -0,0 +1,53 @@
+package mson_test
+
+import (
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+func TestAggregateOptions(t *testing.T) {
+	type totals struct {
+		Sum   int     `json:"sum,sum"`
+		Avg   float64 `json:"avg,avg"`
+		Min   float64 `json:"min,min"`
+		Max   int     `json:"max,max"`
+		Count int     `json:"count,count"`
+		Empty float64 `json:"empty,avg"`
+	}
+
+	data := []byte(`{"sum":[1,2,3],"avg":[1,2,4.5],"min":[3,-1.5,2],"max":[3,9,2],"count":["a",{},null],"empty":[]}`)
+
+	var v totals
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (totals{Sum: 6, Avg: 2.5, Min: -1.5, Max: 9, Count: 3}); v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+
+	for _, data := range []string{`{"sum":[1,"2"]}`, `{"avg":{"a":1}}`} {
+		if err := mson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("aggregated %s", data)
+		}
+	}
+}
+
+func TestAggregateFitsField(t *testing.T) {
+	var v struct {
+		Avg   int  `json:"avg,avg"`
+		Small int8 `json:"small,sum"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"avg":[1,3],"small":[100,27]}`), &v); err != nil || v.Avg != 2 || v.Small != 127 {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	for _, data := range []string{`{"avg":[1,2]}`, `{"small":[100,28]}`} {
+		if err := mson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("stored the aggregate of %s", data)
+		}
+	}
+}
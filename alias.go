@@ -0,0 +1,61 @@
+package mson
+
+import "sync"
+
+var (
+	aliasesMu sync.RWMutex
+	aliases   = map[string][]string{}
+)
+
+// DefineAlias names a chain of tag options so that it can be reused across
+// struct tags, as in DefineAlias("msepoch", "unix,milliseconds") followed by
+// `json:"created,msepoch"`. Aliases may refer to other aliases. DefineAlias
+// panics if name is already a tag option.
+func DefineAlias(name, options string) {
+	if verbs[name] {
+		panic(errorf(ErrInvalidTag, "mson: cannot define alias %s; it is already a tag option", name))
+	}
+
+	aliasesMu.Lock()
+	defer aliasesMu.Unlock()
+	defer resetFastPlans()
+
+	aliases[name] = splitIgnoreQuoted(options, ',')
+}
+
+func expandAliases(tokens []string, s *settings, depth int) []string {
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+
+	return expandAliasesLocked(tokens, s, depth)
+}
+
+func expandAliasesLocked(tokens []string, s *settings, depth int) []string {
+	if len(aliases) == 0 && len(s.aliases) == 0 {
+		return tokens
+	}
+
+	var expanded []string
+
+	for _, token := range tokens {
+		// Aliases of a codec take precedence over package-level ones
+		chain, ok := s.aliases[token]
+
+		if !ok {
+			chain, ok = aliases[token]
+		}
+
+		if !ok {
+			expanded = append(expanded, token)
+			continue
+		}
+
+		if depth > 16 {
+			panic(errorf(ErrInvalidTag, "mson: alias %s expands too deeply; aliases may not be recursive", token))
+		}
+
+		expanded = append(expanded, expandAliasesLocked(chain, s, depth+1)...)
+	}
+
+	return expanded
+}
@@ -0,0 +1,67 @@
+package mson
+
+// An Arena recycles the intermediate objects mson builds while decoding, the
+// key-normalized copies of JSON objects and the sets of keys claimed by
+// fields, so that batch jobs with strict GC budgets can decode many documents
+// and then release those objects in one step with Reset. Decoded values never
+// refer to the arena's objects, so they stay valid after Reset. The maps that
+// encoding/json builds while parsing are not drawn from the arena.
+//
+// Arenas are experimental. An Arena must not be used by concurrent decodes,
+// which includes concurrent calls to a Codec created with WithArena.
+type Arena struct {
+	objects     []map[string]interface{}
+	sets        []map[string]bool
+	usedObjects int
+	usedSets    int
+}
+
+// NewArena returns an empty Arena.
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+// WithArena draws the intermediate objects of the decode from a.
+func WithArena(a *Arena) Option {
+	return func(s *settings) {
+		s.arena = a
+	}
+}
+
+// Reset makes every object of the arena available again. It must not be
+// called while a decode using the arena is in progress.
+func (a *Arena) Reset() {
+	for _, m := range a.objects[:a.usedObjects] {
+		for k := range m {
+			delete(m, k)
+		}
+	}
+
+	for _, m := range a.sets[:a.usedSets] {
+		for k := range m {
+			delete(m, k)
+		}
+	}
+
+	a.usedObjects, a.usedSets = 0, 0
+}
+
+func (a *Arena) object(size int) map[string]interface{} {
+	if a.usedObjects == len(a.objects) {
+		a.objects = append(a.objects, make(map[string]interface{}, size))
+	}
+
+	a.usedObjects++
+
+	return a.objects[a.usedObjects-1]
+}
+
+func (a *Arena) set() map[string]bool {
+	if a.usedSets == len(a.sets) {
+		a.sets = append(a.sets, map[string]bool{})
+	}
+
+	a.usedSets++
+
+	return a.sets[a.usedSets-1]
+}
@@ -0,0 +1,281 @@
+package mson
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+var (
+	jsonNumberType      = reflect.TypeOf(json.Number(""))
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// assignValue stores the value produced by the options of a field in dst,
+// converting it to the field's type when the kinds are compatible: numbers
+// of any type to numbers that can hold them exactly, arrays to slices and
+// arrays of any element type, objects to maps and structs, and values to the
+// named types of their kind. Types implementing json.Unmarshaler or, for
+// strings, encoding.TextUnmarshaler decode the value themselves, and strings
+// stored in []byte are read as base64, as encoding/json does, so that what
+// Marshal writes for such types is read back. Anything else is reported as
+// ErrTypeMismatch instead of letting reflect panic.
+func assignValue(dst reflect.Value, value interface{}, fieldName string, state *decodeState) error {
+	if value == nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+
+	if !state.numbers {
+		v, err := plainNumbers(value, dst.Type(), fieldName)
+
+		if err != nil {
+			return err
+		}
+
+		value = v
+	}
+
+	rv := reflect.ValueOf(value)
+
+	if rv.Type().AssignableTo(dst.Type()) {
+		dst.Set(rv)
+		return nil
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+
+		return assignValue(dst.Elem(), value, fieldName, state)
+	}
+
+	if ok, err := unmarshalValue(dst, value, fieldName); ok {
+		return err
+	}
+
+	// Named types such as type Timeout time.Duration receive the value of
+	// their underlying type from the options
+	if rv.Kind() == dst.Kind() && rv.Type().ConvertibleTo(dst.Type()) && rv.Kind() != reflect.Slice && rv.Kind() != reflect.Map {
+		dst.Set(rv.Convert(dst.Type()))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if ok, err := assignNumber(dst, rv, fieldName); ok {
+			return err
+		}
+	case reflect.Slice, reflect.Array:
+		if elems, ok := value.([]interface{}); ok {
+			return assignElements(dst, elems, fieldName, state)
+		}
+	case reflect.Map:
+		if obj, ok := value.(map[string]interface{}); ok && dst.Type().Key().Kind() == reflect.String {
+			m := reflect.MakeMapWithSize(dst.Type(), len(obj))
+			path := state.path
+			defer func() { state.path = path }()
+
+			for k, v := range obj {
+				elem := reflect.New(dst.Type().Elem()).Elem()
+				state.path = append(path[:len(path):len(path)], k)
+
+				if err := assignValue(elem, v, fieldName+"."+k, state); err != nil {
+					return err
+				}
+
+				m.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+			}
+
+			dst.Set(m)
+			return nil
+		}
+	case reflect.Struct:
+		if obj, ok := value.(map[string]interface{}); ok && !isBigType(dst.Type()) {
+			return decodeStruct(dst, obj, state)
+		}
+	}
+
+	return errorf(ErrTypeMismatch, "mson: cannot store %s in field %s of type %s", rv.Type(), fieldName, dst.Type())
+}
+
+// unmarshalValue stores value in dst through the unmarshaling methods of
+// dst's type, or as base64 for []byte, reporting false when neither applies.
+func unmarshalValue(dst reflect.Value, value interface{}, fieldName string) (bool, error) {
+	if !dst.CanAddr() {
+		return false, nil
+	}
+
+	s, isString := value.(string)
+
+	switch ptr := dst.Addr(); {
+	case ptr.Type().Implements(jsonUnmarshalerType):
+		data, err := json.Marshal(value)
+
+		if err == nil {
+			err = ptr.Interface().(json.Unmarshaler).UnmarshalJSON(data)
+		}
+
+		if err != nil {
+			return true, fmt.Errorf("mson: %w, decoding of field %s into %s failed", err, fieldName, dst.Type())
+		}
+	case isString && ptr.Type().Implements(textUnmarshalerType):
+		if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+			return true, fmt.Errorf("mson: %w, decoding of field %s into %s failed", err, fieldName, dst.Type())
+		}
+	case isString && dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8:
+		b, err := base64.StdEncoding.DecodeString(s)
+
+		if err != nil {
+			return true, fmt.Errorf("mson: %w, decoding of field %s as base64 failed", err, fieldName)
+		}
+
+		dst.SetBytes(b)
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// assignNumber stores the number held by src, which may be of any numeric
+// kind or a json.Number, in dst, reporting false when src holds no number.
+// Integers are stored without going through float64, so that they keep
+// their precision.
+func assignNumber(dst, src reflect.Value, fieldName string) (bool, error) {
+	overflow := func(n interface{}) error {
+		return errorf(ErrOverflow, "mson: field %s holds %v, which overflows %s", fieldName, n, dst.Type())
+	}
+
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true, assignInt(dst, src.Int(), overflow)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n := src.Uint(); n > math.MaxInt64 {
+			return true, assignUint(dst, n, overflow)
+		}
+
+		return true, assignInt(dst, int64(src.Uint()), overflow)
+	case reflect.Float32, reflect.Float64:
+		return true, assignFloat(dst, src.Float(), fieldName, overflow)
+	case reflect.String:
+		if src.Type() != jsonNumberType {
+			return false, nil
+		}
+
+		if n, err := strconv.ParseInt(src.String(), 10, 64); err == nil {
+			return true, assignInt(dst, n, overflow)
+		}
+
+		if n, err := strconv.ParseUint(src.String(), 10, 64); err == nil {
+			return assignNumber(dst, reflect.ValueOf(n), fieldName)
+		}
+
+		n, err := strconv.ParseFloat(src.String(), 64)
+
+		if err != nil {
+			return true, overflow(src.String())
+		}
+
+		return true, assignFloat(dst, n, fieldName, overflow)
+	}
+
+	return false, nil
+}
+
+func assignInt(dst reflect.Value, n int64, overflow func(interface{}) error) error {
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(float64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n < 0 || dst.OverflowUint(uint64(n)) {
+			return overflow(n)
+		}
+
+		dst.SetUint(uint64(n))
+	default:
+		if dst.OverflowInt(n) {
+			return overflow(n)
+		}
+
+		dst.SetInt(n)
+	}
+
+	return nil
+}
+
+func assignFloat(dst reflect.Value, n float64, fieldName string, overflow func(interface{}) error) error {
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if dst.OverflowFloat(n) {
+			return overflow(n)
+		}
+
+		dst.SetFloat(n)
+		return nil
+	}
+
+	if n != math.Trunc(n) {
+		return errorf(ErrTypeMismatch, "mson: field %s holds %v, which is not an integer", fieldName, n)
+	}
+
+	// float64(math.MaxInt64) rounds up to 1<<63, which no longer fits
+	if n < math.MinInt64 || n >= math.MaxInt64 {
+		if n >= 0 && n < math.MaxUint64 {
+			return assignUint(dst, uint64(n), overflow)
+		}
+
+		return overflow(n)
+	}
+
+	return assignInt(dst, int64(n), overflow)
+}
+
+func assignUint(dst reflect.Value, n uint64, overflow func(interface{}) error) error {
+	if dst.Kind() < reflect.Uint || dst.Kind() > reflect.Uint64 || dst.OverflowUint(n) {
+		return overflow(n)
+	}
+
+	dst.SetUint(n)
+	return nil
+}
+
+// assignElements stores each element of an array in the slice or array dst.
+func assignElements(dst reflect.Value, elems []interface{}, fieldName string, state *decodeState) error {
+	out := dst
+
+	if dst.Kind() == reflect.Slice {
+		out = reflect.MakeSlice(dst.Type(), len(elems), len(elems))
+	} else if len(elems) > dst.Len() {
+		return errorf(ErrTypeMismatch, "mson: field %s holds %d elements, which do not fit %s", fieldName, len(elems), dst.Type())
+	}
+
+	path := state.path
+	defer func() { state.path = path }()
+
+	for i, e := range elems {
+		state.path = append(path[:len(path):len(path)], strconv.Itoa(i))
+
+		if err := assignValue(out.Index(i), e, fieldName+"["+strconv.Itoa(i)+"]", state); err != nil {
+			return err
+		}
+	}
+
+	if dst.Kind() == reflect.Array {
+		for i := len(elems); i < dst.Len(); i++ {
+			dst.Index(i).Set(reflect.Zero(dst.Type().Elem()))
+		}
+	} else {
+		dst.Set(out)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package mson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+func TestAssignConvertsToFieldTypes(t *testing.T) {
+	type item struct {
+		N int `json:"n"`
+	}
+
+	type typed struct {
+		Count  int8              `json:"count"`
+		Size   uint              `json:"size"`
+		Tags   []string          `json:"tags"`
+		Labels map[string]int    `json:"labels"`
+		Pair   [2]float32        `json:"pair"`
+		Item   item              `json:"item"`
+		First  item              `json:"first,first"`
+		Items  []item            `json:"items,filter=\"n>=2\""`
+		Names  map[string]string `json:"names"`
+	}
+
+	data := []byte(`{"count":-3,"size":7,"tags":["a","b"],"labels":{"x":1},"pair":[1.5,2],"item":{"n":4},"first":[{"n":5},{"n":6}],"items":[{"n":1},{"n":2}],"names":{"k":"v"}}`)
+
+	var v typed
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	want := typed{
+		Count: -3, Size: 7,
+		Tags:   []string{"a", "b"},
+		Labels: map[string]int{"x": 1},
+		Pair:   [2]float32{1.5, 2},
+		Item:   item{N: 4}, First: item{N: 5},
+		Items: []item{{N: 2}},
+		Names: map[string]string{"k": "v"},
+	}
+
+	if !reflect.DeepEqual(v, want) {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+}
+
+func TestAssignMismatches(t *testing.T) {
+	var v struct {
+		Count int8  `json:"count"`
+		Size  uint  `json:"size"`
+		List  []int `json:"list"`
+		User  struct {
+			Age int `json:"age"`
+		} `json:"user"`
+	}
+
+	tests := []struct {
+		data  string
+		want  error
+		field string
+	}{
+		{`{"count":300}`, mson.ErrOverflow, "count"},
+		{`{"count":1.5}`, mson.ErrTypeMismatch, "count"},
+		{`{"count":""}`, mson.ErrTypeMismatch, "count"},
+		{`{"size":-1}`, mson.ErrOverflow, "size"},
+		{`{"list":{}}`, mson.ErrTypeMismatch, "list"},
+		{`{"user":{"age":"old"}}`, mson.ErrTypeMismatch, "user.age"},
+	}
+
+	for _, tt := range tests {
+		err := mson.Unmarshal([]byte(tt.data), &v)
+
+		var fieldErr *mson.FieldError
+
+		if !errors.Is(err, tt.want) || !errors.As(err, &fieldErr) || fieldErr.Field != tt.field {
+			t.Errorf("decoding %s returned %v, want %v at %s", tt.data, err, tt.want, tt.field)
+		}
+	}
+}
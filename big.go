@@ -0,0 +1,174 @@
+package mson
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+var (
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// isBigType reports whether t, or the type t points to, is one of the
+// math/big number types, which keep the number's text instead of a float64.
+func isBigType(t reflect.Type) bool {
+	t = derefType(t)
+
+	return t == bigIntType || t == bigFloatType || t == bigRatType
+}
+
+// normalizeNumbers returns value with its json.Number values replaced by the
+// float64 values the options expect. Arrays and objects are copied rather
+// than changed, since other fields may still read the decoded document.
+func normalizeNumbers(value interface{}, fieldName string) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := strconv.ParseFloat(string(v), 64)
+
+		if err != nil {
+			return nil, errorf(ErrOverflow, "mson: field %s holds number %s, which overflows float64", fieldName, v)
+		}
+
+		return f, nil
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+
+		for i, e := range v {
+			n, err := normalizeNumbers(e, fieldName)
+
+			if err != nil {
+				return nil, err
+			}
+
+			normalized[i] = n
+		}
+
+		return normalized, nil
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+
+		for k, e := range v {
+			n, err := normalizeNumbers(e, fieldName)
+
+			if err != nil {
+				return nil, err
+			}
+
+			normalized[k] = n
+		}
+
+		return normalized, nil
+	}
+
+	return value, nil
+}
+
+// plainNumbers converts the json.Number values left by the options into the
+// float64 values encoding/json would store in a field of type t. Numeric
+// fields and the types that parse the literal themselves keep them, so that
+// integers are stored exactly.
+func plainNumbers(value interface{}, t reflect.Type, fieldName string) (interface{}, error) {
+	t = derefType(t)
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return normalizeNumbers(value, fieldName)
+	case reflect.Slice, reflect.Map:
+		// []interface{} and map[string]interface{} are stored as they are
+		if reflect.TypeOf(value) == t {
+			return normalizeNumbers(value, fieldName)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return value, nil
+	}
+
+	if _, ok := value.(json.Number); !ok || t == jsonNumberType || isBigType(t) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return value, nil
+	}
+
+	return normalizeNumbers(value, fieldName)
+}
+
+// parseBig converts a JSON number or string into a value of the big type t
+// without going through float64.
+func parseBig(value interface{}, t reflect.Type, fieldName string) (reflect.Value, error) {
+	var s string
+
+	switch v := value.(type) {
+	case json.Number:
+		s = string(v)
+	case string:
+		s = v
+	case float64:
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return reflect.Value{}, errorf(ErrTypeMismatch, "mson: field %s is not a number or a string", fieldName)
+	}
+
+	var (
+		parsed interface{}
+		ok     bool
+	)
+
+	switch t {
+	case bigIntType:
+		parsed, ok = new(big.Int).SetString(s, 10)
+	case bigFloatType:
+		// Enough precision to hold every digit of the literal
+		f, _, err := big.ParseFloat(s, 10, uint(len(s))*4+64, big.ToNearestEven)
+		parsed, ok = f, err == nil
+	case bigRatType:
+		parsed, ok = new(big.Rat).SetString(s)
+	}
+
+	if !ok {
+		return reflect.Value{}, errorf(ErrTypeMismatch, "mson: field %s holds %q, which is not a valid %v", fieldName, s, t)
+	}
+
+	return reflect.ValueOf(parsed).Elem(), nil
+}
+
+// encodeBig writes a big number as a JSON number when float64 can represent
+// its magnitude and as a string otherwise, so that consumers parsing numbers
+// into float64 are not handed an infinity.
+func encodeBig(rv reflect.Value) interface{} {
+	if rv.Kind() != reflect.Ptr {
+		if !rv.CanAddr() {
+			p := reflect.New(rv.Type())
+			p.Elem().Set(rv)
+			rv = p
+		} else {
+			rv = rv.Addr()
+		}
+	}
+
+	switch v := rv.Interface().(type) {
+	case *big.Int:
+		if v.BitLen() <= 1024 {
+			return json.Number(v.String())
+		}
+
+		return v.String()
+	case *big.Float:
+		if f, _ := v.Float64(); !v.IsInf() && !math.IsInf(f, 0) {
+			return json.Number(v.Text('g', -1))
+		}
+
+		return v.Text('g', -1)
+	case *big.Rat:
+		if f, _ := v.Float64(); v.IsInt() && !math.IsInf(f, 0) {
+			return json.Number(v.Num().String())
+		}
+
+		return v.RatString()
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package mson
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// A Cipher encrypts and decrypts the values of fields tagged with encrypted.
+// Ciphertext is base64-encoded by mson before it is placed in the document.
+// A Cipher must be safe for concurrent use.
+type Cipher interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+var (
+	ciphersMu sync.RWMutex
+	ciphers   = map[string]Cipher{}
+)
+
+// RegisterCipher makes c available to the encrypted tag option under name.
+// The cipher registered under the empty name is used by a bare encrypted.
+func RegisterCipher(name string, c Cipher) {
+	ciphersMu.Lock()
+	defer ciphersMu.Unlock()
+
+	if c == nil {
+		delete(ciphers, name)
+		return
+	}
+
+	ciphers[name] = c
+}
+
+func lookupCipher(name string, s *settings) (Cipher, error) {
+	if c, ok := s.ciphers[name]; ok {
+		return c, nil
+	}
+
+	ciphersMu.RLock()
+	defer ciphersMu.RUnlock()
+
+	c, ok := ciphers[name]
+
+	if !ok {
+		return nil, fmt.Errorf("no cipher registered under the name %q", name)
+	}
+
+	return c, nil
+}
+
+type aesGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMCipher returns a Cipher using AES-GCM with the given 16, 24 or 32
+// byte key. The random nonce is prepended to the ciphertext.
+func NewAESGCMCipher(key []byte) (Cipher, error) {
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &aesGCM{aead: aead}, nil
+}
+
+func (c *aesGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (c *aesGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < c.aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, sealed := ciphertext[:c.aead.NonceSize()], ciphertext[c.aead.NonceSize():]
+
+	return c.aead.Open(nil, nonce, sealed, nil)
+}
+
+func decryptValue(value interface{}, parts []string, target reflect.Type, fieldName string, s *settings) (interface{}, error) {
+	var name string
+
+	if len(parts) > 1 {
+		name = parts[1]
+	}
+
+	c, err := lookupCipher(name, s)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, decryption of field %s failed", err, fieldName)
+	}
+
+	strValue, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(strValue)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, decryption of field %s failed", err, fieldName)
+	}
+
+	plaintext, err := c.Decrypt(ciphertext)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, decryption of field %s failed", err, fieldName)
+	}
+
+	if target.Kind() == reflect.String {
+		return string(plaintext), nil
+	}
+
+	var decoded interface{}
+
+	if err := json.Unmarshal(plaintext, &decoded); err != nil {
+		return nil, fmt.Errorf("%w, decoding of decrypted field %s failed", errors.New(strings.Replace(err.Error(), "json", "mson", 1)), fieldName)
+	}
+
+	return decoded, nil
+}
+
+func encryptValue(value interface{}, parts []string, fieldName string, state *encodeState) (interface{}, error) {
+	var name string
+
+	if len(parts) > 1 {
+		name = parts[1]
+	}
+
+	c, err := lookupCipher(name, &state.settings)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, encryption of field %s failed", err, fieldName)
+	}
+
+	var raw []byte
+
+	if plaintext, ok := value.(string); ok {
+		raw = []byte(plaintext)
+	} else {
+		var buf bytes.Buffer
+
+		if err := encodeValue(&buf, reflect.ValueOf(value), state); err != nil {
+			return nil, err
+		}
+
+		raw = buf.Bytes()
+	}
+
+	ciphertext, err := c.Encrypt(raw)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, encryption of field %s failed", err, fieldName)
+	}
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
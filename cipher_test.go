@@ -0,0 +1,157 @@
+package mson_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+type secrets struct {
+	Password string `json:"password,encrypted"`
+	Token    string `json:"token,encrypted,vault"`
+	Note     string `json:"note"`
+}
+
+func TestEncryptedOption(t *testing.T) {
+	primary, err := mson.NewAESGCMCipher(bytes.Repeat([]byte{1}, 32))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vault, err := mson.NewAESGCMCipher(bytes.Repeat([]byte{2}, 16))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codec := mson.NewCodec(mson.WithCipher("", primary), mson.WithCipher("vault", vault))
+	v := secrets{Password: "hunter2", Token: "t0k3n", Note: "plain"}
+
+	out, err := codec.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(out, []byte("hunter2")) || bytes.Contains(out, []byte("t0k3n")) || !bytes.Contains(out, []byte(`"note":"plain"`)) {
+		t.Fatalf("got %s", out)
+	}
+
+	var back secrets
+
+	if err := codec.Unmarshal(out, &back); err != nil {
+		t.Fatal(err)
+	}
+
+	if back != v {
+		t.Fatalf("got %+v", back)
+	}
+
+	// A cipher under another key fails to open the values
+	other, _ := mson.NewAESGCMCipher(bytes.Repeat([]byte{3}, 32))
+
+	if err := mson.NewCodec(mson.WithCipher("", other), mson.WithCipher("vault", vault)).Unmarshal(out, &back); err == nil {
+		t.Fatal("decrypted with the wrong key")
+	}
+
+	if _, err := mson.NewAESGCMCipher([]byte("short")); err == nil {
+		t.Fatal("accepted a key of invalid length")
+	}
+}
+
+func TestRegisteredCipher(t *testing.T) {
+	c, err := mson.NewAESGCMCipher(bytes.Repeat([]byte{4}, 32))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := secrets{Password: "p", Token: "t", Note: "n"}
+
+	if _, err := mson.Marshal(v); err == nil {
+		t.Fatal("encrypted without a cipher")
+	}
+
+	mson.RegisterCipher("", c)
+	mson.RegisterCipher("vault", c)
+	defer mson.RegisterCipher("", nil)
+	defer mson.RegisterCipher("vault", nil)
+
+	out, err := mson.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var back secrets
+
+	// Encryption uses a random nonce, so the values are compared decrypted
+	if err := mson.Unmarshal(out, &back); err != nil || back != v {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+}
+
+func TestEncryptedNonStrings(t *testing.T) {
+	c, err := mson.NewAESGCMCipher(bytes.Repeat([]byte{5}, 32))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type record struct {
+		Codes []int          `json:"codes,encrypted"`
+		Limit float64        `json:"limit,encrypted"`
+		Flags map[string]int `json:"flags,encrypted"`
+	}
+
+	codec := mson.NewCodec(mson.WithCipher("", c))
+	v := record{Codes: []int{1, 2}, Limit: 2.5, Flags: map[string]int{"a": 1}}
+
+	out, err := codec.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var back record
+
+	if err := codec.Unmarshal(out, &back); err != nil || !reflect.DeepEqual(back, v) {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+}
+
+func TestEncryptedStructKeepsTags(t *testing.T) {
+	c, err := mson.NewAESGCMCipher(bytes.Repeat([]byte{6}, 32))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type window struct {
+		Start time.Time `json:"start,unix"`
+		Label string    `json:"label"`
+	}
+
+	type record struct {
+		Window window `json:"window,encrypted"`
+	}
+
+	codec := mson.NewCodec(mson.WithCipher("", c))
+	v := record{Window: window{Start: time.Unix(1700000000, 0), Label: "a"}}
+
+	out, err := codec.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var back record
+
+	if err := codec.Unmarshal(out, &back); err != nil || !back.Window.Start.Equal(v.Window.Start) || back.Window.Label != "a" {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+}
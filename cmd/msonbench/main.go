@@ -0,0 +1,206 @@
+// Command msonbench measures mson against encoding/json on payloads of
+// different shapes and reports time, bytes and allocations per operation.
+//
+// Usage:
+//
+//	msonbench [-count 3] [-write file] [-baseline file] [-threshold 0.15]
+//
+// With -write the results are saved as JSON. With -baseline they are compared
+// to results saved earlier, and msonbench exits with status 1 when a mson
+// benchmark got slower or allocates more by more than the threshold, so that
+// it can guard against performance regressions. Baselines are only
+// meaningful on the machine that recorded them.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/monerowner/mson"
+)
+
+// A Result is the measurement of one benchmark.
+type Result struct {
+	NsPerOp     int64 `json:"ns_per_op"`
+	BytesPerOp  int64 `json:"bytes_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+}
+
+type benchmark struct {
+	name string
+	run  func(b *testing.B)
+}
+
+func main() {
+	write := flag.String("write", "", "save the results to this file")
+	baseline := flag.String("baseline", "", "compare the results to those saved in this file")
+	threshold := flag.Float64("threshold", 0.15, "relative slowdown or allocation growth that counts as a regression")
+	count := flag.Int("count", 3, "run each benchmark this many times and keep the fastest run")
+	flag.Parse()
+
+	results := map[string]Result{}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "benchmark\tns/op\tB/op\tallocs/op\t")
+
+	for _, bm := range benchmarks() {
+		// The fastest run is the least disturbed by the rest of the machine
+		var best testing.BenchmarkResult
+
+		for i := 0; i < *count; i++ {
+			if r := testing.Benchmark(bm.run); i == 0 || r.NsPerOp() < best.NsPerOp() {
+				best = r
+			}
+		}
+
+		results[bm.name] = Result{best.NsPerOp(), best.AllocedBytesPerOp(), best.AllocsPerOp()}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t\n", bm.name, best.NsPerOp(), best.AllocedBytesPerOp(), best.AllocsPerOp())
+	}
+
+	w.Flush()
+
+	if *write != "" {
+		data, err := json.MarshalIndent(results, "", "  ")
+
+		if err == nil {
+			err = os.WriteFile(*write, append(data, '\n'), 0o644)
+		}
+
+		if err != nil {
+			fatal(err)
+		}
+	}
+
+	if *baseline != "" {
+		data, err := os.ReadFile(*baseline)
+
+		if err != nil {
+			fatal(err)
+		}
+
+		var previous map[string]Result
+
+		if err := json.Unmarshal(data, &previous); err != nil {
+			fatal(err)
+		}
+
+		if regressions := compare(previous, results, *threshold); len(regressions) > 0 {
+			for _, r := range regressions {
+				fmt.Fprintln(os.Stderr, "regression:", r)
+			}
+
+			os.Exit(1)
+		}
+	}
+}
+
+// compare returns a description of every mson benchmark that regressed by
+// more than threshold relative to previous.
+func compare(previous, current map[string]Result, threshold float64) []string {
+	var names []string
+
+	for name := range current {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var regressions []string
+
+	for _, name := range names {
+		before, ok := previous[name]
+
+		if !ok || !strings.HasPrefix(name, "mson/") {
+			continue
+		}
+
+		after := current[name]
+
+		if float64(after.NsPerOp) > float64(before.NsPerOp)*(1+threshold) {
+			regressions = append(regressions, fmt.Sprintf("%s: %d ns/op, was %d", name, after.NsPerOp, before.NsPerOp))
+		}
+
+		if float64(after.AllocsPerOp) > float64(before.AllocsPerOp)*(1+threshold) {
+			regressions = append(regressions, fmt.Sprintf("%s: %d allocs/op, was %d", name, after.AllocsPerOp, before.AllocsPerOp))
+		}
+	}
+
+	return regressions
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "msonbench:", err)
+	os.Exit(1)
+}
+
+// benchmarks returns a mson and an encoding/json benchmark for each payload
+// shape, named mson/<shape> and json/<shape>.
+func benchmarks() []benchmark {
+	large := largeArray(10000)
+
+	return []benchmark{
+		{"mson/flat", decodeMson[Flat](flatPayload)},
+		{"json/flat", decodeJSON[Flat](flatPayload)},
+		{"mson/nested", decodeMson[Nested](nestedPayload)},
+		{"json/nested", decodeJSON[NestedJSON](nestedPayload)},
+		{"mson/tags", decodeMson[Tagged](taggedPayload)},
+		{"json/tags", decodeJSON[TaggedJSON](taggedPayload)},
+		{"mson/array", decodeMsonStream[Flat](large)},
+		{"json/array", decodeJSON[[]Flat](large)},
+	}
+}
+
+func decodeMson[T any](payload []byte) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			var v T
+
+			if err := mson.Unmarshal(payload, &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func decodeMsonStream[T any](payload []byte) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			d := mson.NewDecoder(bytes.NewReader(payload))
+
+			for {
+				var v T
+
+				if err := d.Decode(&v); err == io.EOF {
+					break
+				} else if err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	}
+}
+
+func decodeJSON[T any](payload []byte) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			var v T
+
+			if err := json.Unmarshal(payload, &v); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
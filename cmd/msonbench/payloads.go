@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// Flat has only primitive fields without options, which mson decodes on its
+// fast path.
+type Flat struct {
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Email  string  `json:"email"`
+	Age    int     `json:"age"`
+	Score  float64 `json:"score"`
+	Active bool    `json:"active"`
+}
+
+var flatPayload = []byte(`{"id":12345,"name":"Ada Lovelace","email":"ada@example.com","age":36,"score":98.5,"active":true}`)
+
+// Nested holds objects, arrays and a stringified document.
+type Nested struct {
+	User    map[string]interface{} `mson:"user"`
+	Primary interface{}            `mson:"addresses,first"`
+	Items   []interface{}          `mson:"items"`
+	Meta    Summary                `mson:"meta,fromstring"`
+}
+
+// Summary is the stringified document of Nested.
+type Summary struct {
+	Name  string  `mson:"name,slug"`
+	Score float64 `mson:"score,round"`
+}
+
+// NestedJSON is the encoding/json counterpart of Nested.
+type NestedJSON struct {
+	User      map[string]interface{} `json:"user"`
+	Addresses []interface{}          `json:"addresses"`
+	Items     []interface{}          `json:"items"`
+	Meta      string                 `json:"meta"`
+}
+
+var nestedPayload = []byte(`{"user":{"name":"Ada","roles":["admin","dev"],"prefs":{"theme":"dark"}},` +
+	`"addresses":[{"city":"London","zip":"N1"},{"city":"Paris","zip":"75001"}],` +
+	`"items":[{"sku":"a1","qty":2},{"sku":"b2","qty":1},{"sku":"c3","qty":5}],` +
+	`"meta":"{\"name\":\"Monthly Report\",\"score\":4.2}"}`)
+
+// Tagged chains many tag options.
+type Tagged struct {
+	ID      string        `mson:"id,sanitize,maxlen=16"`
+	Slug    string        `mson:"title,slug"`
+	Email   string        `mson:"email,email,noplus"`
+	Status  string        `mson:"status,oneof=active|pending|closed"`
+	Created time.Time     `mson:"created,unix"`
+	Timeout time.Duration `mson:"timeout,duration"`
+	Price   float64       `mson:"price,multiply=100,round"`
+	Tags    []interface{} `mson:"tags,unique,sort"`
+	Host    string        `mson:"host,hostname"`
+}
+
+// TaggedJSON is the encoding/json counterpart of Tagged.
+type TaggedJSON struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Email   string   `json:"email"`
+	Status  string   `json:"status"`
+	Created int64    `json:"created"`
+	Timeout int64    `json:"timeout"`
+	Price   float64  `json:"price"`
+	Tags    []string `json:"tags"`
+	Host    string   `json:"host"`
+}
+
+var taggedPayload = []byte(`{"id":" abc ","title":"Hello World","email":"a+b@X.com","status":"active",` +
+	`"created":1700000000,"timeout":30,"price":1.234,"tags":["b","a","b"],"host":"api.example.com"}`)
+
+// largeArray returns a top-level array of n Flat records.
+func largeArray(n int) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteByte('[')
+
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		fmt.Fprintf(&buf, `{"id":%d,"name":"user %d","email":"user%d@example.com","age":%d,"score":%d.5,"active":%t}`, i, i, i, i%90, i%100, i%2 == 0)
+	}
+
+	buf.WriteByte(']')
+
+	return buf.Bytes()
+}
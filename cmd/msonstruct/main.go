@@ -0,0 +1,391 @@
+// Command msonstruct reads an example JSON document and prints a Go struct
+// that decodes it, with mson tag options suggested for values that look like
+// epoch timestamps, durations, compressed blobs or stringified JSON.
+//
+// Usage:
+//
+//	msonstruct [-name Type] [-pkg package] [file]
+//
+// The document is read from standard input when no file is given. The output
+// is a starting point to be reviewed; the guesses are based on a single
+// example and may not hold for every payload of the API.
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	name := flag.String("name", "Generated", "name of the top-level struct")
+	pkg := flag.String("pkg", "", "package clause to emit; none when empty")
+	flag.Parse()
+
+	var (
+		data []byte
+		err  error
+	)
+
+	switch flag.NArg() {
+	case 0:
+		data, err = io.ReadAll(os.Stdin)
+	case 1:
+		data, err = os.ReadFile(flag.Arg(0))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fatal(err)
+	}
+
+	src, err := generate(data, *name, *pkg)
+
+	if err != nil {
+		fatal(err)
+	}
+
+	os.Stdout.Write(src)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "msonstruct:", err)
+	os.Exit(1)
+}
+
+type generator struct {
+	buf   bytes.Buffer
+	types []string
+	names map[string]bool
+}
+
+func generate(data []byte, name, pkg string) ([]byte, error) {
+	obj, err := decodeObject(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{names: map[string]bool{}}
+	g.structType(name, obj)
+
+	var out bytes.Buffer
+
+	if pkg != "" {
+		fmt.Fprintf(&out, "package %s\n\n", pkg)
+	}
+
+	imports := strings.Join(g.types, "\n\n")
+
+	if strings.Contains(imports, "time.") {
+		out.WriteString("import \"time\"\n\n")
+	}
+
+	out.WriteString(imports)
+
+	return format.Source(out.Bytes())
+}
+
+func decodeObject(data []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var obj map[string]interface{}
+
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("document must be a JSON object: %w", err)
+	}
+
+	return obj, nil
+}
+
+// structType emits a struct named name for obj and returns the name actually
+// used, which differs when an earlier type took it.
+func (g *generator) structType(name string, obj map[string]interface{}) string {
+	name = g.uniqueName(name)
+
+	keys := make([]string, 0, len(obj))
+
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+
+	fields := map[string]bool{}
+
+	for _, k := range keys {
+		typ, options := g.fieldType(name, k, obj[k])
+		field := identifier(k)
+
+		for i := 2; fields[field]; i++ {
+			field = fmt.Sprintf("%s%d", identifier(k), i)
+		}
+
+		fields[field] = true
+
+		tag := k
+
+		if options != "" {
+			tag += "," + options
+		}
+
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, typ, tag)
+	}
+
+	b.WriteString("}")
+
+	// Prepended after the types of its fields, so that every struct comes
+	// before the types it uses
+	g.types = append([]string{b.String()}, g.types...)
+
+	return name
+}
+
+func (g *generator) uniqueName(name string) string {
+	unique := name
+
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+
+	g.names[unique] = true
+
+	return unique
+}
+
+// fieldType returns the Go type and tag options suggested for value, found
+// under key in the struct named parent.
+func (g *generator) fieldType(parent, key string, value interface{}) (string, string) {
+	switch v := value.(type) {
+	case nil:
+		return "interface{}", ""
+	case bool:
+		return "bool", ""
+	case json.Number:
+		return numberType(key, v)
+	case string:
+		return g.stringType(parent, key, v)
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]interface{}", ""
+		}
+
+		typ, options := g.fieldType(parent, singular(key), v[0])
+
+		if options != "" {
+			// Options apply to the whole array, not to its elements
+			return "[]interface{}", ""
+		}
+
+		return "[]" + typ, ""
+	case map[string]interface{}:
+		return g.structType(parent+identifier(key), v), ""
+	}
+
+	return "interface{}", ""
+}
+
+func numberType(key string, n json.Number) (string, string) {
+	i, err := n.Int64()
+
+	if err != nil {
+		return "float64", ""
+	}
+
+	switch {
+	case i >= 1e9 && i < 1e10:
+		return "time.Time", "unix"
+	case i >= 1e12 && i < 1e13:
+		return "time.Time", "unix,milliseconds"
+	}
+
+	if unit, ok := durationUnit(key); ok {
+		if unit == "seconds" {
+			return "time.Duration", "duration"
+		}
+
+		return "time.Duration", "duration," + unit
+	}
+
+	return "int64", ""
+}
+
+// durationUnit guesses from a key such as timeout_ms or ttl whether a number
+// holds a duration, and in which unit.
+func durationUnit(key string) (string, bool) {
+	words := splitWords(key)
+
+	if len(words) == 0 {
+		return "", false
+	}
+
+	switch last := strings.ToLower(words[len(words)-1]); last {
+	case "ms", "millis", "milliseconds":
+		return "milliseconds", true
+	case "us", "micros", "microseconds":
+		return "microseconds", true
+	case "ns", "nanos", "nanoseconds":
+		return "nanoseconds", true
+	case "s", "sec", "secs", "seconds", "ttl", "timeout", "duration", "interval", "delay":
+		return "seconds", true
+	case "min", "mins", "minutes":
+		return "minutes", true
+	case "hours":
+		return "hours", true
+	}
+
+	return "", false
+}
+
+func (g *generator) stringType(parent, key, s string) (string, string) {
+	trimmed := strings.TrimSpace(s)
+
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var inner interface{}
+
+		dec := json.NewDecoder(strings.NewReader(trimmed))
+		dec.UseNumber()
+
+		if dec.Decode(&inner) == nil {
+			typ, options := g.fieldType(parent, key, inner)
+
+			if options == "" {
+				return typ, "fromstring"
+			}
+		}
+	}
+
+	if n := json.Number(trimmed); trimmed != "" && isDigits(trimmed) {
+		if typ, options := numberType(key, n); typ == "time.Time" {
+			return typ, options
+		}
+	}
+
+	if raw, ok := decodeBase64(s); ok {
+		switch {
+		case bytes.HasPrefix(raw, []byte{0x1f, 0x8b}):
+			return "[]byte", "gzip"
+		case len(raw) > 1 && raw[0] == 0x78 && (uint16(raw[0])<<8|uint16(raw[1]))%31 == 0:
+			return "[]byte", "zlib"
+		default:
+			return "[]byte", ""
+		}
+	}
+
+	return "string", ""
+}
+
+// decodeBase64 reports whether s is long and irregular enough to be a
+// base64 blob rather than a word that happens to be valid base64.
+func decodeBase64(s string) ([]byte, bool) {
+	if len(s) < 16 || len(s)%4 != 0 || strings.ContainsAny(s, " \t\n") {
+		return nil, false
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(s)
+
+	if err != nil {
+		return nil, false
+	}
+
+	return raw, strings.ContainsAny(s, "0123456789+/=") && strings.IndexFunc(s, unicode.IsUpper) >= 0
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "JWT": true, "SQL": true, "TTL": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+// identifier turns a JSON key such as created_at or userId into an exported
+// Go identifier such as CreatedAt or UserID.
+func identifier(key string) string {
+	var b strings.Builder
+
+	for _, word := range splitWords(key) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(word[:1]) + strings.ToLower(word[1:]))
+	}
+
+	id := b.String()
+
+	if id == "" || !unicode.IsLetter(rune(id[0])) {
+		id = "F" + id
+	}
+
+	return id
+}
+
+// splitWords splits a key on separators and lower-to-upper case changes.
+func splitWords(key string) []string {
+	var (
+		words []string
+		word  []rune
+		prev  rune
+	)
+
+	for _, r := range key {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+
+			word = nil
+		case unicode.IsUpper(r) && unicode.IsLower(prev) && len(word) > 0:
+			words = append(words, string(word))
+			word = []rune{r}
+		default:
+			word = append(word, r)
+		}
+
+		prev = r
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	return words
+}
+
+// singular names the element type of an array from the array's key.
+func singular(key string) string {
+	switch {
+	case strings.HasSuffix(key, "ies"):
+		return strings.TrimSuffix(key, "ies") + "y"
+	case strings.HasSuffix(key, "s") && !strings.HasSuffix(key, "ss"):
+		return strings.TrimSuffix(key, "s")
+	}
+
+	return key
+}
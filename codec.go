@@ -0,0 +1,44 @@
+package mson
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// A Codec decodes and encodes with a fixed set of options. Aliases and ciphers
+// given to a Codec are visible only to it, so independent parts of a program
+// can configure mson without touching the package-level registries.
+//
+// A Codec is safe for concurrent use once created, unless it was created
+// with WithArena: every decode draws from the arena, so such a Codec must be
+// used by one goroutine at a time.
+type Codec struct {
+	settings settings
+}
+
+// NewCodec returns a Codec that applies the given options to every call.
+func NewCodec(opts ...Option) *Codec {
+	c := &Codec{}
+
+	for _, opt := range opts {
+		opt(&c.settings)
+	}
+
+	return c
+}
+
+// Unmarshal is like the package-level Unmarshal, using the codec's options.
+func (c *Codec) Unmarshal(data []byte, v any) error {
+	return unmarshal(data, v, &decodeState{settings: c.settings})
+}
+
+// Marshal is like the package-level Marshal, using the codec's options.
+func (c *Codec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := encodeValue(&buf, reflect.ValueOf(v), &encodeState{settings: c.settings}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
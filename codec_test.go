@@ -0,0 +1,232 @@
+package mson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+type timeout time.Duration
+
+func TestCodecAliasesAndTagKeys(t *testing.T) {
+	type config struct {
+		Country string  `api:"nation,iso3" json:"country"`
+		Delay   float64 `json:"delay"`
+	}
+
+	codec := mson.NewCodec(mson.WithTagKeys("api", "json"), mson.WithAlias("iso3", "country,alpha3"))
+
+	var v config
+
+	if err := codec.Unmarshal([]byte(`{"nation":"de","country":"ignored","delay":5}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v != (config{Country: "DEU", Delay: 5}) {
+		t.Fatalf("got %+v", v)
+	}
+
+	out, err := codec.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"nation":"DEU","delay":5}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	// The alias is private to the codec
+	defer func() {
+		if recover() == nil {
+			t.Error("resolved an alias defined only on a codec")
+		}
+	}()
+
+	_ = mson.UnmarshalWithOptions([]byte(`{"nation":"de"}`), &v, mson.WithTagKeys("api"))
+}
+
+func TestAliasCannotShadowOption(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("defined an alias named after a tag option")
+		}
+	}()
+
+	mson.WithAlias("unix", "duration")
+}
+
+func TestRegisterTypeOptions(t *testing.T) {
+	type request struct {
+		Timeout  timeout  `json:"timeout"`
+		Override timeout  `json:"override,duration"`
+		Optional *timeout `json:"optional,omitempty"`
+	}
+
+	mson.RegisterTypeOptions(timeout(0), "duration,milliseconds")
+	defer mson.RegisterTypeOptions(timeout(0), "")
+
+	var v request
+
+	if err := mson.Unmarshal([]byte(`{"timeout":1500,"override":2,"optional":10}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Timeout != timeout(1500*time.Millisecond) || v.Override != timeout(2*time.Second) || *v.Optional != timeout(10*time.Millisecond) {
+		t.Errorf("got timeouts %v, %v and %v", v.Timeout, v.Override, *v.Optional)
+	}
+}
+
+func TestWithTypeOptions(t *testing.T) {
+	var v struct {
+		CreatedAt time.Time `json:"created_at"`
+		Explicit  time.Time `json:"explicit,unix"`
+	}
+
+	err := mson.UnmarshalWithOptions([]byte(`{"created_at":1700000000000,"explicit":1700000000}`), &v,
+		mson.WithTypeOptions(time.Time{}, "unix,milliseconds"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.CreatedAt.Equal(time.UnixMilli(1700000000000)) || !v.Explicit.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("got %v and %v", v.CreatedAt, v.Explicit)
+	}
+}
+
+func TestEmptyStringAsNull(t *testing.T) {
+	var v struct {
+		Count float64       `json:"count"`
+		At    time.Time     `json:"at"`
+		Tags  []interface{} `json:"tags"`
+		Name  string        `json:"name"`
+	}
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"count":"","at":"","tags":"","name":""}`), &v, mson.WithEmptyStringAsNull()); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Count != 0 || !v.At.IsZero() || v.Tags != nil || v.Name != "" {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestMergePolicy(t *testing.T) {
+	type settings struct {
+		Name   string                 `json:"name"`
+		Port   float64                `json:"port"`
+		Tags   []interface{}          `json:"tags"`
+		Labels map[string]interface{} `json:"labels"`
+	}
+
+	defaults := func() settings {
+		return settings{Name: "default", Port: 80, Tags: []interface{}{"a"}, Labels: map[string]interface{}{"env": "dev", "team": "core"}}
+	}
+
+	data := []byte(`{"port":8080,"tags":["b"],"labels":{"env":"prod"}}`)
+
+	tests := []struct {
+		policy mson.MergePolicy
+		want   settings
+	}{
+		{mson.MergePolicy{}, settings{Port: 8080, Tags: []interface{}{"b"}, Labels: map[string]interface{}{"env": "prod"}}},
+		{mson.JSONMergePolicy, settings{Name: "default", Port: 8080, Tags: []interface{}{"b"}, Labels: map[string]interface{}{"env": "prod", "team": "core"}}},
+		{mson.MergePolicy{KeepAbsent: true, KeepNonZero: true, AppendSlices: true}, settings{Name: "default", Port: 80, Tags: []interface{}{"a", "b"}, Labels: map[string]interface{}{"env": "prod"}}},
+	}
+
+	for _, tt := range tests {
+		v := defaults()
+
+		if err := mson.UnmarshalWithOptions(data, &v, mson.WithMergePolicy(tt.policy)); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(v, tt.want) {
+			t.Errorf("with %+v got %+v, want %+v", tt.policy, v, tt.want)
+		}
+	}
+}
+
+func TestCaseSensitiveKeys(t *testing.T) {
+	var v struct {
+		Name string `json:"name"`
+	}
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"NAME":"a"}`), &v, mson.WithCaseSensitiveKeys()); err != nil || v.Name != "" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"NAME":"a"}`), &v); err != nil || v.Name != "a" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	var v struct {
+		Name string `json:"name"`
+	}
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"name":"a long enough name"}`), &v, mson.WithMaxBytes(16)); !errors.Is(err, mson.ErrTooLarge) {
+		t.Fatalf("got %v, want ErrTooLarge", err)
+	}
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"name":"short"}`), &v, mson.WithMaxBytes(16)); err != nil || v.Name != "short" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+}
+
+func TestWithInterning(t *testing.T) {
+	type record struct {
+		Kind string                 `json:"kind"`
+		Meta map[string]interface{} `json:"meta"`
+	}
+
+	codec := mson.NewCodec(mson.WithInterning())
+
+	var first, second record
+
+	for _, v := range []*record{&first, &second} {
+		if err := codec.Unmarshal([]byte(`{"kind":"click","meta":{"source":"web"}}`), v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !reflect.DeepEqual(first, second) || first.Kind != "click" || first.Meta["source"] != "web" {
+		t.Fatalf("got %+v and %+v", first, second)
+	}
+}
+
+func TestArena(t *testing.T) {
+	type record struct {
+		Kind string                 `json:"kind"`
+		Meta map[string]interface{} `json:"meta"`
+	}
+
+	arena := mson.NewArena()
+	codec := mson.NewCodec(mson.WithArena(arena))
+
+	var first, second record
+
+	if err := codec.Unmarshal([]byte(`{"kind":"click","meta":{"source":"web"}}`), &first); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := codec.Unmarshal([]byte(`{"kind":"click","meta":{"source":"web"}}`), &second); err != nil {
+		t.Fatal(err)
+	}
+
+	arena.Reset()
+
+	// The decoded values stay valid after Reset
+	if !reflect.DeepEqual(first, second) || first.Kind != "click" || first.Meta["source"] != "web" {
+		t.Fatalf("got %+v and %+v", first, second)
+	}
+
+	if err := codec.Unmarshal([]byte(`{"kind":"view"}`), &first); err != nil || first.Kind != "view" || second.Kind != "click" {
+		t.Fatalf("got %+v and %+v, %v", first, second, err)
+	}
+}
@@ -0,0 +1,43 @@
+package mson
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"reflect"
+	"regexp"
+	"text/template"
+)
+
+var (
+	regexpType       = reflect.TypeOf((*regexp.Regexp)(nil))
+	templateType     = reflect.TypeOf((*template.Template)(nil))
+	htmlTemplateType = reflect.TypeOf((*htmltemplate.Template)(nil))
+)
+
+func compileValue(value interface{}, verb string, target reflect.Type, fieldName string) (reflect.Value, error) {
+	source, ok := value.(string)
+
+	if !ok {
+		return reflect.Value{}, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	var compiled interface{}
+	var err error
+
+	switch {
+	case verb == "regexp" && target == regexpType:
+		compiled, err = regexp.Compile(source)
+	case verb == "template" && target == templateType:
+		compiled, err = template.New(fieldName).Parse(source)
+	case verb == "template" && target == htmlTemplateType:
+		compiled, err = htmltemplate.New(fieldName).Parse(source)
+	default:
+		return reflect.Value{}, errorf(ErrTypeMismatch, "mson: cannot compile field %s with %s; field is of type %s", fieldName, verb, target)
+	}
+
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("mson: %w, compilation of field %s failed", err, fieldName)
+	}
+
+	return reflect.ValueOf(compiled), nil
+}
@@ -0,0 +1,100 @@
+package mson
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// maxDecompressedBytes bounds the decompressed size of gzip and zlib values
+// when no limit was set through WithMaxBytes.
+const maxDecompressedBytes = 64 << 20
+
+// decompressValue decodes and decompresses a gzip or zlib value, failing once
+// the output exceeds the limit of the decode, so that a small value cannot
+// expand without bound.
+func decompressValue(value interface{}, format string, fieldName string, limit int) ([]byte, error) {
+	strValue, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(strValue)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, decompression of field %s failed", err, fieldName)
+	}
+
+	var r io.ReadCloser
+
+	if format == "gzip" {
+		r, err = gzip.NewReader(bytes.NewReader(compressed))
+	} else {
+		r, err = zlib.NewReader(bytes.NewReader(compressed))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, decompression of field %s failed", err, fieldName)
+	}
+
+	defer r.Close()
+
+	if limit <= 0 {
+		limit = maxDecompressedBytes
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, decompression of field %s failed", err, fieldName)
+	}
+
+	if len(raw) > limit {
+		return nil, errorf(ErrTooLarge, "mson: field %s decompresses to more than the limit of %d bytes", fieldName, limit)
+	}
+
+	return raw, nil
+}
+
+func compressValue(value interface{}, format string, fieldName string, state *encodeState) (interface{}, error) {
+	var raw []byte
+
+	switch v := value.(type) {
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		var buf bytes.Buffer
+
+		if err := encodeValue(&buf, reflect.ValueOf(value), state); err != nil {
+			return nil, err
+		}
+
+		raw = buf.Bytes()
+	}
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+
+	if format == "gzip" {
+		w = gzip.NewWriter(&buf)
+	} else {
+		w = zlib.NewWriter(&buf)
+	}
+
+	if _, err := w.Write(raw); err != nil {
+		return nil, fmt.Errorf("mson: %w, compression of field %s failed", err, fieldName)
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("mson: %w, compression of field %s failed", err, fieldName)
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
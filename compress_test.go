@@ -0,0 +1,73 @@
+package mson_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/monerowner/mson"
+	"github.com/monerowner/mson/msontest"
+)
+
+type compressed struct {
+	Text    string `json:"text,gzip"`
+	Raw     []byte `json:"raw,zlib"`
+	Payload struct {
+		Name string `json:"name"`
+	} `json:"payload,gzip"`
+}
+
+func TestCompressionOptions(t *testing.T) {
+	v := compressed{Text: strings.Repeat("abc", 100), Raw: []byte{0, 1, 2}}
+	v.Payload.Name = "inner"
+
+	out, err := mson.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(out, []byte("abcabc")) || bytes.Contains(out, []byte("inner")) {
+		t.Fatalf("got %s, want the values compressed", out)
+	}
+
+	var back compressed
+
+	if err := mson.Unmarshal(out, &back); err != nil {
+		t.Fatal(err)
+	}
+
+	if back.Text != v.Text || !bytes.Equal(back.Raw, v.Raw) || back.Payload.Name != "inner" {
+		t.Fatalf("got %+v", back)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"text":"not base64!"}`), &back); err == nil {
+		t.Fatal("decompressed a value that is not base64")
+	}
+}
+
+func TestDecompressionLimit(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(make([]byte, 1<<20))
+	w.Close()
+
+	data := []byte(`{"text":"` + base64.StdEncoding.EncodeToString(buf.Bytes()) + `"}`)
+
+	var v compressed
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithMaxBytes(1<<19)); !errors.Is(err, mson.ErrTooLarge) {
+		t.Fatalf("got %v, want ErrTooLarge", err)
+	}
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithMaxBytes(1<<21)); err != nil || len(v.Text) != 1<<20 {
+		t.Fatalf("got %d bytes, %v", len(v.Text), err)
+	}
+}
+
+func FuzzCompressionOptions(f *testing.F) {
+	msontest.Fuzz(f, compressed{})
+}
@@ -0,0 +1,150 @@
+package mson_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+type concurrentEvent struct {
+	ID    int64    `json:"id"`
+	Name  string   `json:"name,trimmed"`
+	Tags  []string `json:"tags,sort"`
+	Score float64  `json:"score,round,1"`
+}
+
+// These tests are meant to be run with -race, which reports the unguarded
+// accesses to shared state they provoke.
+
+func TestCodecConcurrentUse(t *testing.T) {
+	var md mson.Metadata
+	var mu sync.Mutex
+	var warnings int
+
+	codec := mson.NewCodec(
+		mson.WithAlias("trimmed", "kebab"),
+		mson.WithMetadata(&md),
+		mson.WithWarnings(func(mson.Warning) {
+			mu.Lock()
+			warnings++
+			mu.Unlock()
+		}),
+	)
+
+	const n = 64
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			data := fmt.Sprintf(`{"id":%d,"name":"event","tags":["b","a"],"score":1.25,"extra":true}`, i)
+
+			var v concurrentEvent
+
+			if err := codec.Unmarshal([]byte(data), &v); err != nil {
+				errs <- err
+				return
+			}
+
+			if v.ID != int64(i) || v.Tags[0] != "a" || v.Score != 1.3 {
+				errs <- fmt.Errorf("decoded %+v from %s", v, data)
+				return
+			}
+
+			if _, err := codec.Marshal(v); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if len(md.Unused) != n || warnings != n {
+		t.Errorf("got %d unused keys and %d warnings, want %d of each", len(md.Unused), warnings, n)
+	}
+}
+
+func TestRegistriesConcurrentUse(t *testing.T) {
+	type registered struct {
+		Count int `json:"count,concurrentdouble"`
+	}
+
+	mson.DefineAlias("concurrentdouble", "multiply,2")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 32; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			// Redefining the alias with the same options races with the
+			// decodes reading it unless the registry is guarded
+			mson.DefineAlias("concurrentdouble", "multiply,2")
+			mson.RegisterTypeOptions(concurrentEvent{}, "")
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			var v registered
+
+			if err := mson.Unmarshal([]byte(`{"count":21}`), &v); err != nil {
+				t.Error(err)
+			} else if v.Count != 42 {
+				t.Errorf("got %d, want 42", v.Count)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestUnmarshalConcurrentFirstUse(t *testing.T) {
+	// Each type is decoded for the first time by all goroutines at once,
+	// filling the plan and field caches concurrently
+	type first struct {
+		A string `json:"a"`
+		B int    `json:"b"`
+	}
+
+	type second struct {
+		A string `json:"a,snake"`
+		B first  `json:"b"`
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			var f first
+			var s second
+
+			if err := mson.Unmarshal([]byte(`{"a":"x","b":1}`), &f); err != nil || f.B != 1 {
+				t.Errorf("got %+v, %v", f, err)
+			}
+
+			if err := mson.Unmarshal([]byte(`{"a":"HelloWorld","b":{"b":2}}`), &s); err != nil || s.A != "hello_world" || s.B.B != 2 {
+				t.Errorf("got %+v, %v", s, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,104 @@
+package mson
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// isBound reports whether the parts of a min or max option bound the value,
+// as in min,1, rather than aggregating an array, as min alone does.
+func isBound(parts []string) bool {
+	return (parts[0] == "min" || parts[0] == "max") && len(parts) > 1
+}
+
+// checkBound checks a value against the bound of a min or max option: numbers
+// must not lie beyond it, and strings, arrays and objects must not hold more
+// or fewer characters or elements than it.
+func checkBound(value interface{}, parts []string, fieldName string) error {
+	bound, err := strconv.ParseFloat(unquote(parts[1]), 64)
+
+	if err != nil {
+		panic(errorf(ErrInvalidTag, "mson: tag option '%s' requires a number, got %s", parts[0], parts[1]))
+	}
+
+	var n float64
+	var unit string
+
+	switch rv := reflect.ValueOf(value); rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = rv.Float()
+	case reflect.String:
+		if rv.Type() == jsonNumberType {
+			if n, err = strconv.ParseFloat(rv.String(), 64); err != nil {
+				return errorf(ErrOverflow, "mson: field %s holds number %s, which overflows float64", fieldName, rv)
+			}
+
+			break
+		}
+
+		n, unit = float64(utf8.RuneCountInString(rv.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		n, unit = float64(rv.Len()), " elements"
+	default:
+		return errorf(ErrTypeMismatch, "mson: field %s is not a number, a string, an array or an object", fieldName)
+	}
+
+	switch {
+	case parts[0] == "min" && n < bound:
+		return &ValidationError{Field: fieldName, Value: fmt.Sprint(value), Reason: "must be at least " + unquote(parts[1]) + unit}
+	case parts[0] == "max" && n > bound:
+		return &ValidationError{Field: fieldName, Value: fmt.Sprint(value), Reason: "must be at most " + unquote(parts[1]) + unit}
+	}
+
+	return nil
+}
+
+// patterns caches the regular expressions of regex options.
+var patterns sync.Map
+
+// matchPattern checks a string against the regular expression of a regex
+// option, which must match somewhere in it unless anchored with ^ and $.
+func matchPattern(value interface{}, parts []string, fieldName string) error {
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'regex' requires a pattern argument"))
+	}
+
+	source := unquote(parts[1])
+	re, ok := patterns.Load(source)
+
+	if !ok {
+		compiled, err := regexp.Compile(source)
+
+		if err != nil {
+			panic(errorf(ErrInvalidTag, "mson: tag option 'regex' holds an invalid pattern: %v", err))
+		}
+
+		re, _ = patterns.LoadOrStore(source, compiled)
+	}
+
+	s, ok := value.(string)
+
+	if !ok {
+		rv := reflect.ValueOf(value)
+
+		if rv.Kind() != reflect.String {
+			return errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+		}
+
+		s = rv.String()
+	}
+
+	if !re.(*regexp.Regexp).MatchString(s) {
+		return &ValidationError{Field: fieldName, Value: s, Reason: "must match " + source}
+	}
+
+	return nil
+}
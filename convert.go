@@ -0,0 +1,278 @@
+package mson
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Convert copies src, a struct or a pointer to one, into the struct pointed to
+// by dst. Fields are matched by their keys, as Unmarshal matches them, and
+// their values are copied without running the options of either struct, so
+// that a time tagged unix stays the same time and a redacted field keeps its
+// value. Values of different types are converted where Unmarshal would
+// convert them: numbers to numeric types that hold them exactly, values to
+// the named types of their kind, and structs, slices, arrays and maps element
+// by element. Anything else is reported as ErrTypeMismatch. Fields of dst
+// with no counterpart in src are zeroed, as Unmarshal zeroes the fields of
+// absent keys.
+func Convert(src any, dst any) error {
+	rv := reflect.ValueOf(dst)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("mson: Convert requires a non-nil pointer to a struct")
+	}
+
+	sv := derefValue(reflect.ValueOf(src))
+
+	if sv.Kind() != reflect.Struct {
+		return errors.New("mson: Convert requires a struct or a pointer to a struct to copy")
+	}
+
+	return convertStruct(rv.Elem(), sv, "")
+}
+
+// Clone returns a deep copy of v, which must be a struct or a pointer to one.
+// Every field is copied, including those tagged "-", and no options run.
+// Pointers, slices and maps are copied rather than shared, except in
+// unexported fields, which are copied as an assignment copies them, and
+// channels and functions are shared with v.
+func Clone[T any](v T) (T, error) {
+	var out T
+
+	rt := reflect.TypeOf(&out).Elem()
+
+	if rt.Kind() != reflect.Struct && (rt.Kind() != reflect.Pointer || rt.Elem().Kind() != reflect.Struct) {
+		return out, errors.New("mson: Clone requires a struct or a pointer to a struct")
+	}
+
+	copyValue(reflect.ValueOf(&out).Elem(), reflect.ValueOf(v))
+
+	return out, nil
+}
+
+// copyValue stores a deep copy of src in dst, which has the same type.
+func copyValue(dst, src reflect.Value) {
+	if isBigType(src.Type()) && src.Kind() == reflect.Struct {
+		// The math/big types hold their digits in unexported slices, which
+		// their Set methods copy
+		x := reflect.New(src.Type())
+		x.Elem().Set(src)
+		dst.Addr().MethodByName("Set").Call([]reflect.Value{x})
+
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+
+		elem := reflect.New(src.Type().Elem())
+		copyValue(elem.Elem(), src.Elem())
+		dst.Set(elem)
+	case reflect.Interface:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+
+		elem := reflect.New(src.Elem().Type()).Elem()
+		copyValue(elem, src.Elem())
+		dst.Set(elem)
+	case reflect.Struct:
+		// Unexported fields cannot be set one by one, so the struct is copied
+		// whole before the fields that can be set are copied deeply
+		dst.Set(src)
+
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				copyValue(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+
+		for i := 0; i < src.Len(); i++ {
+			copyValue(s.Index(i), src.Index(i))
+		}
+
+		dst.Set(s)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return
+		}
+
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+
+		for iter.Next() {
+			elem := reflect.New(src.Type().Elem()).Elem()
+			copyValue(elem, iter.Value())
+			m.SetMapIndex(iter.Key(), elem)
+		}
+
+		dst.Set(m)
+	default:
+		dst.Set(src)
+	}
+}
+
+// convertStruct copies the fields of src into the fields of dst with the same
+// keys, converting their values with convertValue.
+func convertStruct(dst, src reflect.Value, prefix string) error {
+	s := &settings{}
+	byKey := map[string]reflect.StructField{}
+
+	for _, f := range structFields(src.Type(), s) {
+		name, _, _ := parseTag(f, s)
+		byKey[s.key(name)] = f
+	}
+
+	dst.Set(reflect.Zero(dst.Type()))
+
+	for _, f := range structFields(dst.Type(), s) {
+		name, _, _ := parseTag(f, s)
+		sf, ok := byKey[s.key(name)]
+
+		if !ok {
+			continue
+		}
+
+		from, ok := fieldByIndex(src, sf.Index, false)
+
+		// The fields of a nil embedded struct are left zero
+		if !ok {
+			continue
+		}
+
+		to, ok := fieldByIndex(dst, f.Index, true)
+
+		if !ok {
+			continue
+		}
+
+		if err := convertValue(to, from, prefix+name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertValue stores a deep copy of src in dst, converting it to dst's type
+// as Convert describes.
+func convertValue(dst, src reflect.Value, fieldName string) error {
+	if src.Type() == dst.Type() {
+		copyValue(dst, src)
+		return nil
+	}
+
+	switch {
+	case src.Kind() == reflect.Interface || src.Kind() == reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		return convertValue(dst, src.Elem(), fieldName)
+	case dst.Kind() == reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+
+		if err := convertValue(elem.Elem(), src, fieldName); err != nil {
+			return err
+		}
+
+		dst.Set(elem)
+
+		return nil
+	case dst.Kind() == reflect.Interface && src.Type().AssignableTo(dst.Type()):
+		elem := reflect.New(src.Type()).Elem()
+		copyValue(elem, src)
+		dst.Set(elem)
+
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if ok, err := assignNumber(dst, src, fieldName); ok {
+			return err
+		}
+	case reflect.String, reflect.Bool:
+		if src.Kind() == dst.Kind() {
+			dst.Set(src.Convert(dst.Type()))
+			return nil
+		}
+	case reflect.Struct:
+		if src.Kind() == reflect.Struct && !isBigType(src.Type()) && !isBigType(dst.Type()) {
+			return convertStruct(dst, src, fieldName+".")
+		}
+	case reflect.Slice, reflect.Array:
+		if src.Kind() != reflect.Slice && src.Kind() != reflect.Array {
+			break
+		}
+
+		if src.Kind() == reflect.Slice && src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		if dst.Kind() == reflect.Slice {
+			dst.Set(reflect.MakeSlice(dst.Type(), src.Len(), src.Len()))
+		} else if src.Len() > dst.Len() {
+			return errorf(ErrTypeMismatch, "mson: field %s holds %d elements, which do not fit %s", fieldName, src.Len(), dst.Type())
+		} else {
+			dst.Set(reflect.Zero(dst.Type()))
+		}
+
+		for i := 0; i < src.Len(); i++ {
+			if err := convertValue(dst.Index(i), src.Index(i), fieldName); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case reflect.Map:
+		if src.Kind() != reflect.Map || src.Type().Key().Kind() != dst.Type().Key().Kind() {
+			break
+		}
+
+		if src.IsNil() {
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		}
+
+		m := reflect.MakeMapWithSize(dst.Type(), src.Len())
+		iter := src.MapRange()
+
+		for iter.Next() {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+
+			if err := convertValue(elem, iter.Value(), fieldName); err != nil {
+				return err
+			}
+
+			m.SetMapIndex(iter.Key().Convert(dst.Type().Key()), elem)
+		}
+
+		dst.Set(m)
+
+		return nil
+	}
+
+	return errorf(ErrTypeMismatch, "mson: cannot convert field %s of type %s to %s", fieldName, src.Type(), dst.Type())
+}
@@ -0,0 +1,245 @@
+package mson_test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+	"github.com/monerowner/mson/msontest"
+)
+
+// level decodes itself from text, as many enum types do.
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return errors.New("unknown level " + string(text))
+	}
+
+	return nil
+}
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte(map[level]string{1: "low", 2: "high"}[l]), nil
+}
+
+type snapshot struct {
+	At      time.Time   `json:"at"`
+	Expires *time.Time  `json:"expires"`
+	Blob    []byte      `json:"blob"`
+	Addr    net.IP      `json:"addr"`
+	Level   level       `json:"level"`
+	History []time.Time `json:"history"`
+	Nested  struct {
+		Seen time.Time `json:"seen"`
+	} `json:"nested"`
+}
+
+func newSnapshot() snapshot {
+	at := time.Date(2024, 5, 6, 7, 8, 9, 10, time.UTC)
+	expires := at.Add(time.Hour)
+
+	s := snapshot{
+		At:      at,
+		Expires: &expires,
+		Blob:    []byte{0, 1, 2, 0xff},
+		Addr:    net.ParseIP("192.0.2.1"),
+		Level:   2,
+		History: []time.Time{at, expires},
+	}
+	s.Nested.Seen = at
+
+	return s
+}
+
+func TestCloneStandardTypes(t *testing.T) {
+	in := newSnapshot()
+	out, err := mson.Clone(in)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("got %+v, want %+v", out, in)
+	}
+
+	if out.Expires == in.Expires || &out.Blob[0] == &in.Blob[0] {
+		t.Fatal("clone shares memory with the original")
+	}
+}
+
+func TestClonePointer(t *testing.T) {
+	in := newSnapshot()
+	out, err := mson.Clone(&in)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out == &in || !reflect.DeepEqual(*out, in) {
+		t.Fatalf("got %+v, want a copy of %+v", out, in)
+	}
+
+	var nilIn *snapshot
+
+	if out, err := mson.Clone(nilIn); out != nil || err != nil {
+		t.Fatalf("got %v, %v for a nil pointer", out, err)
+	}
+}
+
+func TestConvertBetweenShapes(t *testing.T) {
+	type record struct {
+		ID      int64             `json:"id,string"`
+		Created time.Time         `json:"created,unix"`
+		Key     []byte            `json:"key"`
+		Secret  string            `json:"secret,redact"`
+		Price   int               `json:"price,multiply,100"`
+		Scores  []int32           `json:"scores"`
+		Labels  map[string]uint8  `json:"labels"`
+		Owner   struct{ Age int } `json:"owner"`
+		Hidden  string            `json:"-"`
+	}
+
+	type view struct {
+		ID      uint64              `json:"id"`
+		Created time.Time           `json:"created"`
+		Key     []byte              `json:"key"`
+		Secret  string              `json:"secret,maxlen,1"`
+		Price   float64             `json:"PRICE"`
+		Scores  [3]float64          `json:"scores"`
+		Labels  map[string]*int     `json:"labels"`
+		Owner   *struct{ Age int8 } `json:"owner"`
+		Hidden  string              `json:"hidden"`
+		Extra   string              `json:"extra"`
+	}
+
+	src := record{ID: 9007199254740993, Created: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC), Key: []byte("k"), Secret: "sk", Price: 12,
+		Scores: []int32{1, 2}, Labels: map[string]uint8{"a": 1}, Hidden: "h"}
+	src.Owner.Age = 36
+
+	dst := view{Extra: "stale"}
+
+	if err := mson.Convert(src, &dst); err != nil {
+		t.Fatal(err)
+	}
+
+	one := 1
+	want := view{ID: 9007199254740993, Created: src.Created, Key: []byte("k"), Secret: "sk", Price: 12,
+		Scores: [3]float64{1, 2}, Labels: map[string]*int{"a": &one}, Owner: &struct{ Age int8 }{36}}
+
+	if !reflect.DeepEqual(dst, want) {
+		t.Fatalf("got %+v, want %+v", dst, want)
+	}
+
+	if &dst.Key[0] == &src.Key[0] {
+		t.Fatal("converted value shares memory with the original")
+	}
+}
+
+func TestConvertMismatches(t *testing.T) {
+	type source struct {
+		Count int      `json:"count"`
+		Name  string   `json:"name"`
+		List  []string `json:"list"`
+	}
+
+	tests := []struct {
+		dst  any
+		want error
+	}{
+		{&struct {
+			Count int8 `json:"count"`
+		}{}, mson.ErrOverflow},
+		{&struct {
+			Count string `json:"count"`
+		}{}, mson.ErrTypeMismatch},
+		{&struct {
+			Name int `json:"name"`
+		}{}, mson.ErrTypeMismatch},
+		{&struct {
+			List [1]string `json:"list"`
+		}{}, mson.ErrTypeMismatch},
+	}
+
+	src := source{Count: 300, Name: "a", List: []string{"x", "y"}}
+
+	for _, tt := range tests {
+		if err := mson.Convert(src, tt.dst); !errors.Is(err, tt.want) {
+			t.Errorf("converting into %T returned %v, want %v", tt.dst, err, tt.want)
+		}
+	}
+
+	if err := mson.Convert(src, source{}); err == nil {
+		t.Error("converted into a struct value")
+	}
+}
+
+func TestCloneSkipsOptions(t *testing.T) {
+	type price struct {
+		Cents   int        `json:"cents,multiply,100"`
+		At      time.Time  `json:"at,unix"`
+		Token   string     `json:"token,redact"`
+		Skipped string     `json:"-"`
+		Amount  *big.Float `json:"amount"`
+		note    string
+	}
+
+	in := price{Cents: 250, At: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC), Token: "secret", Skipped: "kept", Amount: big.NewFloat(1.5), note: "n"}
+	out, err := mson.Clone(in)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(in, out) {
+		t.Fatalf("got %+v, want %+v", out, in)
+	}
+
+	if out.Amount == in.Amount {
+		t.Fatal("clone shares memory with the original")
+	}
+
+	if _, err := mson.Clone(map[string]int{}); err == nil {
+		t.Fatal("cloned a map")
+	}
+}
+
+func TestUnmarshalSelfDecodingTypes(t *testing.T) {
+	var v snapshot
+
+	err := mson.Unmarshal([]byte(`{"level":"low","blob":"AAEC/w==","addr":"::1","at":"2024-05-06T07:08:09Z"}`), &v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Level != 1 || !bytes.Equal(v.Blob, []byte{0, 1, 2, 0xff}) || !v.Addr.Equal(net.IPv6loopback) || v.At.Year() != 2024 {
+		t.Fatalf("got %+v", v)
+	}
+
+	for doc, want := range map[string]string{
+		`{"level":"medium"}`:    "unknown level medium",
+		`{"blob":"not base64"}`: "base64",
+		`{"at":"yesterday"}`:    "parsing time",
+	} {
+		if err := mson.Unmarshal([]byte(doc), &v); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("decoding %s returned %v, want an error mentioning %q", doc, err, want)
+		}
+	}
+}
+
+func TestRoundTripStandardTypes(t *testing.T) {
+	msontest.RoundTrip(t, newSnapshot())
+}
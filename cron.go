@@ -0,0 +1,222 @@
+package mson
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type cronSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	domStar, dowStar                      bool
+}
+
+type cronField struct {
+	min, max int
+	names    []string
+}
+
+var (
+	secondField = cronField{0, 59, nil}
+	minuteField = cronField{0, 59, nil}
+	hourField   = cronField{0, 23, nil}
+	domField    = cronField{1, 31, nil}
+	monthField  = cronField{1, 12, []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	dowField    = cronField{0, 7, []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func parseCron(expr string) (*cronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+
+	// A sixth leading field holds the seconds
+	if len(fields) == 5 {
+		fields = append([]string{"0"}, fields...)
+	}
+
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("cron expression %q must have 5 or 6 fields", expr)
+	}
+
+	var s cronSchedule
+	var err error
+
+	for i, f := range []struct {
+		bits *uint64
+		spec cronField
+	}{{&s.second, secondField}, {&s.minute, minuteField}, {&s.hour, hourField}, {&s.dom, domField}, {&s.month, monthField}, {&s.dow, dowField}} {
+		if *f.bits, err = parseCronField(fields[i], f.spec); err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+	}
+
+	// Sunday may be written as 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	s.domStar = fields[3] == "*" || fields[3] == "?"
+	s.dowStar = fields[5] == "*" || fields[5] == "?"
+
+	return &s, nil
+}
+
+func parseCronField(field string, spec cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangeSpec, stepSpec, hasStep := strings.Cut(part, "/")
+		lo, hi := spec.min, spec.max
+		step := 1
+
+		if hasStep {
+			n, err := strconv.Atoi(stepSpec)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+
+		if rangeSpec != "*" && rangeSpec != "?" {
+			first, last, isRange := strings.Cut(rangeSpec, "-")
+
+			n, err := parseCronValue(first, spec)
+			if err != nil {
+				return 0, err
+			}
+			lo = n
+
+			if isRange {
+				if hi, err = parseCronValue(last, spec); err != nil {
+					return 0, err
+				}
+			} else if !hasStep {
+				hi = lo
+			}
+		}
+
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range %q", part)
+		}
+
+		for i := lo; i <= hi; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseCronValue(s string, spec cronField) (int, error) {
+	for i, name := range spec.names {
+		if strings.EqualFold(s, name) {
+			if spec.min == 1 {
+				return i + 1, nil
+			}
+			return i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(s)
+
+	if err != nil || n < spec.min || n > spec.max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", s, spec.min, spec.max)
+	}
+
+	return n, nil
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	// Like Vixie cron, restricting both day fields matches either of them
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+// next returns the first activation strictly after t, or the zero time if the
+// schedule never fires within the next five years. The fields are matched
+// against the wall clock of t's location, which is stepped through with
+// time.Date so that zones with offsets of a fraction of an hour work too.
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.Add(time.Second - time.Duration(t.Nanosecond())).Truncate(time.Second)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location()), time.Hour)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, t.Location()), time.Minute)
+		case s.second&(1<<uint(t.Second())) == 0:
+			t = t.Add(time.Second)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// forward returns u, the start of the next wall clock hour or minute after t,
+// or t moved by d when u does not lie after t: time.Date may resolve a wall
+// clock time repeated by a daylight saving change to its earlier instance.
+func forward(t, u time.Time, d time.Duration) time.Time {
+	if u.After(t) {
+		return u
+	}
+
+	return t.Add(d)
+}
+
+func convertCron(value interface{}, target reflect.Type, fieldName string, now time.Time) (interface{}, error) {
+	expr, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	schedule, err := parseCron(expr)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, parsing of field %s failed", err, fieldName)
+	}
+
+	// Time fields receive the next time the schedule fires
+	if target == timeType {
+		next := schedule.next(now)
+
+		if next.IsZero() {
+			return nil, fmt.Errorf("mson: cron expression in field %s never fires", fieldName)
+		}
+
+		return next, nil
+	}
+
+	return strings.TrimSpace(expr), nil
+}
@@ -0,0 +1,57 @@
+package mson
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestCronNextInZones(t *testing.T) {
+	tests := []struct {
+		zone, expr, from, want string
+	}{
+		// Offsets of a fraction of an hour
+		{"Asia/Kolkata", "0 9 * * *", "2024-03-10T08:59:59", "2024-03-10T09:00:00"},
+		{"Asia/Kolkata", "30 * * * *", "2024-03-10T08:45:00", "2024-03-10T09:30:00"},
+		{"Asia/Kathmandu", "*/15 * * * *", "2024-03-10T08:01:00", "2024-03-10T08:15:00"},
+		{"UTC", "0 0 29 2 *", "2024-03-01T00:00:00", "2028-02-29T00:00:00"},
+		{"UTC", "0 12 1 * mon", "2024-06-02T00:00:00", "2024-06-03T12:00:00"},
+		{"UTC", "30 0 0 * * *", "2024-06-02T00:00:30", "2024-06-03T00:00:30"},
+		// 02:30 does not exist on the day clocks move forward
+		{"America/New_York", "30 2 * * *", "2024-03-10T00:00:00", "2024-03-11T02:30:00"},
+		// 01:30 happens twice on the day clocks move back
+		{"America/New_York", "30 1 * * *", "2024-11-03T00:00:00", "2024-11-03T01:30:00"},
+	}
+
+	for _, tt := range tests {
+		loc, err := time.LoadLocation(tt.zone)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		from, _ := time.ParseInLocation("2006-01-02T15:04:05", tt.from, loc)
+		want, _ := time.ParseInLocation("2006-01-02T15:04:05", tt.want, loc)
+		s, err := parseCron(tt.expr)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := s.next(from); !got.Equal(want) {
+			t.Errorf("%s in %s after %s: got %v, want %v", tt.expr, tt.zone, tt.from, got, want)
+		}
+	}
+}
+
+func TestCronNever(t *testing.T) {
+	s, err := parseCron("0 0 31 2 *")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := s.next(time.Now()); !got.IsZero() {
+		t.Fatalf("got %v for a schedule that never fires", got)
+	}
+}
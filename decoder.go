@@ -0,0 +1,237 @@
+package mson
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// A Decoder reads and decodes a stream of JSON documents, either one after
+// another as in NDJSON or as the elements of a top-level array.
+type Decoder struct {
+	r        *bufio.Reader
+	dec      *json.Decoder
+	settings settings
+	inArray  bool
+	started  bool
+
+	// selected is set once Select positioned the decoder within the first
+	// document, which ends the stream with the selected value. pending holds
+	// that value when it is not an array.
+	selected bool
+	pending  json.RawMessage
+}
+
+// NewDecoder returns a Decoder reading from r that applies the given options
+// to every document.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	d := &Decoder{r: bufio.NewReader(r)}
+	d.dec = json.NewDecoder(d.r)
+
+	for _, opt := range opts {
+		opt(&d.settings)
+	}
+
+	return d
+}
+
+// Decode reads the next document or array element and stores it in the
+// struct pointed to by v. It returns io.EOF once the stream is exhausted.
+func (d *Decoder) Decode(v any) error {
+	raw, err := d.next()
+
+	if err != nil {
+		return err
+	}
+
+	return unmarshal(raw, v, &decodeState{settings: d.settings})
+}
+
+// Select positions the decoder at the value found by following path, a
+// dot-separated list of keys and array indexes such as "results.items" or
+// "pages.0.items", within the first document of the stream. When the value
+// is an array, Decode then returns its elements one after another, reading
+// them as they come; otherwise Decode returns the value itself. In both
+// cases Decode returns io.EOF after the selected value, without reading the
+// rest of the document. The values passed on the way are skipped by a
+// scanner that builds nothing from them, so that selecting a small part of a
+// large document is cheap. Select must be called before Decode.
+func (d *Decoder) Select(path string) error {
+	if d.started {
+		return errors.New("mson: Select must be called before Decode")
+	}
+
+	d.started, d.selected = true, true
+
+	if path != "" {
+		segments := strings.Split(path, ".")
+
+		for i, segment := range segments {
+			if err := d.enter(segment, strings.Join(segments[:i], ".")); err != nil {
+				return err
+			}
+		}
+	}
+
+	if b, err := peekByte(d.r); err != nil {
+		return unexpectedEOF(err)
+	} else if b == '[' {
+		_, err := d.dec.Token()
+		d.inArray = true
+		return err
+	}
+
+	return d.dec.Decode(&d.pending)
+}
+
+// enter moves past the key or index segment of the object or array that
+// comes next, so that the value it holds comes next. It reads the stream
+// itself, before the json.Decoder reading the selected value takes over.
+func (d *Decoder) enter(segment, at string) error {
+	if at == "" {
+		at = "the document"
+	}
+
+	b, err := peekByte(d.r)
+
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+
+	if b != '{' && b != '[' {
+		return errorf(ErrTypeMismatch, "mson: %s is not an object or array, so it has no key %s", at, segment)
+	}
+
+	d.r.ReadByte()
+
+	n, err := strconv.Atoi(segment)
+
+	if b == '[' && (err != nil || n < 0) {
+		return errorf(ErrTypeMismatch, "mson: %s is an array, which has no key %s", at, segment)
+	}
+
+	for i := 0; ; i++ {
+		if c, err := peekByte(d.r); err != nil {
+			return unexpectedEOF(err)
+		} else if c == b+2 {
+			return errorf(ErrFieldMissing, "mson: %s has no key %s", at, segment)
+		}
+
+		if i > 0 {
+			if _, err := expectByte(d.r, ","); err != nil {
+				return err
+			}
+		}
+
+		if b == '[' {
+			if i == n {
+				return nil
+			}
+		} else {
+			key, err := readJSONKey(d.r)
+
+			if err != nil {
+				return err
+			}
+
+			if _, err := expectByte(d.r, ":"); err != nil {
+				return err
+			}
+
+			if d.settings.key(key) == d.settings.key(segment) {
+				return nil
+			}
+		}
+
+		if err := skipJSONValue(d.r); err != nil {
+			return err
+		}
+	}
+}
+
+// next returns the raw bytes of the next document, entering a top-level
+// array the first time it is called on one.
+func (d *Decoder) next() (json.RawMessage, error) {
+	if d.selected && !d.inArray {
+		raw := d.pending
+		d.pending = nil
+
+		if raw == nil {
+			return nil, io.EOF
+		}
+
+		return raw, nil
+	}
+
+	if !d.started {
+		d.started = true
+
+		if b, err := peekByte(d.r); err == nil && b == '[' {
+			if _, err := d.dec.Token(); err != nil {
+				return nil, err
+			}
+
+			d.inArray = true
+		}
+	}
+
+	if d.inArray && !d.dec.More() {
+		// Consume the closing bracket so that trailing garbage is reported
+		if _, err := d.dec.Token(); err != nil {
+			return nil, err
+		}
+
+		d.inArray = false
+
+		// The rest of the document holding a selected array is not read
+		if d.selected {
+			return nil, io.EOF
+		}
+
+		if _, err := d.dec.Token(); err != io.EOF {
+			return nil, errors.New("mson: unexpected data after top-level array")
+		}
+
+		return nil, io.EOF
+	}
+
+	var raw json.RawMessage
+
+	if err := d.dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// Stream decodes every remaining document of d into a value of type T, which
+// must be a struct, and sends it on ch, blocking while ch is full so that slow
+// consumers hold back the decoder. Stream closes ch before returning. It
+// returns nil once the stream is exhausted, the first decode error otherwise,
+// or the context's error when ctx is done first.
+//
+// Stream is a function rather than a method of Decoder because methods cannot
+// take type parameters.
+func Stream[T any](ctx context.Context, d *Decoder, ch chan<- T) error {
+	defer close(ch)
+
+	for {
+		var v T
+
+		if err := d.Decode(&v); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		select {
+		case ch <- v:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
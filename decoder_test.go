@@ -0,0 +1,142 @@
+package mson_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+type streamItem struct {
+	ID   float64 `json:"id"`
+	Name string  `json:"name"`
+}
+
+func decodeAll(t *testing.T, d *mson.Decoder) []streamItem {
+	t.Helper()
+
+	var items []streamItem
+
+	for {
+		var v streamItem
+		err := d.Decode(&v)
+
+		if err == io.EOF {
+			return items
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		items = append(items, v)
+	}
+}
+
+func TestDecoderStreams(t *testing.T) {
+	inputs := []string{
+		"{\"id\":1,\"name\":\"FirstItem\"}\n{\"id\":2,\"name\":\"SecondItem\"}\n",
+		` [ {"id":1,"name":"FirstItem"} , {"id":2,"name":"SecondItem"} ] `,
+	}
+
+	for _, input := range inputs {
+		items := decodeAll(t, mson.NewDecoder(strings.NewReader(input)))
+
+		if len(items) != 2 || items[0] != (streamItem{1, "FirstItem"}) || items[1] != (streamItem{2, "SecondItem"}) {
+			t.Errorf("decoding %s got %+v", input, items)
+		}
+	}
+}
+
+func TestDecoderSelect(t *testing.T) {
+	input := `{"meta":{"skip":[1,2,{"x":"]"}]},"pages":[{"items":[]},{"items":[{"id":3,"name":"A"},{"id":4,"name":"B"}]}],"after":true}`
+
+	d := mson.NewDecoder(strings.NewReader(input))
+
+	if err := d.Select("pages.1.items"); err != nil {
+		t.Fatal(err)
+	}
+
+	if items := decodeAll(t, d); len(items) != 2 || items[0].ID != 3 || items[1].ID != 4 {
+		t.Errorf("got %+v", items)
+	}
+
+	d = mson.NewDecoder(strings.NewReader(input))
+
+	if err := d.Select("pages.1.items.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	if items := decodeAll(t, d); len(items) != 1 || items[0].ID != 3 {
+		t.Errorf("got %+v", items)
+	}
+
+	for _, path := range []string{"missing", "pages.5", "meta.skip.x"} {
+		if err := mson.NewDecoder(strings.NewReader(input)).Select(path); err == nil {
+			t.Errorf("selected %s", path)
+		}
+	}
+
+	d = mson.NewDecoder(strings.NewReader(input))
+	decodeAll(t, d)
+
+	if err := d.Select("pages"); err == nil {
+		t.Error("selected after decoding")
+	}
+}
+
+func TestStream(t *testing.T) {
+	d := mson.NewDecoder(strings.NewReader(`[{"id":1},{"id":2},{"id":3}]`))
+	ch := make(chan streamItem)
+	errc := make(chan error, 1)
+
+	go func() {
+		errc <- mson.Stream(context.Background(), d, ch)
+	}()
+
+	var ids []float64
+
+	for v := range ch {
+		ids = append(ids, v.ID)
+	}
+
+	if err := <-errc; err != nil || len(ids) != 3 || ids[2] != 3 {
+		t.Fatalf("got %v, %v", ids, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	d = mson.NewDecoder(strings.NewReader(`{"id":1}`))
+
+	if err := mson.Stream(ctx, d, make(chan streamItem)); err != context.Canceled {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+
+	d = mson.NewDecoder(strings.NewReader(`{"id":}`))
+
+	if err := mson.Stream(context.Background(), d, make(chan streamItem, 1)); err == nil {
+		t.Fatal("streamed an invalid document")
+	}
+}
+
+func TestDecoderSelectSkipsValues(t *testing.T) {
+	// The skipped values hold brackets and escaped quotes inside strings
+	input := `{"a":"}\"]","b":{"c":[{"d":"\\"},[]],"e":-1.5e3,"f":null},"items":[{"id":1,"name":"A"}]}`
+
+	d := mson.NewDecoder(strings.NewReader(input))
+
+	if err := d.Select("items"); err != nil {
+		t.Fatal(err)
+	}
+
+	if items := decodeAll(t, d); len(items) != 1 || items[0].ID != 1 {
+		t.Errorf("got %+v", items)
+	}
+
+	if err := mson.NewDecoder(strings.NewReader(`{"a":[1,2},"items":[]}`)).Select("items"); err == nil {
+		t.Error("selected in a malformed document")
+	}
+}
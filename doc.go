@@ -0,0 +1,44 @@
+// Package mson decodes and encodes JSON through struct tag options that
+// transform, validate and reshape values on their way in and out.
+//
+// # Concurrency
+//
+// Unmarshal, Marshal and their variants may be called from any number of
+// goroutines at once, as may the methods of a Codec. Each call works on its
+// own state; the package-level registries filled by DefineAlias,
+// RegisterCipher, RegisterTypeOptions, RegisterPhoneNormalizer,
+// RegisterLanguageTagParser and RegisterUnicodeNormalizer are guarded by
+// locks and may be updated while calls are in flight, in which case a call
+// observes either the old or the new entry.
+//
+// Functions and implementations handed to mson are shared in the same way: a
+// Cipher, PhoneNormalizer, LanguageTagParser or UnicodeNormalizer, and the
+// functions given to WithWarnings and WithClock, must be safe for concurrent
+// use if the calls that use them run concurrently. A Metadata given to
+// WithMetadata may be shared too: concurrent decodes append to it in turn,
+// and it must only be read once they have returned.
+//
+// # Tags
+//
+// Fields are read from their mson tag, or from their json tag when they have
+// none, with the grammar of encoding/json: "-" skips a field, "-," names its
+// key "-", and the fields of embedded structs are promoted into the object
+// of the struct embedding them. Options of a json tag mson does not know are
+// ignored. Keys holding commas may be quoted, as in `mson:"\"a,b\""`.
+//
+// # Option chains
+//
+// The options of a tag run in order, each receiving the value produced by
+// the one before it. Chains whose options cannot work on that value, such as
+// round after unix, which produces a time, or that end in a value the field
+// cannot hold, panic with ErrInvalidTag when the field is first decoded,
+// whatever the document holds.
+//
+// # Performance
+//
+// Structs whose fields are all strings, booleans or numbers without decoding
+// options are decoded straight from the document, without building the
+// intermediate map that tag options work on, unless an option of the call
+// such as WithWarnings or WithMetadata needs it. Such decodes allocate
+// nothing beyond the strings they store.
+package mson
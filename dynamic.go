@@ -0,0 +1,49 @@
+package mson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A FieldSpec describes one field of a struct built by StructOf.
+type FieldSpec struct {
+	// Name is the JSON key the field is decoded from.
+	Name string
+
+	// Type is the type of the field.
+	Type reflect.Type
+
+	// Options is the comma-separated chain of tag options applied to the
+	// field, as it would appear after the name in a struct tag.
+	Options string
+}
+
+// StructOf builds a struct type from fields at runtime, so that documents can
+// be decoded with mson options when no struct exists at compile time. Field i
+// of the struct is named F<i> and carries an mson tag made of the spec's name
+// and options; decode into reflect.New(t).Interface() and read the fields back
+// by index. StructOf panics if a spec has no name or no type.
+func StructOf(fields []FieldSpec) reflect.Type {
+	structFields := make([]reflect.StructField, len(fields))
+
+	for i, spec := range fields {
+		if spec.Name == "" || spec.Type == nil {
+			panic(fmt.Errorf("mson: field %d passed to StructOf requires a name and a type", i))
+		}
+
+		tag := spec.Name
+
+		if spec.Options != "" {
+			tag += "," + spec.Options
+		}
+
+		structFields[i] = reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: spec.Type,
+			Tag:  reflect.StructTag(fmt.Sprintf("mson:%q", strings.TrimSpace(tag))),
+		}
+	}
+
+	return reflect.StructOf(structFields)
+}
@@ -0,0 +1,41 @@
+package mson_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+func TestStructOf(t *testing.T) {
+	rt := mson.StructOf([]mson.FieldSpec{
+		{Name: "id", Type: reflect.TypeOf(float64(0))},
+		{Name: "created", Type: reflect.TypeOf(time.Time{}), Options: "unix"},
+		{Name: "tags", Type: reflect.TypeOf([]string(nil)), Options: "sort,unique"},
+	})
+
+	v := reflect.New(rt)
+
+	if err := mson.Unmarshal([]byte(`{"id":7,"created":1700000000,"tags":["b","a","b"]}`), v.Interface()); err != nil {
+		t.Fatal(err)
+	}
+
+	s := v.Elem()
+
+	if s.Field(0).Float() != 7 || !s.Field(1).Interface().(time.Time).Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("got %v", s.Interface())
+	}
+
+	if tags := s.Field(2).Interface().([]string); !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("got tags %v", tags)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("built a struct from a spec without a type")
+		}
+	}()
+
+	mson.StructOf([]mson.FieldSpec{{Name: "x"}})
+}
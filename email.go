@@ -0,0 +1,38 @@
+package mson
+
+import (
+	"net/mail"
+	"strings"
+)
+
+// normalizeEmail validates an RFC 5322 address and returns it with its domain
+// lowercased. The noplus argument drops a +tag from the local part, and strict
+// rejects forms other than a bare address, such as "Name <user@host>".
+func normalizeEmail(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	addr, err := mail.ParseAddress(s)
+
+	if err != nil {
+		return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must be an email address"}
+	}
+
+	if containsOption(parts[1:], "strict") && (addr.Name != "" || addr.Address != strings.TrimSpace(s)) {
+		return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must be a bare email address"}
+	}
+
+	at := strings.LastIndexByte(addr.Address, '@')
+	local, domain := addr.Address[:at], strings.ToLower(addr.Address[at+1:])
+
+	if containsOption(parts[1:], "noplus") {
+		if i := strings.IndexByte(local, '+'); i > 0 {
+			local = local[:i]
+		}
+	}
+
+	return local + "@" + domain, nil
+}
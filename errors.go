@@ -0,0 +1,98 @@
+package mson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// An UnknownEnumValueError is returned in strict mode when a field tagged with
+// oneof receives a value outside of its accepted set.
+type UnknownEnumValueError struct {
+	Field    string
+	Value    string
+	Accepted []string
+}
+
+func (e *UnknownEnumValueError) Error() string {
+	return fmt.Sprintf("mson: field %s received unknown value %q, expected one of %s", e.Field, e.Value, strings.Join(e.Accepted, ", "))
+}
+
+// Errors returned by mson wrap one of these values when the failure falls into
+// its category, so callers can branch on it with errors.Is. Invalid tags are
+// reported by panicking with an error that wraps ErrInvalidTag or
+// ErrUnknownOption.
+var (
+	// ErrFieldMissing reports that an option references a field or property
+	// that is absent from the document.
+	ErrFieldMissing = errors.New("mson: field missing")
+
+	// ErrTypeMismatch reports that a value does not have the type an option
+	// or the destination field requires.
+	ErrTypeMismatch = errors.New("mson: type mismatch")
+
+	// ErrInvalidTag reports a tag option with missing or malformed arguments.
+	ErrInvalidTag = errors.New("mson: invalid tag")
+
+	// ErrOverflow reports a number that does not fit its destination.
+	ErrOverflow = errors.New("mson: overflow")
+
+	// ErrUnknownOption reports a tag option that mson does not know.
+	ErrUnknownOption = errors.New("mson: unknown tag option")
+
+	// ErrInvalidSignature reports a payload whose signature does not verify.
+	ErrInvalidSignature = errors.New("mson: invalid signature")
+
+	// ErrChecksumMismatch reports a checksum verified by the hash option
+	// that does not match the hashed field.
+	ErrChecksumMismatch = errors.New("mson: checksum mismatch")
+
+	// ErrJSONAPI reports a JSON:API document holding errors rather than
+	// data. The error objects are found in the JSONAPIError wrapping it.
+	ErrJSONAPI = errors.New("mson: JSON:API document holds errors")
+
+	// ErrSkippedField reports a key of the document naming a field that
+	// mson cannot decode into, under WithSkippedFields(SkipWithError).
+	ErrSkippedField = errors.New("mson: skipped field")
+
+	// ErrDuplicate reports elements of an array grouped by groupby that
+	// share a key where only one is allowed.
+	ErrDuplicate = errors.New("mson: duplicate key")
+
+	// ErrTooLarge reports a document, or a value decompressed by gzip or
+	// zlib, larger than the limit set with WithMaxBytes.
+	ErrTooLarge = errors.New("mson: too large")
+)
+
+// kindError attaches one of the sentinel errors to an error without changing
+// its message.
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// errorf is like fmt.Errorf, but the returned error also wraps kind.
+func errorf(kind error, format string, a ...any) error {
+	return &kindError{kind, fmt.Errorf(format, a...)}
+}
+
+// A ValidationError is returned when a field's value is rejected by a
+// validating tag option. Its message is meant to be shown to the client that
+// sent the document.
+type ValidationError struct {
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("mson: field %s %s, got %q", e.Field, e.Reason, e.Value)
+}
@@ -0,0 +1,215 @@
+package mson_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+func TestFieldErrorPosition(t *testing.T) {
+	var v struct {
+		Name string `json:"name"`
+		Tint string `json:"tint,color"`
+	}
+
+	data := []byte("{\n  \"name\": \"ada\",\n  \"tint\": 5\n}")
+	err := mson.Unmarshal(data, &v)
+
+	var fieldErr *mson.FieldError
+
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("got %v, want a FieldError", err)
+	}
+
+	if fieldErr.Field != "tint" || fieldErr.Line != 3 || fieldErr.Column != 11 || data[fieldErr.Offset] != '5' {
+		t.Errorf("got %+v", fieldErr)
+	}
+
+	if !errors.Is(err, mson.ErrTypeMismatch) {
+		t.Errorf("got %v, want it to wrap ErrTypeMismatch", err)
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		v    any
+		data string
+		want error
+	}{
+		{&struct {
+			ID uint64 `json:"id,snowflake"`
+		}{}, `{"id":1e300}`, mson.ErrOverflow},
+		{&struct {
+			S []interface{} `json:"s,sort"`
+		}{}, `{"s":{}}`, mson.ErrTypeMismatch},
+		{&struct {
+			S []interface{} `json:"s,sort"`
+		}{}, `{"s":[1,"a"]}`, mson.ErrTypeMismatch},
+		{&struct {
+			S []interface{} `json:"s,unique"`
+		}{}, `{"s":[{},{}]}`, mson.ErrTypeMismatch},
+		{&struct {
+			T time.Time `json:"t,unix"`
+		}{}, `{"t":"soon"}`, mson.ErrTypeMismatch},
+		{&struct {
+			D time.Duration `json:"d,duration"`
+		}{}, `{"d":"long"}`, mson.ErrTypeMismatch},
+		{&struct {
+			S string `json:"s"`
+		}{}, `["s"]`, mson.ErrTypeMismatch},
+	}
+
+	for _, tt := range tests {
+		if err := mson.Unmarshal([]byte(tt.data), tt.v); !errors.Is(err, tt.want) {
+			t.Errorf("decoding %s into %T returned %v, want %v", tt.data, tt.v, err, tt.want)
+		}
+	}
+}
+
+func TestUnknownJSONOptionsIgnored(t *testing.T) {
+	// Options that mson does not know are left to other json libraries when
+	// they lead the json tag, but fail in an mson tag
+	var v struct {
+		S string `json:"s,nosuchoption"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"s":"x"}`), &v); err != nil || v.S != "x" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+}
+
+func TestInvalidTagPanics(t *testing.T) {
+	tests := []any{
+		&struct {
+			S string `mson:"s,nosuchoption"`
+		}{},
+		&struct {
+			S string `json:"s,mime"`
+		}{},
+		&struct {
+			S string `json:"s,maxlen,many"`
+		}{},
+	}
+
+	for _, v := range tests {
+		func() {
+			defer func() {
+				err, _ := recover().(error)
+
+				if !errors.Is(err, mson.ErrInvalidTag) && !errors.Is(err, mson.ErrUnknownOption) {
+					t.Errorf("decoding into %T panicked with %v, want an invalid tag", v, err)
+				}
+			}()
+
+			_ = mson.Unmarshal([]byte(`{"s":"x"}`), v)
+		}()
+	}
+}
+
+func TestUnmarshalTargets(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	var nilUser *user
+
+	for _, v := range []any{user{}, nilUser, &map[string]int{}, nil} {
+		if err := mson.Unmarshal([]byte(`{"name":"a"}`), v); err == nil {
+			t.Errorf("decoded into %T", v)
+		}
+	}
+
+	if err := mson.UnmarshalGraphQL([]byte(`{"data":{"user":{"name":"a"}}}`), "user", user{}); err == nil {
+		t.Error("decoded a GraphQL response into a struct value")
+	}
+
+	if err := mson.UnmarshalJSONAPI([]byte(`{"data":{"type":"user","id":"1"}}`), nilUser); err == nil {
+		t.Error("decoded a JSON:API document into a nil pointer")
+	}
+}
+
+type greeter interface {
+	Greet() string
+}
+
+func TestSkippedFields(t *testing.T) {
+	type withSkipped struct {
+		Name    string `json:"name"`
+		secret  string
+		Greeter greeter `json:"greeter"`
+	}
+
+	data := []byte(`{"name":"a","secret":"s","greeter":{"x":1}}`)
+
+	var v withSkipped
+	warnings, err := mson.UnmarshalWithWarnings(data, &v, mson.WithSkippedFields(mson.SkipWithWarning))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Name != "a" || v.secret != "" || len(warnings) != 2 {
+		t.Fatalf("got %+v and warnings %v", v, warnings)
+	}
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithSkippedFields(mson.SkipWithError)); !errors.Is(err, mson.ErrSkippedField) {
+		t.Fatalf("got %v, want ErrSkippedField", err)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	var v struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		Zip   string `json:"zip"`
+	}
+
+	var md mson.Metadata
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"name":"a","extra":1,"floor":2}`), &v, mson.WithMetadata(&md)); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"extra", "floor"}; !sameElements(md.Unused, want) {
+		t.Errorf("got unused %v, want %v", md.Unused, want)
+	}
+
+	if want := []string{"email", "zip"}; !sameElements(md.Unset, want) {
+		t.Errorf("got unset %v, want %v", md.Unset, want)
+	}
+}
+
+// sameElements reports whether a and b hold the same strings in any order.
+func sameElements(a, b []string) bool {
+	count := map[string]int{}
+
+	for _, s := range a {
+		count[s]++
+	}
+
+	for _, s := range b {
+		count[s]--
+	}
+
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestUnknownEnumValueError(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &mson.UnknownEnumValueError{Field: "state", Value: "x"})
+
+	var enumErr *mson.UnknownEnumValueError
+
+	if !errors.As(err, &enumErr) || !reflect.DeepEqual(enumErr, &mson.UnknownEnumValueError{Field: "state", Value: "x"}) {
+		t.Fatalf("got %v", err)
+	}
+}
@@ -0,0 +1,545 @@
+package mson
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// A fastPlan decodes structs whose fields are all strings, booleans or
+// numbers without decoding options straight from the document, skipping the
+// intermediate map and the boxing of every value. Documents it cannot handle
+// exactly as the general path would, such as strings with escapes, numbers
+// that overflow, nulls or repeated keys, make it give up so that the general
+// path runs.
+type fastPlan struct {
+	fields []fastField
+}
+
+type fastField struct {
+	name  []byte
+	index []int
+	kind  reflect.Kind
+	bits  int
+}
+
+// fastPlans caches the plan of each struct type, or nil when it has none.
+var fastPlans sync.Map
+
+// resetFastPlans forgets the cached plans after a change to a registry that
+// plans depend on.
+func resetFastPlans() {
+	fastPlans.Range(func(k, _ any) bool {
+		fastPlans.Delete(k)
+		return true
+	})
+}
+
+// plain reports whether the settings leave the decoding of fields without
+// options untouched, which the fast path requires.
+func (s *settings) plain() bool {
+	return s.onWarning == nil && s.tagKeys == nil && s.aliases == nil && s.metadata == nil &&
+		s.typeOptions == nil && !s.emptyAsNull && !s.sanitize && s.resolve == nil && !s.proto3 &&
+		s.merge == MergePolicy{} && s.skipReport == SkipSilently
+}
+
+func fastPlanFor(v any, state *decodeState) *fastPlan {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct || state.jwt || !state.plain() {
+		return nil
+	}
+
+	t := rv.Elem().Type()
+
+	if p, ok := fastPlans.Load(t); ok {
+		return p.(*fastPlan)
+	}
+
+	p := buildFastPlan(t, &state.settings)
+	fastPlans.Store(t, p)
+
+	return p
+}
+
+func buildFastPlan(t reflect.Type, s *settings) *fastPlan {
+	p := &fastPlan{}
+
+	for _, f := range structFields(t, s) {
+		name, options, _ := parseTag(f, s)
+
+		// Embedded structs behind pointers are left to the general path,
+		// which allocates them
+		if _, ok := fieldByIndex(reflect.New(t).Elem(), f.Index, false); !ok {
+			return nil
+		}
+
+		if hasDecodeOptions(fieldOptions(f.Type, options, s)) || !isASCII(name) {
+			return nil
+		}
+
+		// Types decoding themselves are left to assignValue
+		if ptr := reflect.PointerTo(f.Type); ptr.Implements(jsonUnmarshalerType) || ptr.Implements(textUnmarshalerType) {
+			return nil
+		}
+
+		field := fastField{name: []byte(name), index: f.Index, kind: f.Type.Kind()}
+
+		switch field.kind {
+		case reflect.String, reflect.Bool:
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			field.bits = f.Type.Bits()
+		default:
+			return nil
+		}
+
+		p.fields = append(p.fields, field)
+	}
+
+	return p
+}
+
+// decode stores the document in rv, reporting false when it has to be left
+// to the general path. Fields may have been assigned by then.
+func (p *fastPlan) decode(data []byte, rv reflect.Value, s *settings) bool {
+	var seenBuf [64]bool
+	var seen []bool
+
+	if len(p.fields) > len(seenBuf) {
+		seen = make([]bool, len(p.fields))
+	} else {
+		seen = seenBuf[:len(p.fields)]
+	}
+
+	i := skipWhitespace(data, 0)
+
+	if i >= len(data) || data[i] != '{' {
+		return false
+	}
+
+	i = skipWhitespace(data, i+1)
+
+	for first := true; ; first = false {
+		if i < len(data) && data[i] == '}' && first {
+			i++
+			break
+		}
+
+		start, end, escaped, ok := scanString(data, i)
+
+		if !ok || escaped {
+			return false
+		}
+
+		key := data[start:end]
+
+		// Lowercasing may map other characters onto ASCII ones
+		if !s.caseSensitive && !asciiBytes(key) {
+			return false
+		}
+
+		if i = skipWhitespace(data, end+1); i >= len(data) || data[i] != ':' {
+			return false
+		}
+
+		i = skipWhitespace(data, i+1)
+		matched := false
+
+		for j := range p.fields {
+			f := &p.fields[j]
+
+			if !equalKey(key, f.name, s.caseSensitive) {
+				continue
+			}
+
+			// Repeated keys are resolved by the general path
+			if seen[j] {
+				return false
+			}
+
+			next, ok := f.set(rv.FieldByIndex(f.index), data, i, s.interner)
+
+			if !ok {
+				return false
+			}
+
+			seen[j], matched = true, true
+			end = next
+		}
+
+		if !matched {
+			if end, ok = scanValue(data, i, 0); !ok {
+				return false
+			}
+		}
+
+		if i = skipWhitespace(data, end); i >= len(data) {
+			return false
+		}
+
+		if data[i] == '}' {
+			i++
+			break
+		}
+
+		if data[i] != ',' {
+			return false
+		}
+
+		i = skipWhitespace(data, i+1)
+	}
+
+	if skipWhitespace(data, i) != len(data) {
+		return false
+	}
+
+	for j, f := range p.fields {
+		if !seen[j] {
+			field := rv.FieldByIndex(f.index)
+			field.Set(reflect.Zero(field.Type()))
+		}
+	}
+
+	return true
+}
+
+// set decodes the value at data[i:] into field, returning the offset after it.
+func (f *fastField) set(field reflect.Value, data []byte, i int, in *interner) (int, bool) {
+	if i >= len(data) {
+		return 0, false
+	}
+
+	switch f.kind {
+	case reflect.String:
+		start, end, escaped, ok := scanString(data, i)
+
+		if !ok || escaped || !utf8.Valid(data[start:end]) {
+			return 0, false
+		}
+
+		if in != nil {
+			field.SetString(in.internBytes(data[start:end]))
+		} else {
+			field.SetString(string(data[start:end]))
+		}
+		return end + 1, true
+	case reflect.Bool:
+		switch {
+		case hasLiteral(data, i, "true"):
+			field.SetBool(true)
+			return i + 4, true
+		case hasLiteral(data, i, "false"):
+			field.SetBool(false)
+			return i + 5, true
+		}
+
+		return 0, false
+	case reflect.Float32, reflect.Float64:
+		end, integral, ok := scanNumber(data, i)
+
+		if !ok {
+			return 0, false
+		}
+
+		var x float64
+
+		if n, ok := parseInteger(data[i:end]); ok && integral && n > -1<<53 && n < 1<<53 {
+			x = float64(n)
+		} else {
+			var err error
+
+			if x, err = strconv.ParseFloat(string(data[i:end]), 64); err != nil {
+				return 0, false
+			}
+		}
+
+		if f.kind == reflect.Float32 && math.Abs(x) > math.MaxFloat32 {
+			return 0, false
+		}
+
+		field.SetFloat(x)
+		return end, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		end, integral, ok := scanNumber(data, i)
+
+		if !ok || !integral {
+			return 0, false
+		}
+
+		n, ok := parseInteger(data[i:end])
+
+		if !ok || n < 0 || f.bits < 64 && n >= 1<<f.bits {
+			return 0, false
+		}
+
+		field.SetUint(uint64(n))
+		return end, true
+	default:
+		end, integral, ok := scanNumber(data, i)
+
+		if !ok || !integral {
+			return 0, false
+		}
+
+		n, ok := parseInteger(data[i:end])
+
+		if !ok || f.bits < 64 && (n < -1<<(f.bits-1) || n >= 1<<(f.bits-1)) {
+			return 0, false
+		}
+
+		field.SetInt(n)
+		return end, true
+	}
+}
+
+func equalKey(key, name []byte, caseSensitive bool) bool {
+	if len(key) != len(name) {
+		return false
+	}
+
+	for i := range key {
+		a, b := key[i], name[i]
+
+		if !caseSensitive {
+			if 'A' <= a && a <= 'Z' {
+				a += 'a' - 'A'
+			}
+
+			if 'A' <= b && b <= 'Z' {
+				b += 'a' - 'A'
+			}
+		}
+
+		if a != b {
+			return false
+		}
+	}
+
+	return true
+}
+
+func asciiBytes(b []byte) bool {
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+func skipWhitespace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\r' || data[i] == '\n') {
+		i++
+	}
+
+	return i
+}
+
+func hasLiteral(data []byte, i int, literal string) bool {
+	return len(data)-i >= len(literal) && string(data[i:i+len(literal)]) == literal
+}
+
+// scanString returns the bounds of the contents of the string at data[i],
+// whose closing quote is at data[end], and whether it holds escapes.
+func scanString(data []byte, i int) (start, end int, escaped, ok bool) {
+	if i >= len(data) || data[i] != '"' {
+		return 0, 0, false, false
+	}
+
+	for j := i + 1; j < len(data); j++ {
+		switch c := data[j]; {
+		case c == '"':
+			return i + 1, j, escaped, true
+		case c == '\\':
+			if j+1 >= len(data) {
+				return 0, 0, false, false
+			}
+
+			escaped = true
+			j++
+
+			switch data[j] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				if len(data)-j < 5 {
+					return 0, 0, false, false
+				}
+
+				for _, h := range data[j+1 : j+5] {
+					if !('0' <= h && h <= '9' || 'a' <= h && h <= 'f' || 'A' <= h && h <= 'F') {
+						return 0, 0, false, false
+					}
+				}
+
+				j += 4
+			default:
+				return 0, 0, false, false
+			}
+		case c < 0x20:
+			return 0, 0, false, false
+		}
+	}
+
+	return 0, 0, false, false
+}
+
+// scanNumber returns the offset after the JSON number at data[i] and whether
+// it has neither a fraction nor an exponent.
+func scanNumber(data []byte, i int) (end int, integral bool, ok bool) {
+	digits := func(j int) int {
+		for j < len(data) && '0' <= data[j] && data[j] <= '9' {
+			j++
+		}
+
+		return j
+	}
+
+	j := i
+
+	if j < len(data) && data[j] == '-' {
+		j++
+	}
+
+	switch {
+	case j < len(data) && data[j] == '0':
+		j++
+	case j < len(data) && '1' <= data[j] && data[j] <= '9':
+		j = digits(j)
+	default:
+		return 0, false, false
+	}
+
+	integral = true
+
+	if j < len(data) && data[j] == '.' {
+		k := digits(j + 1)
+
+		if k == j+1 {
+			return 0, false, false
+		}
+
+		j, integral = k, false
+	}
+
+	if j < len(data) && (data[j] == 'e' || data[j] == 'E') {
+		j++
+
+		if j < len(data) && (data[j] == '+' || data[j] == '-') {
+			j++
+		}
+
+		k := digits(j)
+
+		if k == j {
+			return 0, false, false
+		}
+
+		j, integral = k, false
+	}
+
+	return j, integral, true
+}
+
+// parseInteger parses a JSON integer literal, reporting false on overflow.
+func parseInteger(b []byte) (int64, bool) {
+	negative := b[0] == '-'
+
+	if negative {
+		b = b[1:]
+	}
+
+	var n uint64
+
+	for _, c := range b {
+		if n > (math.MaxUint64-9)/10 {
+			return 0, false
+		}
+
+		n = n*10 + uint64(c-'0')
+	}
+
+	switch {
+	case negative && n <= 1<<63:
+		return -int64(n), true
+	case !negative && n < 1<<63:
+		return int64(n), true
+	}
+
+	return 0, false
+}
+
+// scanValue returns the offset after the JSON value at data[i], checking its
+// syntax the way the general path would.
+func scanValue(data []byte, i, depth int) (int, bool) {
+	if i >= len(data) || depth > 1000 {
+		return 0, false
+	}
+
+	switch c := data[i]; {
+	case c == '"':
+		_, end, _, ok := scanString(data, i)
+		return end + 1, ok
+	case c == '{' || c == '[':
+		closing := byte('}')
+
+		if c == '[' {
+			closing = ']'
+		}
+
+		j := skipWhitespace(data, i+1)
+
+		if j < len(data) && data[j] == closing {
+			return j + 1, true
+		}
+
+		for {
+			if c == '{' {
+				_, end, _, ok := scanString(data, j)
+
+				if !ok {
+					return 0, false
+				}
+
+				if j = skipWhitespace(data, end+1); j >= len(data) || data[j] != ':' {
+					return 0, false
+				}
+
+				j = skipWhitespace(data, j+1)
+			}
+
+			end, ok := scanValue(data, j, depth+1)
+
+			if !ok {
+				return 0, false
+			}
+
+			if j = skipWhitespace(data, end); j >= len(data) {
+				return 0, false
+			}
+
+			if data[j] == closing {
+				return j + 1, true
+			}
+
+			if data[j] != ',' {
+				return 0, false
+			}
+
+			j = skipWhitespace(data, j+1)
+		}
+	case hasLiteral(data, i, "true"):
+		return i + 4, true
+	case hasLiteral(data, i, "false"):
+		return i + 5, true
+	case hasLiteral(data, i, "null"):
+		return i + 4, true
+	default:
+		end, _, ok := scanNumber(data, i)
+		return end, ok
+	}
+}
@@ -0,0 +1,195 @@
+package mson
+
+import (
+	"reflect"
+	"testing"
+)
+
+type fastRecord struct {
+	ID      int64
+	Count   uint64
+	Small   int8
+	Port    uint16
+	Ratio   float64
+	Weight  float32
+	Name    string
+	Enabled bool
+}
+
+var fastDocuments = []string{
+	`{}`,
+	`{"ID":1,"Count":2,"Small":-3,"Port":8080,"Ratio":0.5,"Weight":1.25,"Name":"a","Enabled":true}`,
+	`{"ID":9007199254740993,"Count":9007199254740993,"Ratio":9007199254740993,"Weight":16777217}`,
+	`{"ID":9223372036854775807,"Count":9223372036854775807}`,
+	`{"ID":-9223372036854775808}`,
+	`{"ID":9223372036854775808}`,
+	`{"Count":9223372036854775809}`,
+	`{"Count":18446744073709551615}`,
+	`{"Count":18446744073709551616}`,
+	`{"Count":-1}`,
+	`{"Small":127,"Port":65535}`,
+	`{"Small":128}`,
+	`{"Port":65536}`,
+	`{"ID":-0,"Ratio":-0}`,
+	`{"ID":1e3}`,
+	`{"ID":1.0}`,
+	`{"ID":1.5}`,
+	`{"Ratio":1e308,"Weight":3.4e38}`,
+	`{"Ratio":1e400}`,
+	`{"Weight":1e39}`,
+	`{"Ratio":0.1,"Weight":0.1}`,
+	`{"Ratio":123456789012345678901234567890}`,
+	`{"Name":"café"}`,
+	`{"Name":"café","name":"tea"}`,
+	`{"id":5,"ID":6}`,
+	`{"ID":1,"ID":2}`,
+	`{"Name":null}`,
+	`{"Name":1}`,
+	`{"Enabled":"true"}`,
+	`{"Other":[1,{"a":null}],"Name":"x"}`,
+	` { "ID" : 7 , "Name" : "spaced" } `,
+	`{"ID":1,}`,
+	`{"ID":01}`,
+	`[]`,
+}
+
+// decodeBothWays decodes data with the fast path and with the general path,
+// reporting whether the fast path handled it.
+func decodeBothWays(tb testing.TB, data []byte) (fast, general fastRecord, handled bool, err error) {
+	state := &decodeState{}
+	plan := fastPlanFor(&fast, state)
+
+	if plan == nil {
+		tb.Fatal("fastRecord has no fast plan")
+	}
+
+	handled = plan.decode(data, reflect.ValueOf(&fast).Elem(), &state.settings)
+
+	var parsed map[string]interface{}
+
+	if err = parseDocument(data, &parsed, &state.settings); err == nil {
+		state.source = data
+		err = decodeStruct(reflect.ValueOf(&general).Elem(), parsed, state)
+	}
+
+	return fast, general, handled, err
+}
+
+func checkFastPath(tb testing.TB, data []byte) {
+	fast, general, handled, err := decodeBothWays(tb, data)
+
+	if !handled {
+		return
+	}
+
+	if err != nil {
+		tb.Fatalf("the fast path decoded %s, which the general path rejects: %v", data, err)
+	}
+
+	if fast != general {
+		tb.Fatalf("decoding %s gave %+v on the fast path and %+v on the general path", data, fast, general)
+	}
+}
+
+func TestFastPathMatchesGeneralPath(t *testing.T) {
+	for _, doc := range fastDocuments {
+		checkFastPath(t, []byte(doc))
+	}
+
+	// Integers beyond the precision of float64 must not push the common
+	// documents off the fast path
+	for _, doc := range fastDocuments[1:5] {
+		if _, _, handled, _ := decodeBothWays(t, []byte(doc)); !handled {
+			t.Errorf("the fast path did not handle %s", doc)
+		}
+	}
+}
+
+func TestFastPathLargeIntegers(t *testing.T) {
+	var v fastRecord
+
+	// The first integer fits the fast path, the second is beyond int64 and
+	// falls back to the general path, which must be exact as well
+	if err := Unmarshal([]byte(`{"ID":9007199254740993,"Count":9223372036854775809}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.ID != 9007199254740993 || v.Count != 1<<63+1 {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestKeysDifferingInCase(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		var v fastRecord
+
+		// The spelling of the field wins over the order of the document
+		if err := Unmarshal([]byte(`{"NAME":"a","Name":"b","name":"c","id":1,"Id":2}`), &v); err != nil {
+			t.Fatal(err)
+		}
+
+		if v.Name != "b" || v.ID != 2 {
+			t.Fatalf("got %+v", v)
+		}
+	}
+}
+
+func FuzzFastPath(f *testing.F) {
+	for _, doc := range fastDocuments {
+		f.Add([]byte(doc))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkFastPath(t, data)
+	})
+}
+
+var fastBenchDocument = []byte(`{"ID":9007199254740993,"Count":42,"Small":-3,"Port":8080,"Ratio":0.5,"Weight":1.25,"Name":"record","Enabled":true}`)
+
+func TestFastPathAllocations(t *testing.T) {
+	var v fastRecord
+
+	fast := testing.AllocsPerRun(100, func() {
+		if err := Unmarshal(fastBenchDocument, &v); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	general := testing.AllocsPerRun(100, func() {
+		if err := UnmarshalWithOptions(fastBenchDocument, &v, WithMetadata(&Metadata{})); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	// The only allocation left is the string of Name
+	if fast > 1 || general <= fast {
+		t.Fatalf("got %v allocations on the fast path and %v on the general path", fast, general)
+	}
+}
+
+func BenchmarkUnmarshalFastPath(b *testing.B) {
+	var v fastRecord
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := Unmarshal(fastBenchDocument, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalGeneralPath(b *testing.B) {
+	var v fastRecord
+
+	// Metadata is a setting the fast path does not support
+	opt := WithMetadata(&Metadata{})
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := UnmarshalWithOptions(fastBenchDocument, &v, opt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package mson
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cleanPath expands a leading ~ and $VAR or ${VAR} references in a file path
+// and cleans the result. With the exists argument the path must name an
+// existing file or directory.
+func cleanPath(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	if s == "" {
+		return s, nil
+	}
+
+	s = os.ExpandEnv(s)
+
+	if s == "~" || strings.HasPrefix(s, "~/") || strings.HasPrefix(s, "~"+string(filepath.Separator)) {
+		home, err := os.UserHomeDir()
+
+		if err != nil {
+			return nil, fmt.Errorf("mson: %w, expansion of field %s failed", err, fieldName)
+		}
+
+		s = home + s[1:]
+	}
+
+	s = filepath.Clean(s)
+
+	if containsOption(parts[1:], "exists") {
+		if _, err := os.Stat(s); err != nil {
+			return nil, fmt.Errorf("mson: %w, field %s must name an existing path", err, fieldName)
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,103 @@
+package mson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Point is a geographic coordinate as produced by the latlng tag option.
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+func parsePoint(value interface{}, lngFirst bool) (Point, error) {
+	var pair []float64
+
+	switch v := value.(type) {
+	case string:
+		for _, s := range strings.Split(v, ",") {
+			f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+
+			if err != nil {
+				return Point{}, fmt.Errorf("invalid coordinate %q", v)
+			}
+
+			pair = append(pair, f)
+		}
+	case []interface{}:
+		for _, e := range v {
+			f, ok := e.(float64)
+
+			if !ok {
+				return Point{}, errors.New("coordinate array must hold numbers")
+			}
+
+			pair = append(pair, f)
+		}
+	case map[string]interface{}:
+		var p Point
+		var hasLat, hasLng bool
+
+		for k, e := range v {
+			f, ok := e.(float64)
+
+			switch strings.ToLower(k) {
+			case "lat", "latitude":
+				p.Lat, hasLat = f, ok
+			case "lng", "lon", "long", "longitude":
+				p.Lng, hasLng = f, ok
+			}
+		}
+
+		if !hasLat || !hasLng {
+			return Point{}, errors.New("coordinate object must hold numeric lat and lng")
+		}
+
+		return p, validatePoint(p)
+	default:
+		return Point{}, errors.New("coordinate must be a string, array or object")
+	}
+
+	// GeoJSON positions may carry an altitude as a third element
+	if len(pair) != 2 && !(len(pair) == 3 && lngFirst) {
+		return Point{}, errors.New("coordinate must have exactly two components")
+	}
+
+	p := Point{Lat: pair[0], Lng: pair[1]}
+
+	if lngFirst {
+		p = Point{Lat: pair[1], Lng: pair[0]}
+	}
+
+	return p, validatePoint(p)
+}
+
+func validatePoint(p Point) error {
+	if p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("latitude %v out of range", p.Lat)
+	}
+
+	if p.Lng < -180 || p.Lng > 180 {
+		return fmt.Errorf("longitude %v out of range", p.Lng)
+	}
+
+	return nil
+}
+
+func convertPoint(value interface{}, parts []string, target reflect.Type, fieldName string) (interface{}, error) {
+	p, err := parsePoint(value, len(parts) > 1 && parts[1] == "lnglat")
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, conversion of field %s to a coordinate failed", err, fieldName)
+	}
+
+	if target.Kind() == reflect.String {
+		return strconv.FormatFloat(p.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(p.Lng, 'f', -1, 64), nil
+	}
+
+	return p, nil
+}
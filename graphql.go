@@ -0,0 +1,89 @@
+package mson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// A GraphQLError is an entry of the errors member of a GraphQL response.
+type GraphQLError struct {
+	Message string
+	Path    []interface{}
+}
+
+func (e *GraphQLError) Error() string {
+	if len(e.Path) == 0 {
+		return "mson: GraphQL error: " + e.Message
+	}
+
+	path := make([]string, len(e.Path))
+
+	for i, p := range e.Path {
+		path[i] = fmt.Sprint(p)
+	}
+
+	return fmt.Sprintf("mson: GraphQL error at %s: %s", strings.Join(path, "."), e.Message)
+}
+
+// UnmarshalGraphQL decodes the node found at the dotted path below the data
+// member of a GraphQL response body into v, a pointer to a struct, or to a
+// slice of structs when the node is a list. The response's errors are
+// returned as *GraphQLError values joined together; since GraphQL responses
+// may carry partial data, the node is still decoded when it is present.
+func UnmarshalGraphQL(body []byte, path string, v any) error {
+	var response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []struct {
+			Message string        `json:"message"`
+			Path    []interface{} `json:"path"`
+		} `json:"errors"`
+	}
+
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("mson: UnmarshalGraphQL requires a non-nil pointer")
+	}
+
+	state := &decodeState{}
+
+	if err := parseDocument(body, &response, &state.settings); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, e := range response.Errors {
+		errs = append(errs, &GraphQLError{Message: e.Message, Path: e.Path})
+	}
+
+	var node interface{} = response.Data
+	found := response.Data != nil
+
+	if path != "" {
+		for _, key := range strings.Split(path, ".") {
+			obj, _ := node.(map[string]interface{})
+
+			if node, found = obj[key]; !found {
+				break
+			}
+		}
+	}
+
+	// A null node is valid data and leaves v zero, a missing one is not
+	if !found {
+		if len(errs) == 0 {
+			return errorf(ErrFieldMissing, "mson: GraphQL response holds no data at %s", path)
+		}
+
+		return errors.Join(errs...)
+	}
+
+	if err := decodeNode(reflect.ValueOf(v).Elem(), node, func(obj map[string]interface{}) map[string]interface{} {
+		return obj
+	}, state); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
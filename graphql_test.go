@@ -0,0 +1,51 @@
+package mson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+type graphQLUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name,pascal"`
+}
+
+func TestUnmarshalGraphQL(t *testing.T) {
+	body := []byte(`{"data":{"viewer":{"user":{"id":"1","name":"ada_lovelace"},"friends":[{"id":"2","name":"charles"},{"id":"3","name":"mary"}]}}}`)
+
+	var user graphQLUser
+
+	if err := mson.UnmarshalGraphQL(body, "viewer.user", &user); err != nil || user != (graphQLUser{"1", "AdaLovelace"}) {
+		t.Fatalf("got %+v, %v", user, err)
+	}
+
+	var friends []graphQLUser
+
+	if err := mson.UnmarshalGraphQL(body, "viewer.friends", &friends); err != nil || len(friends) != 2 || friends[0].Name != "Charles" {
+		t.Fatalf("got %+v, %v", friends, err)
+	}
+
+	if err := mson.UnmarshalGraphQL(body, "viewer.enemies", &friends); !errors.Is(err, mson.ErrFieldMissing) {
+		t.Fatalf("got %v, want ErrFieldMissing", err)
+	}
+}
+
+func TestUnmarshalGraphQLPartialData(t *testing.T) {
+	body := []byte(`{"data":{"user":{"id":"1","name":"ada"}},"errors":[{"message":"friends unavailable","path":["user","friends",0]}]}`)
+
+	var user graphQLUser
+	err := mson.UnmarshalGraphQL(body, "user", &user)
+
+	var gqlErr *mson.GraphQLError
+
+	if !errors.As(err, &gqlErr) || gqlErr.Error() != "mson: GraphQL error at user.friends.0: friends unavailable" {
+		t.Fatalf("got %v, want a GraphQLError", err)
+	}
+
+	// The data present alongside the errors is still decoded
+	if user.ID != "1" || user.Name != "Ada" {
+		t.Fatalf("got %+v", user)
+	}
+}
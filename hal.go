@@ -0,0 +1,125 @@
+package mson
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+)
+
+var urlType = reflect.TypeOf(url.URL{})
+
+// extractLink returns the href of the rel relation of a HAL _links object, or
+// the self links of the resources embedded as rel in an _embedded object.
+// Slice fields receive every link of the relation and other fields the first;
+// the result is false when the relation is absent.
+func extractLink(value interface{}, parts []string, target reflect.Type, fieldName string) (interface{}, bool, error) {
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'link' requires a relation argument"))
+	}
+
+	section, ok := value.(map[string]interface{})
+
+	if !ok {
+		return nil, false, errorf(ErrTypeMismatch, "mson: field %s is not an object", fieldName)
+	}
+
+	relation, ok := section[unquote(parts[1])]
+
+	if !ok || relation == nil {
+		return nil, false, nil
+	}
+
+	items, ok := relation.([]interface{})
+
+	if !ok {
+		items = []interface{}{relation}
+	}
+
+	var hrefs []string
+
+	for _, item := range items {
+		href, ok := linkHref(item)
+
+		if !ok {
+			return nil, false, errorf(ErrTypeMismatch, "mson: field %s holds a %s relation without href", fieldName, parts[1])
+		}
+
+		hrefs = append(hrefs, href)
+	}
+
+	if len(hrefs) == 0 {
+		return nil, false, nil
+	}
+
+	if target.Kind() != reflect.Slice {
+		v, err := convertLink(hrefs[0], target, fieldName)
+		return v, err == nil, err
+	}
+
+	links := reflect.MakeSlice(target, 0, len(hrefs))
+
+	for _, href := range hrefs {
+		v, err := convertLink(href, derefType(target.Elem()), fieldName)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		rv := reflect.ValueOf(v)
+
+		if target.Elem().Kind() == reflect.Ptr {
+			ptr := reflect.New(rv.Type())
+			ptr.Elem().Set(rv)
+			rv = ptr
+		}
+
+		links = reflect.Append(links, rv.Convert(target.Elem()))
+	}
+
+	return links.Interface(), true, nil
+}
+
+// linkHref returns the href of a link object, or the self link of an
+// embedded resource.
+func linkHref(v interface{}) (string, bool) {
+	obj, ok := v.(map[string]interface{})
+
+	if !ok {
+		return "", false
+	}
+
+	if href, ok := obj["href"].(string); ok {
+		return href, true
+	}
+
+	links, ok := obj["_links"].(map[string]interface{})
+
+	if !ok {
+		return "", false
+	}
+
+	self := links["self"]
+
+	if list, ok := self.([]interface{}); ok && len(list) > 0 {
+		self = list[0]
+	}
+
+	return linkHref(self)
+}
+
+func convertLink(href string, target reflect.Type, fieldName string) (interface{}, error) {
+	switch {
+	case target == urlType:
+		u, err := url.Parse(href)
+
+		if err != nil {
+			return nil, fmt.Errorf("mson: %w, parsing of field %s failed", err, fieldName)
+		}
+
+		return *u, nil
+	case target.Kind() == reflect.String, target.Kind() == reflect.Interface:
+		return href, nil
+	default:
+		return nil, errorf(ErrTypeMismatch, "mson: cannot store link of field %s in type %s", fieldName, target)
+	}
+}
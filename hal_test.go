@@ -0,0 +1,61 @@
+package mson_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+const halOrder = `{
+	"_links": {"self": {"href": "/orders/1"}, "next": [{"href": "/orders/2?page=2"}, {"href": "/orders/3"}]},
+	"_embedded": {"items": [{"_links": {"self": {"href": "/items/a"}}}, {"_links": {"self": [{"href": "/items/b"}]}}]}
+}`
+
+func TestLinkOption(t *testing.T) {
+	// As with encoding/json, a key is decoded into a single field
+	var v struct {
+		Next  *url.URL `json:"_links,link,next"`
+		Items []string `json:"_embedded,link,items"`
+	}
+
+	if err := mson.Unmarshal([]byte(halOrder), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Next.Path != "/orders/2" || v.Next.Query().Get("page") != "2" {
+		t.Errorf("got next %v", v.Next)
+	}
+
+	if len(v.Items) != 2 || v.Items[0] != "/items/a" || v.Items[1] != "/items/b" {
+		t.Errorf("got items %v", v.Items)
+	}
+
+	var urls struct {
+		Self  string     `json:"_links,link,self"`
+		Items []*url.URL `json:"_embedded,link,items"`
+	}
+
+	if err := mson.Unmarshal([]byte(halOrder), &urls); err != nil {
+		t.Fatal(err)
+	}
+
+	if urls.Self != "/orders/1" || len(urls.Items) != 2 || urls.Items[1].Path != "/items/b" {
+		t.Errorf("got %+v", urls)
+	}
+}
+
+func TestLinkOptionMissingRelation(t *testing.T) {
+	v := struct {
+		Prev string `json:"_links,link,prev"`
+	}{Prev: "stale"}
+
+	// An absent relation leaves the field zero, like a missing key
+	if err := mson.Unmarshal([]byte(halOrder), &v); err != nil || v.Prev != "" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"_links":{"prev":{"title":"no href"}}}`), &v); err == nil {
+		t.Fatal("decoded a link without href")
+	}
+}
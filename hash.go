@@ -0,0 +1,97 @@
+package mson
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// digest returns the hex-encoded hash of data under the given algorithm.
+func digest(algorithm string, data []byte) (string, error) {
+	var h hash.Hash
+
+	switch algorithm {
+	case "md5":
+		h = md5.New()
+	case "sha1":
+		h = sha1.New()
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return "", fmt.Errorf("unknown hash algorithm %s", algorithm)
+	}
+
+	h.Write(data)
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashedBytes returns the bytes the hash option digests for the value of the
+// sibling key name: the contents of a string, and for any other value its
+// bytes as written in the source document, so that keys keep their order and
+// numbers their spelling. Values decoded without a document at hand, or out
+// of reach of locateValue, are hashed by their compact JSON encoding.
+func hashedBytes(name string, value interface{}, state *decodeState) ([]byte, error) {
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+
+	if state.source != nil && len(state.path) > 0 {
+		path := append(state.path[:len(state.path)-1:len(state.path)-1], name)
+
+		if offset, exact := locateValue(state.source, path, &state.settings); exact {
+			var raw json.RawMessage
+
+			if err := json.NewDecoder(bytes.NewReader(state.source[offset:])).Decode(&raw); err == nil {
+				return raw, nil
+			}
+		}
+	}
+
+	return json.Marshal(value)
+}
+
+func verifyHash(value interface{}, parts []string, fieldName string, state *decodeState) error {
+	if len(parts) < 3 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'hash' requires an algorithm and the name of the hashed field"))
+	}
+
+	checksum, ok := value.(string)
+
+	if !ok {
+		return errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	referenced, ok := state.lookup(parts[2])
+
+	if !ok {
+		return errorf(ErrFieldMissing, "mson: field %s references missing field %s", fieldName, parts[2])
+	}
+
+	data, err := hashedBytes(parts[2], referenced, state)
+
+	if err != nil {
+		return fmt.Errorf("mson: %w, verification of field %s failed", err, fieldName)
+	}
+
+	sum, err := digest(parts[1], data)
+
+	if err != nil {
+		return fmt.Errorf("mson: %w, verification of field %s failed", err, fieldName)
+	}
+
+	if !strings.EqualFold(sum, checksum) {
+		return errorf(ErrChecksumMismatch, "mson: checksum in field %s does not match the %s hash of field %s", fieldName, parts[1], parts[2])
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package mson_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+func TestHashOption(t *testing.T) {
+	type upload struct {
+		Body string `json:"body"`
+		Sum  string `json:"sum,hash,sha256,body"`
+	}
+
+	h := sha256.Sum256([]byte("hello"))
+	sum := hex.EncodeToString(h[:])
+
+	var v upload
+
+	if err := mson.Unmarshal([]byte(`{"body":"hello","sum":"`+sum+`"}`), &v); err != nil || v.Body != "hello" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"body":"hello!","sum":"`+sum+`"}`), &v); err == nil {
+		t.Fatal("accepted a digest of other content")
+	}
+}
+
+func TestHashOptionRawValues(t *testing.T) {
+	type upload struct {
+		Body     string                 `json:"body"`
+		Sum      string                 `json:"sum,hash,sha256,body"`
+		Meta     map[string]interface{} `json:"meta"`
+		MetaHash string                 `json:"meta_hash,hash,sha256,meta"`
+	}
+
+	sum := func(s string) string {
+		h := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(h[:])
+	}
+
+	// The object is hashed as written, keeping the order of its keys
+	meta := `{"z":1,"a":1.50}`
+	data := []byte(`{"body":"hello","sum":"` + sum("hello") + `","meta":` + meta + `,"meta_hash":"` + sum(meta) + `"}`)
+
+	var v upload
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Body != "hello" || v.Meta["z"] != 1.0 {
+		t.Fatalf("got %+v", v)
+	}
+
+	data = []byte(`{"body":"hello!","sum":"` + sum("hello") + `"}`)
+
+	if err := mson.Unmarshal(data, &v); !errors.Is(err, mson.ErrChecksumMismatch) {
+		t.Fatalf("got %v, want ErrChecksumMismatch", err)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"sum":"00"}`), &v); !errors.Is(err, mson.ErrFieldMissing) {
+		t.Fatalf("got %v, want ErrFieldMissing", err)
+	}
+}
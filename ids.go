@@ -0,0 +1,159 @@
+package mson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	twitterEpoch = 1288834974657
+	discordEpoch = 1420070400000
+	ksuidEpoch   = 1400000000
+)
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+func parseSnowflake(value interface{}, epoch string) (uint64, time.Time, error) {
+	var id uint64
+
+	switch v := value.(type) {
+	case string:
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		id = n
+	case float64:
+		// Snowflakes above 2^53 cannot be represented exactly as JSON numbers
+		if v < 0 || v > 1<<53 || v != math.Trunc(v) {
+			return 0, time.Time{}, errorf(ErrOverflow, "snowflake is not exactly representable, send it as a string")
+		}
+		id = uint64(v)
+	default:
+		return 0, time.Time{}, errors.New("snowflake must be a string or a number")
+	}
+
+	var offset int64
+
+	switch epoch {
+	case "", "twitter":
+		offset = twitterEpoch
+	case "discord":
+		offset = discordEpoch
+	default:
+		n, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return 0, time.Time{}, fmt.Errorf("invalid snowflake epoch %s", epoch)
+		}
+		offset = n
+	}
+
+	return id, time.UnixMilli(int64(id>>22) + offset), nil
+}
+
+func parseULID(value interface{}) (string, time.Time, error) {
+	s, ok := value.(string)
+
+	if !ok || len(s) != 26 {
+		return "", time.Time{}, errors.New("ULID must be a 26 character string")
+	}
+
+	s = strings.ToUpper(s)
+
+	// The first character may only encode 3 bits, anything larger overflows
+	if s[0] > '7' {
+		return "", time.Time{}, errorf(ErrOverflow, "ULID timestamp overflows 48 bits")
+	}
+
+	var ms int64
+
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(crockford, s[i])
+
+		if d < 0 {
+			return "", time.Time{}, fmt.Errorf("invalid ULID character %q", s[i])
+		}
+
+		if i < 10 {
+			ms = ms<<5 | int64(d)
+		}
+	}
+
+	return s, time.UnixMilli(ms), nil
+}
+
+func parseKSUID(value interface{}) (string, time.Time, error) {
+	s, ok := value.(string)
+
+	if !ok || len(s) != 27 {
+		return "", time.Time{}, errors.New("KSUID must be a 27 character string")
+	}
+
+	n := new(big.Int)
+	base := big.NewInt(62)
+
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(base62, s[i])
+
+		if d < 0 {
+			return "", time.Time{}, fmt.Errorf("invalid KSUID character %q", s[i])
+		}
+
+		n.Mul(n, base).Add(n, big.NewInt(int64(d)))
+	}
+
+	if n.BitLen() > 160 {
+		return "", time.Time{}, errorf(ErrOverflow, "KSUID overflows 20 bytes")
+	}
+
+	raw := n.FillBytes(make([]byte, 20))
+	seconds := int64(raw[0])<<24 | int64(raw[1])<<16 | int64(raw[2])<<8 | int64(raw[3])
+
+	return s, time.Unix(seconds+ksuidEpoch, 0), nil
+}
+
+func parseID(value interface{}, parts []string, target reflect.Type, fieldName string) (interface{}, error) {
+	var id interface{}
+	var t time.Time
+	var err error
+
+	switch parts[0] {
+	case "snowflake":
+		var epoch string
+
+		if len(parts) > 1 {
+			epoch = parts[1]
+		}
+
+		var n uint64
+		n, t, err = parseSnowflake(value, epoch)
+		id = n
+	case "ulid":
+		id, t, err = parseULID(value)
+	case "ksuid":
+		id, t, err = parseKSUID(value)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, parsing of field %s as %s failed", err, fieldName, parts[0])
+	}
+
+	switch {
+	case target == timeType:
+		return t, nil
+	case target.Kind() == reflect.String:
+		return fmt.Sprint(id), nil
+	case reflect.TypeOf(id).ConvertibleTo(target):
+		return reflect.ValueOf(id).Convert(target).Interface(), nil
+	}
+
+	return id, nil
+}
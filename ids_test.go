@@ -0,0 +1,96 @@
+package mson_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+func TestIdentifierOptions(t *testing.T) {
+	type ids struct {
+		Snowflake uint64    `json:"snowflake,snowflake"`
+		Created   time.Time `json:"created,snowflake"`
+		Discord   time.Time `json:"discord,snowflake,discord"`
+		Text      string    `json:"text,snowflake"`
+		ULID      string    `json:"ulid,ulid"`
+		ULIDTime  time.Time `json:"ulid_time,ulid"`
+		KSUIDTime time.Time `json:"ksuid_time,ksuid"`
+	}
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	twitter := uint64(at.UnixMilli()-1288834974657)<<22 | 5
+	discord := uint64(at.UnixMilli()-1420070400000)<<22 | 5
+	id := strconv.FormatUint(twitter, 10)
+
+	data := []byte(`{"snowflake":"` + id + `","created":"` + id + `","discord":"` + strconv.FormatUint(discord, 10) + `","text":"` + id + `",
+		"ulid":"01ARZ3NDEKTSV4RRFFQ69G5FAV","ulid_time":"01ARZ3NDEKTSV4RRFFQ69G5FAV","ksuid_time":"0ujtsYcgvSTl8PAuAdqWYSMnLOv"}`)
+
+	var v ids
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Snowflake != twitter || !v.Created.Equal(at) || !v.Discord.Equal(at) || v.Text != id {
+		t.Errorf("got snowflakes %d, %v, %v and %q", v.Snowflake, v.Created, v.Discord, v.Text)
+	}
+
+	if v.ULID != "01ARZ3NDEKTSV4RRFFQ69G5FAV" || !v.ULIDTime.Equal(time.UnixMilli(1469922850259)) {
+		t.Errorf("got ULID %q at %v", v.ULID, v.ULIDTime)
+	}
+
+	if !v.KSUIDTime.Equal(time.Unix(1507608047, 0)) {
+		t.Errorf("got KSUID time %v", v.KSUIDTime)
+	}
+
+	for _, data := range []string{`{"snowflake":1e300}`, `{"snowflake":"abc"}`, `{"ulid":"too-short"}`, `{"ksuid_time":"!"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("decoded invalid identifier %s", data)
+		}
+	}
+}
+
+func TestSemverOption(t *testing.T) {
+	type release struct {
+		Version    mson.Version `json:"version,semver"`
+		Text       string       `json:"text,semver"`
+		Compatible bool         `json:"compatible,semver,\"^1.2||>=2.0.0&&<2.1\""`
+		Unstable   bool         `json:"unstable,semver!,\">=1.0.0\""`
+	}
+
+	var v release
+
+	if err := mson.Unmarshal([]byte(`{"version":"v1.4.0-rc.1+build.5","text":"2","compatible":"1.9.3","unstable":"0.9.0"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Version.String() != "1.4.0-rc.1+build.5" || v.Text != "2.0.0" || !v.Compatible || !v.Unstable {
+		t.Fatalf("got %+v", v)
+	}
+
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0+other", 0},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-rc.11", "1.0.0-rc.2", 1},
+		{"2.0", "1.99.99", 1},
+	}
+
+	for _, tt := range tests {
+		a, _ := mson.ParseVersion(tt.a)
+		b, _ := mson.ParseVersion(tt.b)
+
+		if got := a.Compare(b); got != tt.want {
+			t.Errorf("comparing %s to %s got %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if _, err := mson.ParseVersion("1.x"); err == nil {
+		t.Error("parsed an invalid version")
+	}
+}
@@ -0,0 +1,93 @@
+package mson
+
+import "sync"
+
+const (
+	// Longer strings are rarely repeated verbatim and are not interned.
+	maxInternedLength = 64
+
+	// Once this many strings are interned, new ones are no longer added.
+	maxInternedStrings = 1 << 16
+)
+
+// An interner hands out a single copy of equal strings, so that the values
+// repeated across many decoded records share their memory.
+type interner struct {
+	mu      sync.Mutex
+	strings map[string]string
+}
+
+// WithInterning stores a single copy of the short strings that repeat across
+// the decoded values, such as map keys and enum-like fields, to cut the memory
+// held by large decodes. The copies are shared by every decode of a Decoder or
+// Codec created with this option.
+func WithInterning() Option {
+	return func(s *settings) {
+		s.interner = &interner{strings: map[string]string{}}
+	}
+}
+
+func (in *interner) intern(s string) string {
+	if len(s) > maxInternedLength {
+		return s
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if interned, ok := in.strings[s]; ok {
+		return interned
+	}
+
+	if len(in.strings) < maxInternedStrings {
+		in.strings[s] = s
+	}
+
+	return s
+}
+
+// internBytes is like intern, but only copies b when it is not yet interned.
+func (in *interner) internBytes(b []byte) string {
+	if len(b) > maxInternedLength {
+		return string(b)
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if interned, ok := in.strings[string(b)]; ok {
+		return interned
+	}
+
+	s := string(b)
+
+	if len(in.strings) < maxInternedStrings {
+		in.strings[s] = s
+	}
+
+	return s
+}
+
+// value interns the strings of a decoded value, including the keys and
+// elements of the maps and arrays it holds.
+func (in *interner) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return in.intern(v)
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = in.value(elem)
+		}
+	case []string:
+		for i, elem := range v {
+			v[i] = in.intern(elem)
+		}
+	case map[string]interface{}:
+		for k, elem := range v {
+			// Assigning under an equal key replaces the stored key
+			v[in.intern(k)] = in.value(elem)
+		}
+	}
+
+	return v
+}
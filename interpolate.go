@@ -0,0 +1,83 @@
+package mson
+
+import (
+	"fmt"
+	"strings"
+)
+
+// interpolateString replaces the ${ref} placeholders of s with what resolve
+// returns for ref. $${ is kept as a literal ${.
+func interpolateString(s string, resolve func(ref string) (string, error)) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var b strings.Builder
+
+	for {
+		i := strings.Index(s, "${")
+
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+
+		if end < 0 {
+			return "", fmt.Errorf("unterminated placeholder in %q", s)
+		}
+
+		resolved, err := resolve(s[i+2 : i+end])
+
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(s[:i])
+		b.WriteString(resolved)
+		s = s[i+end+1:]
+	}
+}
+
+// interpolateValue applies interpolateString to every string held by value.
+func interpolateValue(value interface{}, resolve func(ref string) (string, error), fieldName string) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		s, err := interpolateString(v, resolve)
+
+		if err != nil {
+			return nil, fmt.Errorf("mson: %w, interpolation of field %s failed", err, fieldName)
+		}
+
+		return s, nil
+	case []interface{}:
+		for i, e := range v {
+			n, err := interpolateValue(e, resolve, fieldName)
+
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = n
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			n, err := interpolateValue(e, resolve, fieldName)
+
+			if err != nil {
+				return nil, err
+			}
+
+			v[k] = n
+		}
+	}
+
+	return value, nil
+}
@@ -0,0 +1,160 @@
+package mson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval is a time span as produced by the interval tag option.
+type Interval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Duration returns the length of the interval.
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+func (i Interval) String() string {
+	return i.Start.Format(time.RFC3339Nano) + "/" + i.End.Format(time.RFC3339Nano)
+}
+
+type isoDuration struct {
+	years, months, days int
+	clock               time.Duration
+}
+
+func parseISODuration(s string) (isoDuration, error) {
+	var d isoDuration
+
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return d, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+
+	inTime := false
+	number := ""
+
+	for _, r := range s[1:] {
+		switch {
+		case r == 'T':
+			if inTime || number != "" {
+				return d, fmt.Errorf("invalid ISO 8601 duration %q", s)
+			}
+			inTime = true
+			continue
+		case r >= '0' && r <= '9', r == '.', r == ',':
+			number += string(r)
+			continue
+		}
+
+		n, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+
+		if err != nil {
+			return d, fmt.Errorf("invalid ISO 8601 duration %q", s)
+		}
+
+		number = ""
+
+		switch {
+		case r == 'Y' && !inTime:
+			d.years = int(n)
+		case r == 'M' && !inTime:
+			d.months = int(n)
+		case r == 'W' && !inTime:
+			d.days += int(n) * 7
+		case r == 'D' && !inTime:
+			d.days += int(n)
+		case r == 'H' && inTime:
+			d.clock += time.Duration(n * float64(time.Hour))
+		case r == 'M' && inTime:
+			d.clock += time.Duration(n * float64(time.Minute))
+		case r == 'S' && inTime:
+			d.clock += time.Duration(n * float64(time.Second))
+		default:
+			return d, fmt.Errorf("invalid ISO 8601 duration %q", s)
+		}
+	}
+
+	if number != "" {
+		return d, fmt.Errorf("invalid ISO 8601 duration %q", s)
+	}
+
+	return d, nil
+}
+
+func (d isoDuration) addTo(t time.Time, sign int) time.Time {
+	return t.AddDate(sign*d.years, sign*d.months, sign*d.days).Add(time.Duration(sign) * d.clock)
+}
+
+func parseInterval(s string) (Interval, error) {
+	first, second, ok := strings.Cut(strings.TrimSpace(s), "/")
+
+	if !ok {
+		return Interval{}, fmt.Errorf("interval %q must have the form <start>/<end>", s)
+	}
+
+	var i Interval
+
+	switch {
+	case strings.HasPrefix(first, "P") && strings.HasPrefix(second, "P"):
+		return Interval{}, errors.New("interval cannot be made of two durations")
+	case strings.HasPrefix(first, "P"):
+		d, err := parseISODuration(first)
+		if err != nil {
+			return Interval{}, err
+		}
+
+		if i.End, err = time.Parse(time.RFC3339, second); err != nil {
+			return Interval{}, err
+		}
+
+		i.Start = d.addTo(i.End, -1)
+	case strings.HasPrefix(second, "P"):
+		d, err := parseISODuration(second)
+		if err != nil {
+			return Interval{}, err
+		}
+
+		if i.Start, err = time.Parse(time.RFC3339, first); err != nil {
+			return Interval{}, err
+		}
+
+		i.End = d.addTo(i.Start, 1)
+	default:
+		var err error
+
+		if i.Start, err = time.Parse(time.RFC3339, first); err != nil {
+			return Interval{}, err
+		}
+
+		if i.End, err = time.Parse(time.RFC3339, second); err != nil {
+			return Interval{}, err
+		}
+	}
+
+	if i.End.Before(i.Start) {
+		return Interval{}, fmt.Errorf("interval %q ends before it starts", s)
+	}
+
+	return i, nil
+}
+
+func convertInterval(value interface{}, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	i, err := parseInterval(s)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, conversion of field %s to an interval failed", err, fieldName)
+	}
+
+	return i, nil
+}
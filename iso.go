@@ -0,0 +1,109 @@
+package mson
+
+import (
+	"fmt"
+	"strings"
+)
+
+type isoCountry struct {
+	alpha2  string
+	alpha3  string
+	numeric string
+	name    string
+	aliases []string
+}
+
+type isoCurrency struct {
+	code    string
+	numeric string
+	name    string
+}
+
+var (
+	countryIndex  = map[string]*isoCountry{}
+	currencyIndex = map[string]*isoCurrency{}
+)
+
+func init() {
+	for i := range countries {
+		c := &countries[i]
+
+		for _, key := range append([]string{c.alpha2, c.alpha3, c.numeric, c.name}, c.aliases...) {
+			countryIndex[strings.ToLower(key)] = c
+		}
+	}
+
+	for i := range currencies {
+		c := &currencies[i]
+
+		for _, key := range []string{c.code, c.numeric, c.name} {
+			if key != "" {
+				currencyIndex[strings.ToLower(key)] = c
+			}
+		}
+	}
+}
+
+func normalizeCountry(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	c, ok := countryIndex[strings.ToLower(strings.TrimSpace(s))]
+
+	if !ok {
+		return nil, fmt.Errorf("mson: field %s holds unknown country %q", fieldName, s)
+	}
+
+	format := "alpha2"
+
+	if len(parts) > 1 {
+		format = parts[1]
+	}
+
+	switch format {
+	case "alpha2":
+		return c.alpha2, nil
+	case "alpha3":
+		return c.alpha3, nil
+	case "numeric":
+		return c.numeric, nil
+	case "name":
+		return c.name, nil
+	}
+
+	panic(errorf(ErrInvalidTag, "mson: tag option 'country' received invalid argument %s", format))
+}
+
+func normalizeCurrency(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	c, ok := currencyIndex[strings.ToLower(strings.TrimSpace(s))]
+
+	if !ok {
+		return nil, fmt.Errorf("mson: field %s holds unknown currency %q", fieldName, s)
+	}
+
+	format := "code"
+
+	if len(parts) > 1 {
+		format = parts[1]
+	}
+
+	switch format {
+	case "code":
+		return c.code, nil
+	case "numeric":
+		return c.numeric, nil
+	case "name":
+		return c.name, nil
+	}
+
+	panic(errorf(ErrInvalidTag, "mson: tag option 'currency' received invalid argument %s", format))
+}
@@ -0,0 +1,439 @@
+package mson
+
+// Tables generated from the Debian iso-codes ISO 3166-1 and ISO 4217 data.
+
+var countries = []isoCountry{
+	{"AD", "AND", "020", "Andorra", []string{"Principality of Andorra"}},
+	{"AE", "ARE", "784", "United Arab Emirates", nil},
+	{"AF", "AFG", "004", "Afghanistan", []string{"Islamic Republic of Afghanistan"}},
+	{"AG", "ATG", "028", "Antigua and Barbuda", nil},
+	{"AI", "AIA", "660", "Anguilla", nil},
+	{"AL", "ALB", "008", "Albania", []string{"Republic of Albania"}},
+	{"AM", "ARM", "051", "Armenia", []string{"Republic of Armenia"}},
+	{"AO", "AGO", "024", "Angola", []string{"Republic of Angola"}},
+	{"AQ", "ATA", "010", "Antarctica", nil},
+	{"AR", "ARG", "032", "Argentina", []string{"Argentine Republic"}},
+	{"AS", "ASM", "016", "American Samoa", nil},
+	{"AT", "AUT", "040", "Austria", []string{"Republic of Austria"}},
+	{"AU", "AUS", "036", "Australia", nil},
+	{"AW", "ABW", "533", "Aruba", nil},
+	{"AX", "ALA", "248", "Åland Islands", nil},
+	{"AZ", "AZE", "031", "Azerbaijan", []string{"Republic of Azerbaijan"}},
+	{"BA", "BIH", "070", "Bosnia and Herzegovina", []string{"Republic of Bosnia and Herzegovina"}},
+	{"BB", "BRB", "052", "Barbados", nil},
+	{"BD", "BGD", "050", "Bangladesh", []string{"People's Republic of Bangladesh"}},
+	{"BE", "BEL", "056", "Belgium", []string{"Kingdom of Belgium"}},
+	{"BF", "BFA", "854", "Burkina Faso", nil},
+	{"BG", "BGR", "100", "Bulgaria", []string{"Republic of Bulgaria"}},
+	{"BH", "BHR", "048", "Bahrain", []string{"Kingdom of Bahrain"}},
+	{"BI", "BDI", "108", "Burundi", []string{"Republic of Burundi"}},
+	{"BJ", "BEN", "204", "Benin", []string{"Republic of Benin"}},
+	{"BL", "BLM", "652", "Saint Barthélemy", nil},
+	{"BM", "BMU", "060", "Bermuda", nil},
+	{"BN", "BRN", "096", "Brunei Darussalam", nil},
+	{"BO", "BOL", "068", "Bolivia", []string{"Bolivia, Plurinational State of", "Plurinational State of Bolivia"}},
+	{"BQ", "BES", "535", "Bonaire, Sint Eustatius and Saba", nil},
+	{"BR", "BRA", "076", "Brazil", []string{"Federative Republic of Brazil"}},
+	{"BS", "BHS", "044", "Bahamas", []string{"Commonwealth of the Bahamas"}},
+	{"BT", "BTN", "064", "Bhutan", []string{"Kingdom of Bhutan"}},
+	{"BV", "BVT", "074", "Bouvet Island", nil},
+	{"BW", "BWA", "072", "Botswana", []string{"Republic of Botswana"}},
+	{"BY", "BLR", "112", "Belarus", []string{"Republic of Belarus"}},
+	{"BZ", "BLZ", "084", "Belize", nil},
+	{"CA", "CAN", "124", "Canada", nil},
+	{"CC", "CCK", "166", "Cocos (Keeling) Islands", nil},
+	{"CD", "COD", "180", "Congo, The Democratic Republic of the", nil},
+	{"CF", "CAF", "140", "Central African Republic", nil},
+	{"CG", "COG", "178", "Congo", []string{"Republic of the Congo"}},
+	{"CH", "CHE", "756", "Switzerland", []string{"Swiss Confederation"}},
+	{"CI", "CIV", "384", "Côte d'Ivoire", []string{"Republic of Côte d'Ivoire"}},
+	{"CK", "COK", "184", "Cook Islands", nil},
+	{"CL", "CHL", "152", "Chile", []string{"Republic of Chile"}},
+	{"CM", "CMR", "120", "Cameroon", []string{"Republic of Cameroon"}},
+	{"CN", "CHN", "156", "China", []string{"People's Republic of China"}},
+	{"CO", "COL", "170", "Colombia", []string{"Republic of Colombia"}},
+	{"CR", "CRI", "188", "Costa Rica", []string{"Republic of Costa Rica"}},
+	{"CU", "CUB", "192", "Cuba", []string{"Republic of Cuba"}},
+	{"CV", "CPV", "132", "Cabo Verde", []string{"Republic of Cabo Verde"}},
+	{"CW", "CUW", "531", "Curaçao", nil},
+	{"CX", "CXR", "162", "Christmas Island", nil},
+	{"CY", "CYP", "196", "Cyprus", []string{"Republic of Cyprus"}},
+	{"CZ", "CZE", "203", "Czechia", []string{"Czech Republic"}},
+	{"DE", "DEU", "276", "Germany", []string{"Federal Republic of Germany"}},
+	{"DJ", "DJI", "262", "Djibouti", []string{"Republic of Djibouti"}},
+	{"DK", "DNK", "208", "Denmark", []string{"Kingdom of Denmark"}},
+	{"DM", "DMA", "212", "Dominica", []string{"Commonwealth of Dominica"}},
+	{"DO", "DOM", "214", "Dominican Republic", nil},
+	{"DZ", "DZA", "012", "Algeria", []string{"People's Democratic Republic of Algeria"}},
+	{"EC", "ECU", "218", "Ecuador", []string{"Republic of Ecuador"}},
+	{"EE", "EST", "233", "Estonia", []string{"Republic of Estonia"}},
+	{"EG", "EGY", "818", "Egypt", []string{"Arab Republic of Egypt"}},
+	{"EH", "ESH", "732", "Western Sahara", nil},
+	{"ER", "ERI", "232", "Eritrea", []string{"the State of Eritrea"}},
+	{"ES", "ESP", "724", "Spain", []string{"Kingdom of Spain"}},
+	{"ET", "ETH", "231", "Ethiopia", []string{"Federal Democratic Republic of Ethiopia"}},
+	{"FI", "FIN", "246", "Finland", []string{"Republic of Finland"}},
+	{"FJ", "FJI", "242", "Fiji", []string{"Republic of Fiji"}},
+	{"FK", "FLK", "238", "Falkland Islands (Malvinas)", nil},
+	{"FM", "FSM", "583", "Micronesia, Federated States of", []string{"Federated States of Micronesia"}},
+	{"FO", "FRO", "234", "Faroe Islands", nil},
+	{"FR", "FRA", "250", "France", []string{"French Republic"}},
+	{"GA", "GAB", "266", "Gabon", []string{"Gabonese Republic"}},
+	{"GB", "GBR", "826", "United Kingdom", []string{"United Kingdom of Great Britain and Northern Ireland"}},
+	{"GD", "GRD", "308", "Grenada", nil},
+	{"GE", "GEO", "268", "Georgia", nil},
+	{"GF", "GUF", "254", "French Guiana", nil},
+	{"GG", "GGY", "831", "Guernsey", nil},
+	{"GH", "GHA", "288", "Ghana", []string{"Republic of Ghana"}},
+	{"GI", "GIB", "292", "Gibraltar", nil},
+	{"GL", "GRL", "304", "Greenland", nil},
+	{"GM", "GMB", "270", "Gambia", []string{"Republic of the Gambia"}},
+	{"GN", "GIN", "324", "Guinea", []string{"Republic of Guinea"}},
+	{"GP", "GLP", "312", "Guadeloupe", nil},
+	{"GQ", "GNQ", "226", "Equatorial Guinea", []string{"Republic of Equatorial Guinea"}},
+	{"GR", "GRC", "300", "Greece", []string{"Hellenic Republic"}},
+	{"GS", "SGS", "239", "South Georgia and the South Sandwich Islands", nil},
+	{"GT", "GTM", "320", "Guatemala", []string{"Republic of Guatemala"}},
+	{"GU", "GUM", "316", "Guam", nil},
+	{"GW", "GNB", "624", "Guinea-Bissau", []string{"Republic of Guinea-Bissau"}},
+	{"GY", "GUY", "328", "Guyana", []string{"Republic of Guyana"}},
+	{"HK", "HKG", "344", "Hong Kong", []string{"Hong Kong Special Administrative Region of China"}},
+	{"HM", "HMD", "334", "Heard Island and McDonald Islands", nil},
+	{"HN", "HND", "340", "Honduras", []string{"Republic of Honduras"}},
+	{"HR", "HRV", "191", "Croatia", []string{"Republic of Croatia"}},
+	{"HT", "HTI", "332", "Haiti", []string{"Republic of Haiti"}},
+	{"HU", "HUN", "348", "Hungary", nil},
+	{"ID", "IDN", "360", "Indonesia", []string{"Republic of Indonesia"}},
+	{"IE", "IRL", "372", "Ireland", nil},
+	{"IL", "ISR", "376", "Israel", []string{"State of Israel"}},
+	{"IM", "IMN", "833", "Isle of Man", nil},
+	{"IN", "IND", "356", "India", []string{"Republic of India"}},
+	{"IO", "IOT", "086", "British Indian Ocean Territory", nil},
+	{"IQ", "IRQ", "368", "Iraq", []string{"Republic of Iraq"}},
+	{"IR", "IRN", "364", "Iran", []string{"Iran, Islamic Republic of", "Islamic Republic of Iran"}},
+	{"IS", "ISL", "352", "Iceland", []string{"Republic of Iceland"}},
+	{"IT", "ITA", "380", "Italy", []string{"Italian Republic"}},
+	{"JE", "JEY", "832", "Jersey", nil},
+	{"JM", "JAM", "388", "Jamaica", nil},
+	{"JO", "JOR", "400", "Jordan", []string{"Hashemite Kingdom of Jordan"}},
+	{"JP", "JPN", "392", "Japan", nil},
+	{"KE", "KEN", "404", "Kenya", []string{"Republic of Kenya"}},
+	{"KG", "KGZ", "417", "Kyrgyzstan", []string{"Kyrgyz Republic"}},
+	{"KH", "KHM", "116", "Cambodia", []string{"Kingdom of Cambodia"}},
+	{"KI", "KIR", "296", "Kiribati", []string{"Republic of Kiribati"}},
+	{"KM", "COM", "174", "Comoros", []string{"Union of the Comoros"}},
+	{"KN", "KNA", "659", "Saint Kitts and Nevis", nil},
+	{"KP", "PRK", "408", "North Korea", []string{"Korea, Democratic People's Republic of", "Democratic People's Republic of Korea"}},
+	{"KR", "KOR", "410", "South Korea", []string{"Korea, Republic of"}},
+	{"KW", "KWT", "414", "Kuwait", []string{"State of Kuwait"}},
+	{"KY", "CYM", "136", "Cayman Islands", nil},
+	{"KZ", "KAZ", "398", "Kazakhstan", []string{"Republic of Kazakhstan"}},
+	{"LA", "LAO", "418", "Laos", []string{"Lao People's Democratic Republic"}},
+	{"LB", "LBN", "422", "Lebanon", []string{"Lebanese Republic"}},
+	{"LC", "LCA", "662", "Saint Lucia", nil},
+	{"LI", "LIE", "438", "Liechtenstein", []string{"Principality of Liechtenstein"}},
+	{"LK", "LKA", "144", "Sri Lanka", []string{"Democratic Socialist Republic of Sri Lanka"}},
+	{"LR", "LBR", "430", "Liberia", []string{"Republic of Liberia"}},
+	{"LS", "LSO", "426", "Lesotho", []string{"Kingdom of Lesotho"}},
+	{"LT", "LTU", "440", "Lithuania", []string{"Republic of Lithuania"}},
+	{"LU", "LUX", "442", "Luxembourg", []string{"Grand Duchy of Luxembourg"}},
+	{"LV", "LVA", "428", "Latvia", []string{"Republic of Latvia"}},
+	{"LY", "LBY", "434", "Libya", nil},
+	{"MA", "MAR", "504", "Morocco", []string{"Kingdom of Morocco"}},
+	{"MC", "MCO", "492", "Monaco", []string{"Principality of Monaco"}},
+	{"MD", "MDA", "498", "Moldova", []string{"Moldova, Republic of", "Republic of Moldova"}},
+	{"ME", "MNE", "499", "Montenegro", nil},
+	{"MF", "MAF", "663", "Saint Martin (French part)", nil},
+	{"MG", "MDG", "450", "Madagascar", []string{"Republic of Madagascar"}},
+	{"MH", "MHL", "584", "Marshall Islands", []string{"Republic of the Marshall Islands"}},
+	{"MK", "MKD", "807", "North Macedonia", []string{"Republic of North Macedonia"}},
+	{"ML", "MLI", "466", "Mali", []string{"Republic of Mali"}},
+	{"MM", "MMR", "104", "Myanmar", []string{"Republic of Myanmar"}},
+	{"MN", "MNG", "496", "Mongolia", nil},
+	{"MO", "MAC", "446", "Macao", []string{"Macao Special Administrative Region of China"}},
+	{"MP", "MNP", "580", "Northern Mariana Islands", []string{"Commonwealth of the Northern Mariana Islands"}},
+	{"MQ", "MTQ", "474", "Martinique", nil},
+	{"MR", "MRT", "478", "Mauritania", []string{"Islamic Republic of Mauritania"}},
+	{"MS", "MSR", "500", "Montserrat", nil},
+	{"MT", "MLT", "470", "Malta", []string{"Republic of Malta"}},
+	{"MU", "MUS", "480", "Mauritius", []string{"Republic of Mauritius"}},
+	{"MV", "MDV", "462", "Maldives", []string{"Republic of Maldives"}},
+	{"MW", "MWI", "454", "Malawi", []string{"Republic of Malawi"}},
+	{"MX", "MEX", "484", "Mexico", []string{"United Mexican States"}},
+	{"MY", "MYS", "458", "Malaysia", nil},
+	{"MZ", "MOZ", "508", "Mozambique", []string{"Republic of Mozambique"}},
+	{"NA", "NAM", "516", "Namibia", []string{"Republic of Namibia"}},
+	{"NC", "NCL", "540", "New Caledonia", nil},
+	{"NE", "NER", "562", "Niger", []string{"Republic of the Niger"}},
+	{"NF", "NFK", "574", "Norfolk Island", nil},
+	{"NG", "NGA", "566", "Nigeria", []string{"Federal Republic of Nigeria"}},
+	{"NI", "NIC", "558", "Nicaragua", []string{"Republic of Nicaragua"}},
+	{"NL", "NLD", "528", "Netherlands", []string{"Kingdom of the Netherlands"}},
+	{"NO", "NOR", "578", "Norway", []string{"Kingdom of Norway"}},
+	{"NP", "NPL", "524", "Nepal", []string{"Federal Democratic Republic of Nepal"}},
+	{"NR", "NRU", "520", "Nauru", []string{"Republic of Nauru"}},
+	{"NU", "NIU", "570", "Niue", nil},
+	{"NZ", "NZL", "554", "New Zealand", nil},
+	{"OM", "OMN", "512", "Oman", []string{"Sultanate of Oman"}},
+	{"PA", "PAN", "591", "Panama", []string{"Republic of Panama"}},
+	{"PE", "PER", "604", "Peru", []string{"Republic of Peru"}},
+	{"PF", "PYF", "258", "French Polynesia", nil},
+	{"PG", "PNG", "598", "Papua New Guinea", []string{"Independent State of Papua New Guinea"}},
+	{"PH", "PHL", "608", "Philippines", []string{"Republic of the Philippines"}},
+	{"PK", "PAK", "586", "Pakistan", []string{"Islamic Republic of Pakistan"}},
+	{"PL", "POL", "616", "Poland", []string{"Republic of Poland"}},
+	{"PM", "SPM", "666", "Saint Pierre and Miquelon", nil},
+	{"PN", "PCN", "612", "Pitcairn", nil},
+	{"PR", "PRI", "630", "Puerto Rico", nil},
+	{"PS", "PSE", "275", "Palestine, State of", []string{"the State of Palestine"}},
+	{"PT", "PRT", "620", "Portugal", []string{"Portuguese Republic"}},
+	{"PW", "PLW", "585", "Palau", []string{"Republic of Palau"}},
+	{"PY", "PRY", "600", "Paraguay", []string{"Republic of Paraguay"}},
+	{"QA", "QAT", "634", "Qatar", []string{"State of Qatar"}},
+	{"RE", "REU", "638", "Réunion", nil},
+	{"RO", "ROU", "642", "Romania", nil},
+	{"RS", "SRB", "688", "Serbia", []string{"Republic of Serbia"}},
+	{"RU", "RUS", "643", "Russian Federation", nil},
+	{"RW", "RWA", "646", "Rwanda", []string{"Rwandese Republic"}},
+	{"SA", "SAU", "682", "Saudi Arabia", []string{"Kingdom of Saudi Arabia"}},
+	{"SB", "SLB", "090", "Solomon Islands", nil},
+	{"SC", "SYC", "690", "Seychelles", []string{"Republic of Seychelles"}},
+	{"SD", "SDN", "729", "Sudan", []string{"Republic of the Sudan"}},
+	{"SE", "SWE", "752", "Sweden", []string{"Kingdom of Sweden"}},
+	{"SG", "SGP", "702", "Singapore", []string{"Republic of Singapore"}},
+	{"SH", "SHN", "654", "Saint Helena, Ascension and Tristan da Cunha", nil},
+	{"SI", "SVN", "705", "Slovenia", []string{"Republic of Slovenia"}},
+	{"SJ", "SJM", "744", "Svalbard and Jan Mayen", nil},
+	{"SK", "SVK", "703", "Slovakia", []string{"Slovak Republic"}},
+	{"SL", "SLE", "694", "Sierra Leone", []string{"Republic of Sierra Leone"}},
+	{"SM", "SMR", "674", "San Marino", []string{"Republic of San Marino"}},
+	{"SN", "SEN", "686", "Senegal", []string{"Republic of Senegal"}},
+	{"SO", "SOM", "706", "Somalia", []string{"Federal Republic of Somalia"}},
+	{"SR", "SUR", "740", "Suriname", []string{"Republic of Suriname"}},
+	{"SS", "SSD", "728", "South Sudan", []string{"Republic of South Sudan"}},
+	{"ST", "STP", "678", "Sao Tome and Principe", []string{"Democratic Republic of Sao Tome and Principe"}},
+	{"SV", "SLV", "222", "El Salvador", []string{"Republic of El Salvador"}},
+	{"SX", "SXM", "534", "Sint Maarten (Dutch part)", nil},
+	{"SY", "SYR", "760", "Syria", []string{"Syrian Arab Republic"}},
+	{"SZ", "SWZ", "748", "Eswatini", []string{"Kingdom of Eswatini"}},
+	{"TC", "TCA", "796", "Turks and Caicos Islands", nil},
+	{"TD", "TCD", "148", "Chad", []string{"Republic of Chad"}},
+	{"TF", "ATF", "260", "French Southern Territories", nil},
+	{"TG", "TGO", "768", "Togo", []string{"Togolese Republic"}},
+	{"TH", "THA", "764", "Thailand", []string{"Kingdom of Thailand"}},
+	{"TJ", "TJK", "762", "Tajikistan", []string{"Republic of Tajikistan"}},
+	{"TK", "TKL", "772", "Tokelau", nil},
+	{"TL", "TLS", "626", "Timor-Leste", []string{"Democratic Republic of Timor-Leste"}},
+	{"TM", "TKM", "795", "Turkmenistan", nil},
+	{"TN", "TUN", "788", "Tunisia", []string{"Republic of Tunisia"}},
+	{"TO", "TON", "776", "Tonga", []string{"Kingdom of Tonga"}},
+	{"TR", "TUR", "792", "Türkiye", []string{"Republic of Türkiye"}},
+	{"TT", "TTO", "780", "Trinidad and Tobago", []string{"Republic of Trinidad and Tobago"}},
+	{"TV", "TUV", "798", "Tuvalu", nil},
+	{"TW", "TWN", "158", "Taiwan", []string{"Taiwan, Province of China"}},
+	{"TZ", "TZA", "834", "Tanzania", []string{"Tanzania, United Republic of", "United Republic of Tanzania"}},
+	{"UA", "UKR", "804", "Ukraine", nil},
+	{"UG", "UGA", "800", "Uganda", []string{"Republic of Uganda"}},
+	{"UM", "UMI", "581", "United States Minor Outlying Islands", nil},
+	{"US", "USA", "840", "United States", []string{"United States of America"}},
+	{"UY", "URY", "858", "Uruguay", []string{"Eastern Republic of Uruguay"}},
+	{"UZ", "UZB", "860", "Uzbekistan", []string{"Republic of Uzbekistan"}},
+	{"VA", "VAT", "336", "Holy See (Vatican City State)", nil},
+	{"VC", "VCT", "670", "Saint Vincent and the Grenadines", nil},
+	{"VE", "VEN", "862", "Venezuela", []string{"Venezuela, Bolivarian Republic of", "Bolivarian Republic of Venezuela"}},
+	{"VG", "VGB", "092", "Virgin Islands, British", []string{"British Virgin Islands"}},
+	{"VI", "VIR", "850", "Virgin Islands, U.S.", []string{"Virgin Islands of the United States"}},
+	{"VN", "VNM", "704", "Vietnam", []string{"Viet Nam", "Socialist Republic of Viet Nam"}},
+	{"VU", "VUT", "548", "Vanuatu", []string{"Republic of Vanuatu"}},
+	{"WF", "WLF", "876", "Wallis and Futuna", nil},
+	{"WS", "WSM", "882", "Samoa", []string{"Independent State of Samoa"}},
+	{"YE", "YEM", "887", "Yemen", []string{"Republic of Yemen"}},
+	{"YT", "MYT", "175", "Mayotte", nil},
+	{"ZA", "ZAF", "710", "South Africa", []string{"Republic of South Africa"}},
+	{"ZM", "ZMB", "894", "Zambia", []string{"Republic of Zambia"}},
+	{"ZW", "ZWE", "716", "Zimbabwe", []string{"Republic of Zimbabwe"}},
+}
+
+var currencies = []isoCurrency{
+	{"AED", "784", "UAE Dirham"},
+	{"AFN", "971", "Afghani"},
+	{"ALL", "008", "Lek"},
+	{"AMD", "051", "Armenian Dram"},
+	{"ANG", "532", "Netherlands Antillean Guilder"},
+	{"AOA", "973", "Kwanza"},
+	{"ARS", "032", "Argentine Peso"},
+	{"AUD", "036", "Australian Dollar"},
+	{"AWG", "533", "Aruban Florin"},
+	{"AZN", "944", "Azerbaijan Manat"},
+	{"BAM", "977", "Convertible Mark"},
+	{"BBD", "052", "Barbados Dollar"},
+	{"BDT", "050", "Taka"},
+	{"BGN", "975", "Bulgarian Lev"},
+	{"BHD", "048", "Bahraini Dinar"},
+	{"BIF", "108", "Burundi Franc"},
+	{"BMD", "060", "Bermudian Dollar"},
+	{"BND", "096", "Brunei Dollar"},
+	{"BOB", "068", "Boliviano"},
+	{"BOV", "984", "Mvdol"},
+	{"BRL", "986", "Brazilian Real"},
+	{"BSD", "044", "Bahamian Dollar"},
+	{"BTN", "064", "Ngultrum"},
+	{"BWP", "072", "Pula"},
+	{"BYN", "933", "Belarusian Ruble"},
+	{"BZD", "084", "Belize Dollar"},
+	{"CAD", "124", "Canadian Dollar"},
+	{"CDF", "976", "Congolese Franc"},
+	{"CHE", "947", "WIR Euro"},
+	{"CHF", "756", "Swiss Franc"},
+	{"CHW", "948", "WIR Franc"},
+	{"CLF", "990", "Unidad de Fomento"},
+	{"CLP", "152", "Chilean Peso"},
+	{"CNY", "156", "Yuan Renminbi"},
+	{"COP", "170", "Colombian Peso"},
+	{"COU", "970", "Unidad de Valor Real"},
+	{"CRC", "188", "Costa Rican Colon"},
+	{"CUC", "931", "Peso Convertible"},
+	{"CUP", "192", "Cuban Peso"},
+	{"CVE", "132", "Cabo Verde Escudo"},
+	{"CZK", "203", "Czech Koruna"},
+	{"DJF", "262", "Djibouti Franc"},
+	{"DKK", "208", "Danish Krone"},
+	{"DOP", "214", "Dominican Peso"},
+	{"DZD", "012", "Algerian Dinar"},
+	{"EGP", "818", "Egyptian Pound"},
+	{"ERN", "232", "Nakfa"},
+	{"ETB", "230", "Ethiopian Birr"},
+	{"EUR", "978", "Euro"},
+	{"FJD", "242", "Fiji Dollar"},
+	{"FKP", "238", "Falkland Islands Pound"},
+	{"GBP", "826", "Pound Sterling"},
+	{"GEL", "981", "Lari"},
+	{"GHS", "936", "Ghana Cedi"},
+	{"GIP", "292", "Gibraltar Pound"},
+	{"GMD", "270", "Dalasi"},
+	{"GNF", "324", "Guinean Franc"},
+	{"GTQ", "320", "Quetzal"},
+	{"GYD", "328", "Guyana Dollar"},
+	{"HKD", "344", "Hong Kong Dollar"},
+	{"HNL", "340", "Lempira"},
+	{"HRK", "191", "Kuna"},
+	{"HTG", "332", "Gourde"},
+	{"HUF", "348", "Forint"},
+	{"IDR", "360", "Rupiah"},
+	{"ILS", "376", "New Israeli Sheqel"},
+	{"INR", "356", "Indian Rupee"},
+	{"IQD", "368", "Iraqi Dinar"},
+	{"IRR", "364", "Iranian Rial"},
+	{"ISK", "352", "Iceland Krona"},
+	{"JMD", "388", "Jamaican Dollar"},
+	{"JOD", "400", "Jordanian Dinar"},
+	{"JPY", "392", "Yen"},
+	{"KES", "404", "Kenyan Shilling"},
+	{"KGS", "417", "Som"},
+	{"KHR", "116", "Riel"},
+	{"KMF", "174", "Comorian Franc"},
+	{"KPW", "408", "North Korean Won"},
+	{"KRW", "410", "Won"},
+	{"KWD", "414", "Kuwaiti Dinar"},
+	{"KYD", "136", "Cayman Islands Dollar"},
+	{"KZT", "398", "Tenge"},
+	{"LAK", "418", "Lao Kip"},
+	{"LBP", "422", "Lebanese Pound"},
+	{"LKR", "144", "Sri Lanka Rupee"},
+	{"LRD", "430", "Liberian Dollar"},
+	{"LSL", "426", "Loti"},
+	{"LYD", "434", "Libyan Dinar"},
+	{"MAD", "504", "Moroccan Dirham"},
+	{"MDL", "498", "Moldovan Leu"},
+	{"MGA", "969", "Malagasy Ariary"},
+	{"MKD", "807", "Denar"},
+	{"MMK", "104", "Kyat"},
+	{"MNT", "496", "Tugrik"},
+	{"MOP", "446", "Pataca"},
+	{"MRU", "929", "Ouguiya"},
+	{"MUR", "480", "Mauritius Rupee"},
+	{"MVR", "462", "Rufiyaa"},
+	{"MWK", "454", "Malawi Kwacha"},
+	{"MXN", "484", "Mexican Peso"},
+	{"MXV", "979", "Mexican Unidad de Inversion (UDI)"},
+	{"MYR", "458", "Malaysian Ringgit"},
+	{"MZN", "943", "Mozambique Metical"},
+	{"NAD", "516", "Namibia Dollar"},
+	{"NGN", "566", "Naira"},
+	{"NIO", "558", "Cordoba Oro"},
+	{"NOK", "578", "Norwegian Krone"},
+	{"NPR", "524", "Nepalese Rupee"},
+	{"NZD", "554", "New Zealand Dollar"},
+	{"OMR", "512", "Rial Omani"},
+	{"PAB", "590", "Balboa"},
+	{"PEN", "604", "Sol"},
+	{"PGK", "598", "Kina"},
+	{"PHP", "608", "Philippine Peso"},
+	{"PKR", "586", "Pakistan Rupee"},
+	{"PLN", "985", "Zloty"},
+	{"PYG", "600", "Guarani"},
+	{"QAR", "634", "Qatari Rial"},
+	{"RON", "946", "Romanian Leu"},
+	{"RSD", "941", "Serbian Dinar"},
+	{"RUB", "643", "Russian Ruble"},
+	{"RWF", "646", "Rwanda Franc"},
+	{"SAR", "682", "Saudi Riyal"},
+	{"SBD", "090", "Solomon Islands Dollar"},
+	{"SCR", "690", "Seychelles Rupee"},
+	{"SDG", "938", "Sudanese Pound"},
+	{"SEK", "752", "Swedish Krona"},
+	{"SGD", "702", "Singapore Dollar"},
+	{"SHP", "654", "Saint Helena Pound"},
+	{"SLE", "925", "Leone"},
+	{"SLL", "694", "Leone"},
+	{"SOS", "706", "Somali Shilling"},
+	{"SRD", "968", "Surinam Dollar"},
+	{"SSP", "728", "South Sudanese Pound"},
+	{"STN", "930", "Dobra"},
+	{"SVC", "222", "El Salvador Colon"},
+	{"SYP", "760", "Syrian Pound"},
+	{"SZL", "748", "Lilangeni"},
+	{"THB", "764", "Baht"},
+	{"TJS", "972", "Somoni"},
+	{"TMT", "934", "Turkmenistan New Manat"},
+	{"TND", "788", "Tunisian Dinar"},
+	{"TOP", "776", "Pa’anga"},
+	{"TRY", "949", "Turkish Lira"},
+	{"TTD", "780", "Trinidad and Tobago Dollar"},
+	{"TWD", "901", "New Taiwan Dollar"},
+	{"TZS", "834", "Tanzanian Shilling"},
+	{"UAH", "980", "Hryvnia"},
+	{"UGX", "800", "Uganda Shilling"},
+	{"USD", "840", "US Dollar"},
+	{"USN", "997", "US Dollar (Next day)"},
+	{"UYI", "940", "Uruguay Peso en Unidades Indexadas (UI)"},
+	{"UYU", "858", "Peso Uruguayo"},
+	{"UYW", "927", "Unidad Previsional"},
+	{"UZS", "860", "Uzbekistan Sum"},
+	{"VED", "926", "Bolívar Soberano"},
+	{"VES", "928", "Bolívar Soberano"},
+	{"VND", "704", "Dong"},
+	{"VUV", "548", "Vatu"},
+	{"WST", "882", "Tala"},
+	{"XAF", "950", "CFA Franc BEAC"},
+	{"XAG", "961", "Silver"},
+	{"XAU", "959", "Gold"},
+	{"XBA", "955", "Bond Markets Unit European Composite Unit (EURCO)"},
+	{"XBB", "956", "Bond Markets Unit European Monetary Unit (E.M.U.-6)"},
+	{"XBC", "957", "Bond Markets Unit European Unit of Account 9 (E.U.A.-9)"},
+	{"XBD", "958", "Bond Markets Unit European Unit of Account 17 (E.U.A.-17)"},
+	{"XCD", "951", "East Caribbean Dollar"},
+	{"XDR", "960", "SDR (Special Drawing Right)"},
+	{"XOF", "952", "CFA Franc BCEAO"},
+	{"XPD", "964", "Palladium"},
+	{"XPF", "953", "CFP Franc"},
+	{"XPT", "962", "Platinum"},
+	{"XSU", "994", "Sucre"},
+	{"XTS", "963", "Codes specifically reserved for testing purposes"},
+	{"XUA", "965", "ADB Unit of Account"},
+	{"XXX", "999", "The codes assigned for transactions where no currency is involved"},
+	{"YER", "886", "Yemeni Rial"},
+	{"ZAR", "710", "Rand"},
+	{"ZMW", "967", "Zambian Kwacha"},
+	{"ZWL", "932", "Zimbabwe Dollar"},
+}
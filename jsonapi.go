@@ -0,0 +1,190 @@
+package mson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// UnmarshalJSONAPI decodes a JSON:API (jsonapi.org) document into v, a
+// pointer to a struct for a single resource or to a slice of structs for a
+// collection. Each resource is flattened before its tags are applied: its
+// attributes become keys next to id and type, and each relationship becomes a
+// key holding the linked resource identifier, or an array of them for to-many
+// relationships. Linked resources found under included are merged into their
+// identifier with their attributes. A document holding errors is returned as
+// a *JSONAPIError.
+func UnmarshalJSONAPI(data []byte, v any, opts ...Option) error {
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("mson: UnmarshalJSONAPI requires a non-nil pointer")
+	}
+
+	state := newDecodeState(opts)
+
+	var doc struct {
+		Data     json.RawMessage          `json:"data"`
+		Included []map[string]interface{} `json:"included"`
+		Errors   []map[string]interface{} `json:"errors"`
+	}
+
+	if err := parseDocument(data, &doc, &state.settings); err != nil {
+		return err
+	}
+
+	if len(doc.Errors) > 0 {
+		return jsonAPIError(doc.Errors)
+	}
+
+	included := make(map[string]map[string]interface{}, len(doc.Included))
+
+	for _, res := range doc.Included {
+		included[resourceKey(res)] = res
+	}
+
+	var primary interface{}
+
+	if len(doc.Data) > 0 {
+		if err := parseDocument(doc.Data, &primary, &state.settings); err != nil {
+			return err
+		}
+	}
+
+	return decodeNode(reflect.ValueOf(v).Elem(), primary, func(res map[string]interface{}) map[string]interface{} {
+		return flattenResource(res, included)
+	}, state)
+}
+
+func resourceKey(res map[string]interface{}) string {
+	return fmt.Sprint(res["type"]) + "/" + fmt.Sprint(res["id"])
+}
+
+// flattenResource merges the attributes and relationships of a resource into
+// a single object next to its id and type. Linked resources are looked up in
+// included, whose own relationships are reduced to identifiers so that cycles
+// between resources end.
+func flattenResource(res map[string]interface{}, included map[string]map[string]interface{}) map[string]interface{} {
+	flat := map[string]interface{}{}
+
+	if attributes, ok := res["attributes"].(map[string]interface{}); ok {
+		for k, v := range attributes {
+			flat[k] = v
+		}
+	}
+
+	relationships, _ := res["relationships"].(map[string]interface{})
+
+	for name, rel := range relationships {
+		relationship, _ := rel.(map[string]interface{})
+		linkage, ok := relationship["data"]
+
+		if !ok {
+			continue
+		}
+
+		switch l := linkage.(type) {
+		case map[string]interface{}:
+			flat[name] = linkedResource(l, included)
+		case []interface{}:
+			linked := make([]interface{}, len(l))
+
+			for i, id := range l {
+				if m, ok := id.(map[string]interface{}); ok {
+					linked[i] = linkedResource(m, included)
+				}
+			}
+
+			flat[name] = linked
+		default:
+			flat[name] = nil
+		}
+	}
+
+	for _, k := range []string{"id", "type"} {
+		if v, ok := res[k]; ok {
+			flat[k] = v
+		}
+	}
+
+	return flat
+}
+
+func linkedResource(id map[string]interface{}, included map[string]map[string]interface{}) interface{} {
+	if res, ok := included[resourceKey(id)]; ok {
+		return flattenResource(res, nil)
+	}
+
+	return map[string]interface{}{"id": id["id"], "type": id["type"]}
+}
+
+// A JSONAPIError is returned by UnmarshalJSONAPI for a document holding
+// errors. It wraps ErrJSONAPI.
+type JSONAPIError struct {
+	Errors []JSONAPIErrorObject
+}
+
+// A JSONAPIErrorObject is one of the error objects of a JSON:API document.
+// Members the server did not send are left empty.
+type JSONAPIErrorObject struct {
+	ID     string
+	Status string
+	Code   string
+	Title  string
+	Detail string
+	Source map[string]interface{}
+	Meta   map[string]interface{}
+}
+
+func (e *JSONAPIError) Error() string {
+	messages := make([]string, len(e.Errors))
+
+	for i, obj := range e.Errors {
+		message := obj.Title
+
+		if obj.Detail != "" {
+			message = obj.Detail
+		}
+
+		if obj.Status != "" {
+			message = obj.Status + " " + message
+		}
+
+		messages[i] = message
+	}
+
+	return fmt.Sprintf("%s: %s", ErrJSONAPI, strings.Join(messages, "; "))
+}
+
+func (e *JSONAPIError) Unwrap() error {
+	return ErrJSONAPI
+}
+
+func jsonAPIError(errs []map[string]interface{}) error {
+	objs := make([]JSONAPIErrorObject, len(errs))
+
+	for i, e := range errs {
+		member := func(name string) string {
+			if v, ok := e[name]; ok && v != nil {
+				return fmt.Sprint(v)
+			}
+
+			return ""
+		}
+
+		source, _ := e["source"].(map[string]interface{})
+		meta, _ := e["meta"].(map[string]interface{})
+
+		objs[i] = JSONAPIErrorObject{
+			ID:     member("id"),
+			Status: member("status"),
+			Code:   member("code"),
+			Title:  member("title"),
+			Detail: member("detail"),
+			Source: source,
+			Meta:   meta,
+		}
+	}
+
+	return &JSONAPIError{Errors: objs}
+}
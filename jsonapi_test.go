@@ -0,0 +1,63 @@
+package mson_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+type article struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Author struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"author"`
+}
+
+func TestUnmarshalJSONAPI(t *testing.T) {
+	data := []byte(`{
+		"data": {"type": "articles", "id": "1", "attributes": {"title": "Hello"},
+			"relationships": {"author": {"data": {"type": "people", "id": "9"}}}},
+		"included": [{"type": "people", "id": "9", "attributes": {"name": "Ada"}}]
+	}`)
+
+	var a article
+
+	if err := mson.UnmarshalJSONAPI(data, &a); err != nil {
+		t.Fatal(err)
+	}
+
+	if a.ID != "1" || a.Title != "Hello" || a.Author.ID != "9" || a.Author.Name != "Ada" {
+		t.Fatalf("got %+v", a)
+	}
+}
+
+func TestUnmarshalJSONAPIErrors(t *testing.T) {
+	data := []byte(`{"errors": [
+		{"status": "422", "code": "too_short", "title": "Invalid attribute", "detail": "Title is too short", "source": {"pointer": "/data/attributes/title"}},
+		{"status": "403", "title": "Forbidden"}
+	]}`)
+
+	var a article
+	err := mson.UnmarshalJSONAPI(data, &a)
+
+	if !errors.Is(err, mson.ErrJSONAPI) {
+		t.Fatalf("got %v, want ErrJSONAPI", err)
+	}
+
+	var apiErr *mson.JSONAPIError
+
+	if !errors.As(err, &apiErr) || len(apiErr.Errors) != 2 {
+		t.Fatalf("got %#v, want a JSONAPIError with two objects", err)
+	}
+
+	if first := apiErr.Errors[0]; first.Code != "too_short" || first.Source["pointer"] != "/data/attributes/title" {
+		t.Errorf("got %+v", first)
+	}
+
+	if want := "mson: JSON:API document holds errors: 422 Title is too short; 403 Forbidden"; err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+}
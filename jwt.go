@@ -0,0 +1,52 @@
+package mson
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// UnmarshalJWTClaims decodes the claims segment of a JSON Web Token into the
+// struct pointed to by v. The token's signature is NOT verified; callers must
+// do so before trusting the claims. Since JWT timestamps are NumericDates,
+// time.Time fields without a conversion option are decoded as if tagged unix.
+func UnmarshalJWTClaims(token string, v any) error {
+	segments := strings.Split(token, ".")
+
+	if len(segments) != 3 {
+		return errors.New("mson: malformed JWT, expected three segments")
+	}
+
+	claims, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segments[1], "="))
+
+	if err != nil {
+		return fmt.Errorf("mson: %w, decoding of JWT claims failed", err)
+	}
+
+	return unmarshal(claims, v, &decodeState{jwt: true})
+}
+
+func jwtOptions(metaData reflect.StructField, options []string) []string {
+	t := metaData.Type
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t != timeType {
+		return options
+	}
+
+	for _, opt := range options {
+		if name := splitIgnoreQuoted(opt, ',')[0]; name == "unix" || name == "unix!" {
+			return options
+		}
+	}
+
+	return append([]string{"unix"}, options...)
+}
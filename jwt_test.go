@@ -0,0 +1,38 @@
+package mson_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+func TestUnmarshalJWTClaims(t *testing.T) {
+	type claims struct {
+		Subject  string    `json:"sub"`
+		Expires  time.Time `json:"exp"`
+		IssuedAt time.Time `json:"iat,unix,milliseconds"`
+		Issuer   string    `json:"iss"`
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"ada","exp":1700000000,"iat":1700000000123,"iss":"auth"}`))
+	token := "eyJhbGciOiJub25lIn0." + payload + ".signature"
+
+	var v claims
+
+	if err := mson.UnmarshalJWTClaims(token, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	// Timestamps without an option of their own are NumericDates
+	if v.Subject != "ada" || !v.Expires.Equal(time.Unix(1700000000, 0)) || !v.IssuedAt.Equal(time.UnixMilli(1700000000123)) || v.Issuer != "auth" {
+		t.Fatalf("got %+v", v)
+	}
+
+	for _, token := range []string{"only.two", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte(`{"exp":"soon"}`)) + ".c"} {
+		if err := mson.UnmarshalJWTClaims(token, &v); err == nil {
+			t.Errorf("decoded invalid token %s", token)
+		}
+	}
+}
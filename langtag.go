@@ -0,0 +1,145 @@
+package mson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// A LanguageTagParser parses BCP 47 language tags into a value assignable to
+// fields tagged with langtag, such as golang.org/x/text/language.Tag. The
+// built-in parser validates the tag's syntax and canonicalizes it into a
+// string, which keeps x/text an optional dependency. A LanguageTagParser must
+// be safe for concurrent use.
+type LanguageTagParser interface {
+	ParseLanguageTag(tag string) (interface{}, error)
+}
+
+var (
+	langMu     sync.RWMutex
+	langParser LanguageTagParser = basicLanguageTagParser{}
+)
+
+// RegisterLanguageTagParser replaces the parser used by the langtag tag
+// option. Passing nil restores the built-in parser.
+func RegisterLanguageTagParser(p LanguageTagParser) {
+	langMu.Lock()
+	defer langMu.Unlock()
+
+	if p == nil {
+		p = basicLanguageTagParser{}
+	}
+
+	langParser = p
+}
+
+var deprecatedLanguages = map[string]string{
+	"in": "id",
+	"iw": "he",
+	"ji": "yi",
+	"jw": "jv",
+	"mo": "ro",
+}
+
+type basicLanguageTagParser struct{}
+
+func (basicLanguageTagParser) ParseLanguageTag(tag string) (interface{}, error) {
+	subtags := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+
+	for i, s := range subtags {
+		if len(s) == 0 || len(s) > 8 || !isAlphanumeric(s) {
+			return nil, fmt.Errorf("invalid language tag %q", tag)
+		}
+
+		subtags[i] = strings.ToLower(s)
+	}
+
+	lang := subtags[0]
+
+	if (len(lang) < 2 || len(lang) > 8 || len(lang) == 4 || !isAlpha(lang)) && lang != "x" && lang != "i" {
+		return nil, fmt.Errorf("invalid language tag %q", tag)
+	}
+
+	if replacement, ok := deprecatedLanguages[lang]; ok {
+		subtags[0] = replacement
+	}
+
+	// Singletons start extensions and private use sections, which are not cased
+	for i := 1; i < len(subtags); i++ {
+		s := subtags[i]
+
+		switch {
+		case len(s) == 1:
+			if i == len(subtags)-1 {
+				return nil, fmt.Errorf("invalid language tag %q", tag)
+			}
+			return strings.Join(subtags, "-"), nil
+		case len(s) == 4 && isAlpha(s) && i == 1:
+			subtags[i] = strings.ToUpper(s[:1]) + s[1:]
+		case len(s) == 2 && isAlpha(s), len(s) == 3 && !isAlpha(s) && isNumeric(s):
+			subtags[i] = strings.ToUpper(s)
+		case len(s) >= 5, len(s) == 4 && s[0] >= '0' && s[0] <= '9':
+		case len(s) == 3 && isAlpha(s) && i <= 3:
+			// Extended language subtags
+		default:
+			return nil, fmt.Errorf("invalid language tag %q", tag)
+		}
+	}
+
+	return strings.Join(subtags, "-"), nil
+}
+
+func isAlpha(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isNumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; (c < 'a' || c > 'z') && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseLanguageTag(value interface{}, target reflect.Type, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	langMu.RLock()
+	p := langParser
+	langMu.RUnlock()
+
+	tag, err := p.ParseLanguageTag(s)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, parsing of field %s as a language tag failed", err, fieldName)
+	}
+
+	if target.Kind() == reflect.String {
+		return fmt.Sprint(tag), nil
+	}
+
+	return tag, nil
+}
@@ -0,0 +1,203 @@
+package mson_test
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+func TestPhoneOption(t *testing.T) {
+	type contact struct {
+		Phone string `json:"phone,phone,GB"`
+		Intl  string `json:"intl,phone"`
+		US    string `json:"us,phone,US"`
+	}
+
+	var v contact
+
+	if err := mson.Unmarshal([]byte(`{"phone":"020 7946 0958","intl":"0049 (30) 1234-5678","us":"1-415-555-0100"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (contact{Phone: "+442079460958", Intl: "+493012345678", US: "+14155550100"}); v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+
+	for _, data := range []string{`{"phone":"12"}`, `{"intl":"555-0100"}`, `{"phone":"call me"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("decoded invalid number %s", data)
+		}
+	}
+}
+
+func TestCountryAndCurrencyOptions(t *testing.T) {
+	type codes struct {
+		Country  string `json:"country,country"`
+		Alpha3   string `json:"alpha3,country,alpha3"`
+		Name     string `json:"name,country,name"`
+		Currency string `json:"currency,currency"`
+		Numeric  string `json:"numeric,currency,numeric"`
+	}
+
+	data := []byte(`{"country":"Federal Republic of Germany","alpha3":"de","name":"276","currency":"euro","numeric":"EUR"}`)
+
+	var v codes
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (codes{Country: "DE", Alpha3: "DEU", Name: "Germany", Currency: "EUR", Numeric: "978"}); v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+
+	for _, data := range []string{`{"country":"Atlantis"}`, `{"currency":"doubloon"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("decoded invalid code %s", data)
+		}
+	}
+}
+
+func TestLangtagOption(t *testing.T) {
+	var v struct {
+		Language string `json:"language,langtag"`
+		Private  string `json:"private,langtag"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"language":"zh_hant_tw","private":"iw-x-Custom"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Language != "zh-Hant-TW" || v.Private != "he-x-custom" {
+		t.Fatalf("got %+v", v)
+	}
+
+	for _, data := range []string{`{"language":"en-"}`, `{"language":"e"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("decoded invalid tag %s", data)
+		}
+	}
+}
+
+type tagParser struct{}
+
+func (tagParser) ParseLanguageTag(tag string) (interface{}, error) {
+	if tag == "" {
+		return nil, errors.New("empty tag")
+	}
+
+	return "parsed:" + tag, nil
+}
+
+type fixedPhone struct{}
+
+func (fixedPhone) NormalizePhone(number, region string) (string, error) {
+	return region + ":" + number, nil
+}
+
+func TestRegisterPhoneNormalizer(t *testing.T) {
+	mson.RegisterPhoneNormalizer(fixedPhone{})
+	defer mson.RegisterPhoneNormalizer(nil)
+
+	var v struct {
+		Phone string `json:"phone,phone,FR"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"phone":"01"}`), &v); err != nil || v.Phone != "FR:01" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+}
+
+func TestRegisterLanguageTagParser(t *testing.T) {
+	mson.RegisterLanguageTagParser(tagParser{})
+	defer mson.RegisterLanguageTagParser(nil)
+
+	var v struct {
+		Language string `json:"language,langtag"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"language":"en"}`), &v); err != nil || v.Language != "parsed:en" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+}
+
+func TestColorOption(t *testing.T) {
+	type theme struct {
+		Named  string `json:"named,color"`
+		Short  string `json:"short,color"`
+		Alpha  string `json:"alpha,color"`
+		Clear  string `json:"clear,color"`
+		Opaque string `json:"opaque,color"`
+	}
+
+	data := []byte(`{"named":"RebeccaPurple","short":"#f0a","alpha":"#ff000080","clear":"transparent","opaque":"rgb(100%,0,0)"}`)
+
+	var v theme
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	want := theme{
+		Named: "#663399", Short: "#ff00aa",
+		Alpha: "#ff000080", Clear: "#00000000",
+		Opaque: "#ff0000",
+	}
+
+	if v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+
+	for _, data := range []string{`{"named":"reddish"}`, `{"short":"#12345"}`, `{"opaque":"rgb(300,0,0)"}`, `{"opaque":"rgb(1,2)"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("decoded invalid color %s", data)
+		}
+	}
+}
+
+func TestColorOptionPremultipliesRGBA(t *testing.T) {
+	var v struct {
+		RGB  color.RGBA  `json:"rgb,color"`
+		NRGB color.NRGBA `json:"nrgb,color"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"rgb":"rgba(255, 0, 0, 50%)","nrgb":"rgba(255, 0, 0, 50%)"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.RGB != (color.RGBA{R: 0x80, A: 0x80}) || v.NRGB != (color.NRGBA{R: 0xff, A: 0x80}) {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestLatLngOption(t *testing.T) {
+	type place struct {
+		Text    mson.Point `json:"text,latlng"`
+		Pair    mson.Point `json:"pair,latlng"`
+		GeoJSON mson.Point `json:"geojson,latlng,lnglat"`
+		Object  mson.Point `json:"object,latlng"`
+		String  string     `json:"string,latlng"`
+	}
+
+	data := []byte(`{"text":" 51.5, -0.12 ","pair":[51.5,-0.12],"geojson":[-0.12,51.5,35],"object":{"latitude":51.5,"lon":-0.12},"string":[51.5,-0.12]}`)
+
+	var v place
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	p := mson.Point{Lat: 51.5, Lng: -0.12}
+
+	if v.Text != p || v.Pair != p || v.GeoJSON != p || v.Object != p || v.String != "51.5,-0.12" {
+		t.Fatalf("got %+v", v)
+	}
+
+	for _, data := range []string{`{"pair":[91,0]}`, `{"pair":[0,181]}`, `{"pair":[1,2,3]}`, `{"object":{"lat":1}}`, `{"text":"north"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("decoded invalid coordinate %s", data)
+		}
+	}
+}
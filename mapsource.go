@@ -0,0 +1,115 @@
+package mson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Decode stores the values of src in the struct pointed to by v, applying the
+// options found in each field's tag as Unmarshal does, without a JSON
+// document in between. src may come from any source of loosely typed data,
+// such as a YAML or TOML parser or a database driver: numbers of any type,
+// maps with keys of any type and slices of any type are read like their JSON
+// counterparts.
+func Decode(src map[string]any, v any) error {
+	return decodeMap(src, v, &decodeState{})
+}
+
+// DecodeWithOptions is like Decode, but applies the given options to the
+// decode.
+func DecodeWithOptions(src map[string]any, v any, opts ...Option) error {
+	return decodeMap(src, v, newDecodeState(opts))
+}
+
+// Decode is like the package-level Decode, using the codec's options.
+func (c *Codec) Decode(src map[string]any, v any) error {
+	return decodeMap(src, v, &decodeState{settings: c.settings})
+}
+
+func decodeMap(src map[string]any, v any, state *decodeState) error {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("mson: Decode requires a non-nil pointer to a struct")
+	}
+
+	obj, _ := documentValue(reflect.ValueOf(src)).(map[string]interface{})
+
+	return decodeStruct(rv.Elem(), obj, state)
+}
+
+// documentValue returns v in the shape of a parsed JSON document: objects
+// as map[string]interface{}, arrays as []interface{} and numbers as
+// json.Number, as parseDocument leaves them. Other values, such as strings,
+// booleans or times, are kept as they are.
+func documentValue(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+
+		obj := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+
+		for iter.Next() {
+			obj[fmt.Sprint(iter.Key().Interface())] = documentValue(iter.Value())
+		}
+
+		return obj
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+
+		// Bytes are kept whole rather than read as an array of numbers
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+
+		elems := make([]interface{}, v.Len())
+
+		for i := range elems {
+			elems[i] = documentValue(v.Index(i))
+		}
+
+		return elems
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return json.Number(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+
+		// JSON has no literal for these, so they stay floats
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return f
+		}
+
+		return json.Number(strconv.FormatFloat(f, 'g', -1, v.Type().Bits()))
+	case reflect.String:
+		if v.Type() == jsonNumberType {
+			return v.Interface()
+		}
+
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	}
+
+	return v.Interface()
+}
@@ -0,0 +1,74 @@
+package mson_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+func TestDecodeFromMap(t *testing.T) {
+	type service struct {
+		Name    string            `json:"name,kebab"`
+		Port    uint16            `json:"port,port"`
+		Timeout time.Duration     `json:"timeout,duration"`
+		Weights []float64         `json:"weights,sort"`
+		Labels  map[string]string `json:"labels"`
+		Started time.Time         `json:"started"`
+	}
+
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Values as a YAML parser or a database driver would produce them
+	src := map[string]any{
+		"name":    "ApiGateway",
+		"port":    int64(8080),
+		"timeout": 30,
+		"weights": []int{3, 1, 2},
+		"labels":  map[interface{}]interface{}{"tier": "edge"},
+		"started": started,
+	}
+
+	var v service
+
+	if err := mson.Decode(src, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Name != "api-gateway" || v.Port != 8080 || v.Timeout != 30*time.Second || !v.Started.Equal(started) {
+		t.Errorf("got %+v", v)
+	}
+
+	if len(v.Weights) != 3 || v.Weights[0] != 1 || v.Labels["tier"] != "edge" {
+		t.Errorf("got %v and %v", v.Weights, v.Labels)
+	}
+
+	var md mson.Metadata
+
+	if err := mson.DecodeWithOptions(map[string]any{"name": "a", "unknown": true}, &v, mson.WithMetadata(&md)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(md.Unused) != 1 || md.Unused[0] != "unknown" {
+		t.Errorf("got unused keys %v", md.Unused)
+	}
+
+	if err := mson.Decode(src, v); err == nil {
+		t.Error("decoded into a struct that is not a pointer")
+	}
+}
+
+func TestDecodeLargeIntegersFromMap(t *testing.T) {
+	var v struct {
+		Big   int64  `json:"big"`
+		Large uint64 `json:"large"`
+	}
+
+	if err := mson.Decode(map[string]any{"big": uint64(1<<63 - 1), "large": uint64(1<<64 - 1)}, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Big != 1<<63-1 || v.Large != 1<<64-1 {
+		t.Fatalf("got %+v", v)
+	}
+}
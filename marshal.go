@@ -0,0 +1,607 @@
+package mson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"image/color"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type encodeState struct {
+	settings
+	parent reflect.Value
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func encodeTag(field reflect.Value, options []string, fieldName string, state *encodeState) (interface{}, error) {
+	var value interface{} = field.Interface()
+
+	// Options are applied in reverse so that Marshal undoes what Unmarshal
+	// did. Options that lose information, such as round or timetrunc, are
+	// left as they are. redact and mask hide what the other options write,
+	// so they are applied last wherever they appear in the tag.
+	var hidden []string
+
+	for i := len(options) - 1; i >= 0; i-- {
+		parts := splitIgnoreQuoted(options[i], ',')
+
+		modified := parts[0]
+		var inverted bool
+
+		if len(parts[0]) > 0 && rune(parts[0][len(parts[0])-1]) == '!' {
+			inverted = true
+			modified = parts[0][:len(parts[0])-1]
+		}
+
+		switch modified {
+		case "redact", "mask":
+			hidden = parts
+		case "encrypted":
+			v, err := encryptValue(value, parts, fieldName, state)
+
+			if err != nil {
+				return nil, err
+			}
+
+			value = v
+		case "gzip", "zlib":
+			v, err := compressValue(value, modified, fieldName, state)
+
+			if err != nil {
+				return nil, err
+			}
+
+			value = v
+		case "durfmt":
+			d, ok := value.(time.Duration)
+
+			if !ok {
+				return nil, errorf(ErrTypeMismatch, "mson: field %s is not a time.Duration", fieldName)
+			}
+
+			format := "human"
+
+			if len(parts) > 1 {
+				format = parts[1]
+			}
+
+			v, err := formatDuration(d, format)
+
+			if err != nil {
+				return nil, fmt.Errorf("mson: %w, formatting of field %s failed", err, fieldName)
+			}
+
+			value = v
+		case "unix", "duration":
+			v, err := formatUnits(value, append([]string{modified}, parts[1:]...), inverted, fieldName, state.now())
+
+			if err != nil {
+				return nil, err
+			}
+
+			value = v
+		case "add", "subtract", "multiply", "divide":
+			v, err := invertArithmetic(value, append([]string{modified}, parts[1:]...), fieldName)
+
+			if err != nil {
+				return nil, err
+			}
+
+			value = v
+		case "prec", "fmt":
+			v, err := formatFloat(value, options, fieldName)
+
+			if err != nil {
+				return nil, err
+			}
+
+			value = v
+		case "null":
+			if len(parts) > 2 && parts[2] == "emit" && field.IsZero() {
+				value = unquote(parts[1])
+			}
+		case "string":
+			value = formatStringOption(value)
+		case "hostport":
+			if hp, ok := value.(HostPort); ok {
+				value = hp.String()
+			}
+		case "mac":
+			value = formatMAC(value)
+		case "tostring":
+			v, err := stringifyValue(value, parts, state)
+
+			if err != nil {
+				return nil, err
+			}
+
+			value = v
+		case "pem", "x509":
+			v, err := formatPEM(value, parts)
+
+			if err != nil {
+				return nil, err
+			}
+
+			value = v
+		case "htmlescape":
+			s, ok := value.(string)
+
+			if !ok {
+				return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+			}
+
+			value = html.EscapeString(s)
+		case "color":
+			if c, ok := value.(color.Color); ok {
+				value = formatColor(c)
+			}
+		case "hash":
+			if len(parts) < 3 {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'hash' requires an algorithm and the name of the hashed field"))
+			}
+
+			sibling, metaData, ok := findField(state.parent, parts[2], &state.settings)
+
+			if !ok {
+				return nil, errorf(ErrFieldMissing, "mson: field %s references missing field %s", fieldName, parts[2])
+			}
+
+			_, siblingOptions, _ := parseTag(metaData, &state.settings)
+			referenced, err := encodeTag(sibling, fieldOptions(metaData.Type, siblingOptions, &state.settings), parts[2], state)
+
+			if err != nil {
+				return nil, err
+			}
+
+			// The referenced field is hashed as Marshal writes it, or by the
+			// contents of a string, as verifyHash reads it back
+			var buf bytes.Buffer
+
+			if s, ok := referenced.(string); ok {
+				buf.WriteString(s)
+			} else if err := encodeValue(&buf, reflect.ValueOf(referenced), state); err != nil {
+				return nil, err
+			}
+
+			sum, err := digest(parts[1], buf.Bytes())
+
+			if err != nil {
+				return nil, fmt.Errorf("mson: %w, hashing of field %s failed", err, fieldName)
+			}
+
+			value = sum
+		}
+	}
+
+	if hidden == nil || state.noRedact || field.IsZero() {
+		return value, nil
+	}
+
+	return hideValue(value, hidden, fieldName)
+}
+
+// hideValue replaces a value with the text redact writes, or with the value
+// masked as mask asks for.
+func hideValue(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	if strings.TrimSuffix(parts[0], "!") == "redact" {
+		if len(parts) > 1 {
+			if arg, err := strconv.Unquote(parts[1]); err == nil {
+				return arg, nil
+			}
+
+			return parts[1], nil
+		}
+
+		return "[REDACTED]", nil
+	}
+
+	mode := "all"
+
+	if len(parts) > 1 {
+		mode = parts[1]
+	}
+
+	masked, err := maskString(fmt.Sprint(value), mode)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, masking of field %s failed", err, fieldName)
+	}
+
+	return masked, nil
+}
+
+// stringifyValue encodes a value as JSON, honoring the tags of nested structs,
+// and embeds the document as a string as many times as tostring asks for.
+// Nil values stay null.
+func stringifyValue(value interface{}, parts []string, state *encodeState) (interface{}, error) {
+	layers := 1
+
+	if len(parts) > 1 {
+		n, err := strconv.Atoi(parts[1])
+
+		if err != nil || n < 1 {
+			panic(errorf(ErrInvalidTag, "mson: tag option 'tostring' received invalid argument %s", parts[1]))
+		}
+
+		layers = n
+	}
+
+	rv := reflect.ValueOf(value)
+
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+
+	if err := encodeValue(&buf, rv, state); err != nil {
+		return nil, err
+	}
+
+	s := buf.String()
+
+	for i := 1; i < layers; i++ {
+		b, err := json.Marshal(s)
+
+		if err != nil {
+			return nil, err
+		}
+
+		s = string(b)
+	}
+
+	return s, nil
+}
+
+// formatFloat renders a float field as a JSON number with the precision and
+// format given by the field's prec and fmt options.
+func formatFloat(value interface{}, options []string, fieldName string) (interface{}, error) {
+	if n, ok := value.(json.Number); ok {
+		// Already formatted by the other of the two options
+		return n, nil
+	}
+
+	rv := reflect.ValueOf(value)
+
+	if rv.Kind() != reflect.Float32 && rv.Kind() != reflect.Float64 {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a float", fieldName)
+	}
+
+	prec, format := -1, byte('f')
+
+	for _, opt := range options {
+		parts := splitIgnoreQuoted(opt, ',')
+
+		if (parts[0] == "prec" || parts[0] == "fmt") && len(parts) < 2 {
+			panic(errorf(ErrInvalidTag, "mson: tag option '%s' requires an argument", parts[0]))
+		}
+
+		switch parts[0] {
+		case "prec":
+			p, err := strconv.Atoi(parts[1])
+
+			if err != nil || p < 0 {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'prec' received invalid argument %s", parts[1]))
+			}
+
+			prec = p
+		case "fmt":
+			if parts[1] != "f" && parts[1] != "e" && parts[1] != "g" {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'fmt' received invalid argument %s", parts[1]))
+			}
+
+			format = parts[1][0]
+		}
+	}
+
+	f := rv.Float()
+
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, errorf(ErrOverflow, "mson: field %s holds unsupported value %v", fieldName, f)
+	}
+
+	bits := 64
+
+	if rv.Kind() == reflect.Float32 {
+		bits = 32
+	}
+
+	return json.Number(strconv.FormatFloat(f, format, prec, bits)), nil
+}
+
+func shouldOmit(field reflect.Value, options []string, state *encodeState) (bool, error) {
+	for _, opt := range options {
+		parts := splitIgnoreQuoted(opt, ',')
+
+		switch parts[0] {
+		case "omitempty":
+			if isEmptyValue(field) {
+				return true, nil
+			}
+		case "omitzero":
+			if isZeroValue(field) {
+				return true, nil
+			}
+		case "omitif":
+			omit, err := evaluateOmitIf(field, parts[1:], state)
+
+			if err != nil || omit {
+				return omit, err
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// evaluateOmitIf reports whether all conditions of an omitif option hold.
+// Conditions are "equals,<value>" comparing the field itself, "zero", and
+// "<field>=<value>" or "<field>!=<value>" comparing a sibling field.
+func evaluateOmitIf(field reflect.Value, conditions []string, state *encodeState) (bool, error) {
+	if len(conditions) == 0 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'omitif' requires at least one condition"))
+	}
+
+	for i := 0; i < len(conditions); i++ {
+		var holds bool
+
+		switch cond := conditions[i]; {
+		case cond == "equals":
+			if i+1 >= len(conditions) {
+				panic(errorf(ErrInvalidTag, "mson: condition 'equals' of tag option 'omitif' requires a value"))
+			}
+
+			i++
+			holds = fmt.Sprint(field.Interface()) == unquote(conditions[i])
+		case cond == "zero":
+			holds = isZeroValue(field)
+		default:
+			name, want, ok := strings.Cut(cond, "=")
+
+			if !ok {
+				panic(errorf(ErrInvalidTag, "mson: invalid condition %s for tag option 'omitif'", cond))
+			}
+
+			negated := strings.HasSuffix(name, "!")
+			name = strings.TrimSuffix(name, "!")
+
+			sibling, _, found := findField(state.parent, name, &state.settings)
+
+			if !found {
+				return false, errorf(ErrFieldMissing, "mson: tag option 'omitif' references missing field %s", name)
+			}
+
+			holds = (fmt.Sprint(sibling.Interface()) == unquote(want)) != negated
+		}
+
+		if !holds {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func encodeField(buf *bytes.Buffer, field reflect.Value, metaData reflect.StructField, state *encodeState) (bool, error) {
+	fieldName, value, ok, err := fieldValue(field, metaData, state)
+
+	if !ok || err != nil {
+		return false, err
+	}
+
+	key, _ := json.Marshal(fieldName)
+	buf.Write(key)
+	buf.WriteByte(':')
+
+	return true, encodeValue(buf, reflect.ValueOf(value), state)
+}
+
+// fieldValue returns the key of a field and its value with the options of
+// its tag applied, reporting false when the field is left out.
+func fieldValue(field reflect.Value, metaData reflect.StructField, state *encodeState) (string, interface{}, bool, error) {
+	fieldName, tagOptions, ok := parseTag(metaData, &state.settings)
+
+	if !ok {
+		return "", nil, false, nil
+	}
+
+	options := fieldOptions(metaData.Type, tagOptions, &state.settings)
+
+	if omit, err := shouldOmit(field, options, state); omit || err != nil {
+		return "", nil, false, err
+	}
+
+	value, err := encodeTag(field, options, fieldName, state)
+
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	return fieldName, value, true, nil
+}
+
+func encodeStruct(buf *bytes.Buffer, rv reflect.Value, state *encodeState) error {
+	first := true
+
+	parent := state.parent
+	state.parent = rv
+	defer func() { state.parent = parent }()
+
+	buf.WriteByte('{')
+
+	for _, f := range structFields(rv.Type(), &state.settings) {
+		field, ok := fieldByIndex(rv, f.Index, false)
+
+		// The fields of a nil embedded struct are left out
+		if !ok {
+			continue
+		}
+
+		mark := buf.Len()
+
+		if !first {
+			buf.WriteByte(',')
+		}
+
+		written, err := encodeField(buf, field, f, state)
+
+		if err != nil {
+			return err
+		}
+
+		if written {
+			first = false
+		} else {
+			buf.Truncate(mark)
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return nil
+}
+
+func encodeValue(buf *bytes.Buffer, rv reflect.Value, state *encodeState) error {
+	if !rv.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+
+	if isBigType(rv.Type()) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+
+		return encodeJSON(buf, reflect.ValueOf(encodeBig(rv)))
+	}
+
+	if rv.Type().Implements(jsonMarshalerType) || rv.Type().Implements(textMarshalerType) {
+		return encodeJSON(buf, rv)
+	}
+
+	if rv.Type() == orderedMapType {
+		m := rv.Interface().(OrderedMap)
+		return encodeJSON(buf, reflect.ValueOf(&m))
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+
+		return encodeValue(buf, rv.Elem(), state)
+	case reflect.Struct:
+		return encodeStruct(buf, rv, state)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return encodeJSON(buf, rv)
+		}
+
+		buf.WriteByte('[')
+
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := encodeValue(buf, rv.Index(i), state); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte(']')
+	case reflect.Map:
+		if rv.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+
+		if rv.Type().Key().Kind() != reflect.String {
+			return encodeJSON(buf, rv)
+		}
+
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		buf.WriteByte('{')
+
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			key, _ := json.Marshal(k.String())
+			buf.Write(key)
+			buf.WriteByte(':')
+
+			if err := encodeValue(buf, rv.MapIndex(k), state); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteByte('}')
+	default:
+		return encodeJSON(buf, rv)
+	}
+
+	return nil
+}
+
+func encodeJSON(buf *bytes.Buffer, rv reflect.Value) error {
+	b, err := json.Marshal(rv.Interface())
+
+	if err != nil {
+		return errors.New(strings.Replace(err.Error(), "json", "mson", 1))
+	}
+
+	buf.Write(b)
+
+	return nil
+}
+
+// Marshal returns the JSON encoding of v, applying the options found in each
+// field's tag in reverse. Fields tagged with redact or mask are hidden in the
+// output, which makes Marshal suitable for logging structs holding secrets.
+func Marshal(v any) ([]byte, error) {
+	return MarshalWithOptions(v)
+}
+
+// MarshalWithOptions is like Marshal, but applies the given options to the
+// encode.
+func MarshalWithOptions(v any, opts ...Option) ([]byte, error) {
+	state := &encodeState{}
+
+	for _, opt := range opts {
+		opt(&state.settings)
+	}
+
+	var buf bytes.Buffer
+
+	if err := encodeValue(&buf, reflect.ValueOf(v), state); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
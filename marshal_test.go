@@ -0,0 +1,265 @@
+package mson_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+	"github.com/monerowner/mson/msontest"
+)
+
+func TestMarshalOmitEmpty(t *testing.T) {
+	type inner struct {
+		A int `json:"a"`
+	}
+
+	type document struct {
+		Status string   `json:"status"`
+		Empty  string   `json:"empty,omitempty"`
+		Nil    []int    `json:"nil,omitempty"`
+		Zero   inner    `json:"zero,omitzero"`
+		Kept   inner    `json:"kept,omitempty"`
+		Items  []string `json:"items,omitempty"`
+	}
+
+	out, err := mson.Marshal(document{Status: "draft", Items: []string{}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"status":"draft","kept":{"a":0}}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	out, err = mson.Marshal(document{Empty: "e", Zero: inner{A: 1}, Items: []string{"a"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"status":"","empty":"e","zero":{"a":1},"kept":{"a":0},"items":["a"]}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+func TestMarshalOmitIf(t *testing.T) {
+	type document struct {
+		Status string `json:"status"`
+		Draft  string `json:"draft,omitif,status=draft"`
+		Live   string `json:"live,omitif,status!=draft"`
+		Both   string `json:"both,omitif,zero,status=draft"`
+	}
+
+	out, err := mson.Marshal(document{Status: "draft", Draft: "d", Live: "l"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"status":"draft","live":"l"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	out, err = mson.Marshal(document{Status: "live", Draft: "d", Live: "l", Both: "b"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"status":"live","draft":"d","both":"b"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+func TestMarshalOmitIfMissingField(t *testing.T) {
+	var v struct {
+		A string `json:"a,omitif,missing=x"`
+	}
+
+	if _, err := mson.Marshal(v); err == nil {
+		t.Fatal("omitted a field on a condition naming a missing field")
+	}
+}
+
+func TestMarshalOmitIfEquals(t *testing.T) {
+	type document struct {
+		Default int    `json:"default,omitif,equals,10"`
+		Name    string `json:"name,omitif,equals,\"-\""`
+	}
+
+	out, err := mson.Marshal(document{Default: 10, Name: "-"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	out, err = mson.Marshal(document{Default: 11, Name: "n"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"default":11,"name":"n"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+func TestMarshalOmitIfFieldNamedLikeOption(t *testing.T) {
+	// count and sort are tag options as well as the names of fields here
+	type summary struct {
+		Count int    `json:"count"`
+		Total int    `json:"total,omitif,count=0"`
+		Order string `json:"order,omitif,equals,sort"`
+		Sort  string `json:"sort,omitif,count!=0"`
+	}
+
+	out, err := mson.Marshal(summary{Total: 5, Order: "sort", Sort: "asc"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"count":0,"sort":"asc"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+func TestMarshalRedaction(t *testing.T) {
+	type account struct {
+		Password string `json:"password,redact"`
+		Token    string `json:"token,redact,\"***\""`
+		Card     string `json:"card,mask,last4"`
+		Name     string `json:"name,mask,first1"`
+		Unset    string `json:"unset,redact"`
+	}
+
+	v := account{Password: "hunter2", Token: "t", Card: "4111111111111111", Name: "Ada"}
+	out, err := mson.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"password":"[REDACTED]","token":"***","card":"************1111","name":"A**","unset":""}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	out, err = mson.MarshalWithOptions(v, mson.WithoutRedaction())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"password":"hunter2","token":"t","card":"4111111111111111","name":"Ada","unset":""}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+func TestMarshalRedactionAfterOptions(t *testing.T) {
+	type session struct {
+		Created time.Time     `json:"created,unix,redact"`
+		Timeout time.Duration `json:"timeout,redact,duration"`
+		Expires time.Time     `json:"expires,unix,mask,last2"`
+	}
+
+	v := session{Created: time.Unix(1700000000, 0), Timeout: time.Minute, Expires: time.Unix(1700000099, 0)}
+	out, err := mson.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"created":"[REDACTED]","timeout":"[REDACTED]","expires":"********99"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+func TestMarshalFloatFormat(t *testing.T) {
+	type measurement struct {
+		Price  float64 `json:"price,prec,2"`
+		Large  float64 `json:"large,fmt,e,prec,3"`
+		Ratio  float32 `json:"ratio,fmt,g"`
+		Plain  float64 `json:"plain"`
+		Padded float64 `json:"padded,prec,3"`
+	}
+
+	out, err := mson.Marshal(measurement{Price: 3.14159, Large: 123456, Ratio: 0.1, Plain: 1e21, Padded: 2})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"price":3.14,"large":1.235e+05,"ratio":0.1,"plain":1e+21,"padded":2.000}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	if _, err := mson.Marshal(measurement{Price: math.Inf(1)}); !errors.Is(err, mson.ErrOverflow) {
+		t.Errorf("got %v, want ErrOverflow", err)
+	}
+}
+
+func TestMarshalToString(t *testing.T) {
+	type payload struct {
+		Kind string `json:"kind,snake"`
+		N    int    `json:"n"`
+	}
+
+	type envelope struct {
+		Payload payload  `json:"payload,tostring"`
+		Twice   payload  `json:"twice,tostring,2"`
+		Nil     *payload `json:"nil,tostring"`
+	}
+
+	out, err := mson.Marshal(envelope{Payload: payload{Kind: "a_b", N: 1}, Twice: payload{N: 2}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"payload":"{\"kind\":\"a_b\",\"n\":1}","twice":"\"{\\\"kind\\\":\\\"\\\",\\\"n\\\":2}\"","nil":null}`
+
+	if string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+func TestMarshalNullEmit(t *testing.T) {
+	type row struct {
+		Score int    `json:"score,null,\"N/A\",emit"`
+		Name  string `json:"name,null,\"-\""`
+	}
+
+	out, err := mson.Marshal(row{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"score":"N/A","name":""}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	var v row
+
+	if err := mson.Unmarshal([]byte(`{"score":"N/A","name":"-"}`), &v); err != nil || v != (row{}) {
+		t.Errorf("got %+v, %v", v, err)
+	}
+}
+
+func TestRoundTripArithmetic(t *testing.T) {
+	type priced struct {
+		Cents   int64   `json:"cents,multiply,100"`
+		Offset  int     `json:"offset,add,5"`
+		Percent float64 `json:"percent,divide,100"`
+		Less    int     `json:"less,subtract,1"`
+	}
+
+	msontest.RoundTrip(t, priced{Cents: 1250, Offset: 7, Percent: 0.25, Less: 9})
+}
@@ -0,0 +1,57 @@
+package mson
+
+import "reflect"
+
+// A MergePolicy controls what decoding does with the values a struct already
+// holds. The zero policy replaces them all: fields whose keys are absent are
+// zeroed and decoded values overwrite the previous ones.
+type MergePolicy struct {
+	// KeepAbsent leaves fields whose keys are absent untouched.
+	KeepAbsent bool
+
+	// KeepNonZero leaves fields other than slices and maps untouched when
+	// they already hold a non-zero value, so that a struct of defaults is
+	// only filled in where it has gaps.
+	KeepNonZero bool
+
+	// AppendSlices appends the decoded elements to those of slice fields.
+	AppendSlices bool
+
+	// MergeMaps adds the decoded entries to the map of map fields, replacing
+	// the entries of keys present in both.
+	MergeMaps bool
+}
+
+// JSONMergePolicy decodes into populated structs the way encoding/json does:
+// absent keys keep their fields and maps are merged, while slices and other
+// values are replaced.
+var JSONMergePolicy = MergePolicy{KeepAbsent: true, MergeMaps: true}
+
+// WithMergePolicy decodes into populated structs following p.
+func WithMergePolicy(p MergePolicy) Option {
+	return func(s *settings) {
+		s.merge = p
+	}
+}
+
+// mergeValue combines the previous value of a slice or map field with the one
+// just decoded into it, as the merge policy asks.
+func mergeValue(field, previous reflect.Value, p MergePolicy) {
+	// A null in the document clears the field rather than merging
+	if previous.IsNil() || field.IsNil() {
+		return
+	}
+
+	switch {
+	case field.Kind() == reflect.Slice && p.AppendSlices:
+		field.Set(reflect.AppendSlice(previous, field))
+	case field.Kind() == reflect.Map && p.MergeMaps:
+		iter := field.MapRange()
+
+		for iter.Next() {
+			previous.SetMapIndex(iter.Key(), iter.Value())
+		}
+
+		field.Set(previous)
+	}
+}
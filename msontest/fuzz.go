@@ -0,0 +1,144 @@
+// Package msontest provides helpers for testing types tagged for mson.
+package msontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+// Fuzz fuzzes decoding into the struct type of v, which must be a struct or a
+// pointer to one, with a Codec made of opts. The corpus is seeded with
+// documents shaped after the type's keys, holding a value of every JSON kind
+// under each key. Every input must decode without panicking, decoding it
+// twice must give the same value and error, and a successful decode must
+// marshal without panicking.
+//
+// Options that read the current time get FuzzTime, so that decoding twice
+// gives the same value; pass mson.WithClock to choose another time.
+//
+// Call it from a fuzz test:
+//
+//	func FuzzEvent(f *testing.F) { msontest.Fuzz(f, Event{}) }
+func Fuzz(f *testing.F, v any, opts ...mson.Option) {
+	f.Helper()
+
+	rt := structType(f, v)
+	codec := mson.NewCodec(append([]mson.Option{mson.WithClock(func() time.Time { return FuzzTime })}, opts...)...)
+
+	keys, err := fieldKeys(v, opts)
+
+	if err != nil {
+		f.Fatalf("msontest: %v", err)
+	}
+
+	for _, seed := range seeds(keys) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		first, err1 := decode(t, codec, rt, data)
+		second, err2 := decode(t, codec, rt, data)
+
+		if fmt.Sprint(err1) != fmt.Sprint(err2) {
+			t.Fatalf("decoding %s twice returned different errors: %v, then %v", data, err1, err2)
+		}
+
+		if err1 != nil {
+			return
+		}
+
+		if !reflect.DeepEqual(first, second) {
+			t.Fatalf("decoding %s twice returned different values: %+v, then %+v", data, first, second)
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Fatalf("marshaling the value decoded from %s panicked: %v", data, r)
+				}
+			}()
+
+			codec.Marshal(first)
+		}()
+	})
+}
+
+func structType(tb testing.TB, v any) reflect.Type {
+	tb.Helper()
+
+	rt := reflect.TypeOf(v)
+
+	for rt != nil && rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+
+	if rt == nil || rt.Kind() != reflect.Struct {
+		tb.Fatalf("msontest: %T is not a struct or a pointer to one", v)
+	}
+
+	return rt
+}
+
+func decode(t *testing.T, codec *mson.Codec, rt reflect.Type, data []byte) (v any, err error) {
+	t.Helper()
+
+	ptr := reflect.New(rt)
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("decoding %s panicked: %v", data, r)
+		}
+	}()
+
+	err = codec.Unmarshal(data, ptr.Interface())
+
+	return ptr.Elem().Interface(), err
+}
+
+// FuzzTime is the time Fuzz gives to options that read the current time.
+var FuzzTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+var sampleValues = []interface{}{
+	nil, true, 0, -1, 1.5, 1700000000, "", "a", "1", "{}", []interface{}{}, []interface{}{1, "a"}, map[string]interface{}{},
+}
+
+// seeds returns documents holding each sample value under every key.
+func seeds(keys []string) [][]byte {
+	docs := [][]byte{[]byte("{}")}
+
+	for _, sample := range sampleValues {
+		doc := make(map[string]interface{}, len(keys))
+
+		for _, k := range keys {
+			doc[k] = sample
+		}
+
+		if data, err := json.Marshal(doc); err == nil {
+			docs = append(docs, data)
+		}
+	}
+
+	return docs
+}
+
+// fieldKeys returns the keys of the fields mson decodes into v with opts.
+func fieldKeys(v any, opts []mson.Option) ([]string, error) {
+	fields, err := mson.Fields(v, opts...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(fields))
+
+	for i, f := range fields {
+		keys[i] = f.Key
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,136 @@
+package msontest
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+// RoundTrip marshals v with a Codec made of opts, unmarshals the document into
+// a new value of the same type and marshals that again, failing t unless both
+// documents are equal. Each key whose value changed, or whose value cannot be
+// unmarshaled at all, is reported along with the tag of its field, since
+// either means one of the field's options is not invertible.
+func RoundTrip(t testing.TB, v any, opts ...mson.Option) {
+	t.Helper()
+
+	rt := structType(t, v)
+	codec := mson.NewCodec(opts...)
+	tags := fieldTags(v, opts)
+
+	first, err := codec.Marshal(v)
+
+	if err != nil {
+		t.Fatalf("msontest: marshaling %T failed: %v", v, err)
+	}
+
+	ptr := reflect.New(rt)
+
+	if err := codec.Unmarshal(first, ptr.Interface()); err != nil {
+		reportUndecodable(t, codec, rt, first, tags, err)
+		return
+	}
+
+	second, err := codec.Marshal(ptr.Interface())
+
+	if err != nil {
+		t.Fatalf("msontest: marshaling the decoded %v failed: %v", rt, err)
+	}
+
+	var before, after map[string]interface{}
+
+	if err := json.Unmarshal(first, &before); err != nil {
+		t.Fatalf("msontest: marshaling %T did not produce an object: %s", v, first)
+	}
+
+	if err := json.Unmarshal(second, &after); err != nil {
+		t.Fatalf("msontest: marshaling the decoded %v did not produce an object: %s", rt, second)
+	}
+
+	for _, k := range changedKeys(before, after) {
+		t.Errorf("msontest: key %s of %v does not survive a round trip (tag %q): %v became %v", k, rt, tags[k], describe(before, k), describe(after, k))
+	}
+}
+
+// reportUndecodable reports the keys of doc that fail to unmarshal into rt on
+// their own, falling back to err when the keys only fail together.
+func reportUndecodable(t testing.TB, codec *mson.Codec, rt reflect.Type, doc []byte, tags map[string]string, err error) {
+	t.Helper()
+
+	var values map[string]json.RawMessage
+
+	if json.Unmarshal(doc, &values) != nil {
+		t.Errorf("msontest: unmarshaling %s into %v failed: %v", doc, rt, err)
+		return
+	}
+
+	keys := make([]string, 0, len(values))
+
+	for k := range values {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	reported := false
+
+	for _, k := range keys {
+		single, _ := json.Marshal(map[string]json.RawMessage{k: values[k]})
+
+		if err := codec.Unmarshal(single, reflect.New(rt).Interface()); err != nil {
+			t.Errorf("msontest: key %s of %v does not survive a round trip (tag %q): %s cannot be unmarshaled: %v", k, rt, tags[k], values[k], err)
+			reported = true
+		}
+	}
+
+	if !reported {
+		t.Errorf("msontest: unmarshaling %s into %v failed: %v", doc, rt, err)
+	}
+}
+
+func changedKeys(before, after map[string]interface{}) []string {
+	var changed []string
+
+	for k, v := range before {
+		if w, ok := after[k]; !ok || !reflect.DeepEqual(v, w) {
+			changed = append(changed, k)
+		}
+	}
+
+	for k := range after {
+		if _, ok := before[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+
+	sort.Strings(changed)
+
+	return changed
+}
+
+func describe(doc map[string]interface{}, k string) string {
+	v, ok := doc[k]
+
+	if !ok {
+		return "<absent>"
+	}
+
+	data, _ := json.Marshal(v)
+
+	return string(data)
+}
+
+// fieldTags maps the keys of v's fields to the tags they came from.
+func fieldTags(v any, opts []mson.Option) map[string]string {
+	tags := map[string]string{}
+	fields, _ := mson.Fields(v, opts...)
+
+	for _, f := range fields {
+		tags[f.Key] = f.Tag
+	}
+
+	return tags
+}
@@ -0,0 +1,206 @@
+package mson
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	hardwareAddrType = reflect.TypeOf(net.HardwareAddr{})
+	mac48Type        = reflect.TypeOf([6]byte{})
+)
+
+// parseMAC parses a MAC address written with colons, dashes or dots into the
+// field's type: net.HardwareAddr, [6]byte, or a string in the canonical
+// lowercase colon-separated form.
+func parseMAC(value interface{}, target reflect.Type, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	addr, err := net.ParseMAC(s)
+
+	if err != nil {
+		return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must be a MAC address"}
+	}
+
+	switch target {
+	case hardwareAddrType:
+		return addr, nil
+	case mac48Type:
+		if len(addr) != 6 {
+			return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must be a 48-bit MAC address"}
+		}
+
+		var a [6]byte
+		copy(a[:], addr)
+
+		return a, nil
+	}
+
+	if target.Kind() == reflect.String {
+		return addr.String(), nil
+	}
+
+	return nil, errorf(ErrTypeMismatch, "mson: cannot convert field %s to a MAC address; field is of type %s", fieldName, target)
+}
+
+// formatMAC is the inverse of parseMAC.
+func formatMAC(value interface{}) interface{} {
+	switch v := value.(type) {
+	case net.HardwareAddr:
+		if v == nil {
+			return nil
+		}
+
+		return v.String()
+	case [6]byte:
+		return net.HardwareAddr(v[:]).String()
+	}
+
+	return value
+}
+
+// A HostPort is a network address split into its host and port, as decoded
+// by the hostport tag option.
+type HostPort struct {
+	Host string
+	Port uint16
+}
+
+// String joins the host and port, bracketing IPv6 hosts.
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(int(hp.Port)))
+}
+
+var hostPortType = reflect.TypeOf(HostPort{})
+
+// parsePort validates a port number between 1 and 65535, sent as a number or
+// a string.
+func parsePort(value interface{}, fieldName string) (uint16, error) {
+	var s string
+
+	switch v := value.(type) {
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		s = v
+	default:
+		return 0, errorf(ErrTypeMismatch, "mson: field %s is not a number or a string", fieldName)
+	}
+
+	port, err := strconv.ParseUint(s, 10, 16)
+
+	if err != nil || port == 0 {
+		return 0, &ValidationError{Field: fieldName, Value: s, Reason: "must be a port between 1 and 65535"}
+	}
+
+	return uint16(port), nil
+}
+
+func convertPort(value interface{}, target reflect.Type, fieldName string) (interface{}, error) {
+	port, err := parsePort(value, fieldName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch target.Kind() {
+	case reflect.String:
+		return strconv.Itoa(int(port)), nil
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return reflect.ValueOf(port).Convert(target).Interface(), nil
+	}
+
+	return nil, errorf(ErrTypeMismatch, "mson: cannot convert field %s to a port; field is of type %s", fieldName, target)
+}
+
+// parseHostPort splits host:port, including bracketed IPv6 hosts, into a
+// HostPort, or checks it and keeps it whole for string fields.
+func parseHostPort(value interface{}, target reflect.Type, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	host, portString, err := net.SplitHostPort(s)
+
+	if err != nil {
+		return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must have the form host:port"}
+	}
+
+	port, err := parsePort(portString, fieldName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hp := HostPort{Host: host, Port: port}
+
+	switch {
+	case target == hostPortType:
+		return hp, nil
+	case target.Kind() == reflect.String:
+		return hp.String(), nil
+	}
+
+	return nil, errorf(ErrTypeMismatch, "mson: cannot convert field %s to a host and port; field is of type %s", fieldName, target)
+}
+
+// validateHostname checks an RFC 1123 hostname and returns it lowercased
+// without a trailing dot. The fqdn argument requires at least two labels, and
+// wildcard allows a leading *. label.
+func validateHostname(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	name := strings.ToLower(strings.TrimSuffix(s, "."))
+
+	if containsOption(parts[1:], "wildcard") {
+		name = strings.TrimPrefix(name, "*.")
+	}
+
+	labels := strings.Split(name, ".")
+
+	if name == "" || len(name) > 253 {
+		return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must be a hostname"}
+	}
+
+	for _, label := range labels {
+		if !validLabel(label) {
+			return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must be a hostname"}
+		}
+	}
+
+	if containsOption(parts[1:], "fqdn") && len(labels) < 2 {
+		return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must be a fully qualified domain name"}
+	}
+
+	return strings.ToLower(strings.TrimSuffix(s, ".")), nil
+}
+
+// validLabel reports whether label is 1 to 63 letters, digits and hyphens,
+// neither starting nor ending with a hyphen.
+func validLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
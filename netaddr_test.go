@@ -0,0 +1,170 @@
+package mson_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/monerowner/mson"
+	"github.com/monerowner/mson/msontest"
+)
+
+type endpoint struct {
+	MAC      net.HardwareAddr `json:"mac,mac"`
+	Array    [6]byte          `json:"array,mac"`
+	Text     string           `json:"text,mac"`
+	Port     uint16           `json:"port,port"`
+	PortText string           `json:"port_text,port"`
+	Addr     mson.HostPort    `json:"addr,hostport"`
+	Joined   string           `json:"joined,hostport"`
+	Host     string           `json:"host,hostname"`
+	Domain   string           `json:"domain,hostname,fqdn"`
+	Wildcard string           `json:"wildcard,hostname,wildcard"`
+}
+
+func TestMACOption(t *testing.T) {
+	var v struct {
+		MAC   net.HardwareAddr `json:"mac,mac"`
+		Array [6]byte          `json:"array,mac"`
+		Text  string           `json:"text,mac"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"mac":"00-1A-2b-3c-4D-5e","array":"001a.2b3c.4d5e","text":"00:1A:2B:3C:4D:5E"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.MAC.String() != "00:1a:2b:3c:4d:5e" || v.Array != [6]byte{0, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e} || v.Text != "00:1a:2b:3c:4d:5e" {
+		t.Errorf("got MAC addresses %v, %v and %q", v.MAC, v.Array, v.Text)
+	}
+
+	var validationErr *mson.ValidationError
+
+	for _, data := range []string{`{"mac":"00:1a:2b"}`, `{"array":"00:00:5e:00:53:01:02:03"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); !errors.As(err, &validationErr) {
+			t.Errorf("decoding %s returned %v, want a ValidationError", data, err)
+		}
+	}
+}
+
+func TestPortOptions(t *testing.T) {
+	var v struct {
+		Port     uint16        `json:"port,port"`
+		PortText string        `json:"port_text,port"`
+		Addr     mson.HostPort `json:"addr,hostport"`
+		Joined   string        `json:"joined,hostport"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"port":8080,"port_text":"443","addr":"[::1]:9000","joined":"example.com:80"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Port != 8080 || v.PortText != "443" {
+		t.Errorf("got ports %d and %q", v.Port, v.PortText)
+	}
+
+	if v.Addr != (mson.HostPort{Host: "::1", Port: 9000}) || v.Addr.String() != "[::1]:9000" || v.Joined != "example.com:80" {
+		t.Errorf("got addresses %+v and %q", v.Addr, v.Joined)
+	}
+
+	var validationErr *mson.ValidationError
+
+	for _, data := range []string{`{"port":0}`, `{"port":65536}`, `{"port_text":"http"}`, `{"addr":"example.com"}`, `{"joined":"example.com:0"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); !errors.As(err, &validationErr) {
+			t.Errorf("decoding %s returned %v, want a ValidationError", data, err)
+		}
+	}
+}
+
+func TestHostnameOption(t *testing.T) {
+	var v struct {
+		Host     string `json:"host,hostname"`
+		Domain   string `json:"domain,hostname,fqdn"`
+		Wildcard string `json:"wildcard,hostname,wildcard"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"host":"LocalHost.","domain":"Example.COM","wildcard":"*.example.com"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Host != "localhost" || v.Domain != "example.com" || v.Wildcard != "*.example.com" {
+		t.Errorf("got hostnames %q, %q and %q", v.Host, v.Domain, v.Wildcard)
+	}
+
+	var validationErr *mson.ValidationError
+
+	for _, data := range []string{`{"host":"-bad-.example.com"}`, `{"host":"under_score"}`, `{"domain":"localhost"}`, `{"host":"*.example.com"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); !errors.As(err, &validationErr) {
+			t.Errorf("decoding %s returned %v, want a ValidationError", data, err)
+		}
+	}
+}
+
+func TestRoundTripNetworkOptions(t *testing.T) {
+	msontest.RoundTrip(t, endpoint{
+		MAC:      net.HardwareAddr{0, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e},
+		Array:    [6]byte{1, 2, 3, 4, 5, 6},
+		Text:     "00:1a:2b:3c:4d:5e",
+		Port:     22,
+		PortText: "22",
+		Addr:     mson.HostPort{Host: "example.com", Port: 443},
+		Joined:   "[::1]:80",
+		Host:     "localhost",
+		Domain:   "example.com",
+		Wildcard: "*.example.com",
+	})
+}
+
+func TestEmailOption(t *testing.T) {
+	type contact struct {
+		Email  string `json:"email,email"`
+		NoPlus string `json:"noplus,email,noplus"`
+		Strict string `json:"strict,email,strict"`
+	}
+
+	var v contact
+
+	if err := mson.Unmarshal([]byte(`{"email":"Ada <Ada@Example.COM>","noplus":"ada+news@example.com","strict":"ada@example.com"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v != (contact{Email: "Ada@example.com", NoPlus: "ada@example.com", Strict: "ada@example.com"}) {
+		t.Fatalf("got %+v", v)
+	}
+
+	var validationErr *mson.ValidationError
+
+	for _, data := range []string{`{"email":"not an address"}`, `{"strict":"Ada <ada@example.com>"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); !errors.As(err, &validationErr) {
+			t.Errorf("decoding %s returned %v, want a ValidationError", data, err)
+		}
+	}
+}
+
+func TestUploadOptions(t *testing.T) {
+	type upload struct {
+		Type string `json:"type,mime,\"image/*|application/pdf\""`
+		Name string `json:"name,ext,\"jpg|.PNG\""`
+	}
+
+	var v upload
+
+	if err := mson.Unmarshal([]byte(`{"type":"image/png; charset=binary","name":"Cat.png"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Type != "image/png; charset=binary" || v.Name != "Cat.png" {
+		t.Fatalf("got %+v", v)
+	}
+
+	var validationErr *mson.ValidationError
+
+	for _, data := range []string{`{"type":"text/html"}`, `{"type":"image"}`, `{"name":"cat.gif"}`, `{"name":"jpg"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); !errors.As(err, &validationErr) {
+			t.Errorf("decoding %s returned %v, want a ValidationError", data, err)
+		}
+	}
+}
+
+func FuzzNetworkOptions(f *testing.F) {
+	msontest.Fuzz(f, endpoint{})
+}
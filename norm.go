@@ -0,0 +1,155 @@
+package mson
+
+import (
+	"fmt"
+	"sync"
+	"unicode/utf8"
+)
+
+// A UnicodeNormalizer converts a string into the Unicode normalization form
+// nfc, nfd, nfkc or nfkd, as requested by the norm tag option. The built-in
+// normalizer only knows the Latin letters and compatibility characters of the
+// first Unicode blocks and leaves other text unchanged; register one backed by
+// golang.org/x/text/unicode/norm for full coverage. A UnicodeNormalizer must
+// be safe for concurrent use.
+type UnicodeNormalizer interface {
+	NormalizeString(form, s string) (string, error)
+}
+
+var (
+	normMu            sync.RWMutex
+	unicodeNormalizer UnicodeNormalizer = basicUnicodeNormalizer{}
+)
+
+// RegisterUnicodeNormalizer replaces the normalizer used by the norm tag
+// option. Passing nil restores the built-in normalizer.
+func RegisterUnicodeNormalizer(n UnicodeNormalizer) {
+	normMu.Lock()
+	defer normMu.Unlock()
+
+	if n == nil {
+		n = basicUnicodeNormalizer{}
+	}
+
+	unicodeNormalizer = n
+}
+
+var latinDecompositions = func() map[rune][2]rune {
+	m := make(map[rune][2]rune, len(latinCompositions))
+
+	for pair, r := range latinCompositions {
+		m[r] = pair
+	}
+
+	return m
+}()
+
+type basicUnicodeNormalizer struct{}
+
+func (basicUnicodeNormalizer) NormalizeString(form, s string) (string, error) {
+	var compat, compose bool
+
+	switch form {
+	case "nfc":
+		compose = true
+	case "nfd":
+	case "nfkc":
+		compat, compose = true, true
+	case "nfkd":
+		compat = true
+	default:
+		return "", fmt.Errorf("unknown normalization form %s", form)
+	}
+
+	if isASCII(s) {
+		// ASCII text is the same in every form
+		return s, nil
+	}
+
+	var decomposed []rune
+
+	for _, r := range s {
+		decomposed = decomposeRune(decomposed, r, compat)
+	}
+
+	if !compose {
+		return string(decomposed), nil
+	}
+
+	composed := decomposed[:0]
+
+	for _, r := range decomposed {
+		if n := len(composed); n > 0 {
+			if c, ok := latinCompositions[[2]rune{composed[n-1], r}]; ok {
+				composed[n-1] = c
+				continue
+			}
+		}
+
+		composed = append(composed, r)
+	}
+
+	return string(composed), nil
+}
+
+func decomposeRune(dst []rune, r rune, compat bool) []rune {
+	if compat {
+		switch {
+		case r >= 0xFF01 && r <= 0xFF5E:
+			// Fullwidth forms of the printable ASCII characters
+			return append(dst, r-0xFEE0)
+		case r == 0x3000:
+			return append(dst, ' ')
+		}
+
+		if s, ok := latinCompatibility[r]; ok {
+			for _, c := range s {
+				dst = decomposeRune(dst, c, compat)
+			}
+
+			return dst
+		}
+	}
+
+	if pair, ok := latinDecompositions[r]; ok {
+		return append(decomposeRune(dst, pair[0], compat), pair[1])
+	}
+
+	return append(dst, r)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+func normalizeString(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	form := "nfc"
+
+	if len(parts) > 1 {
+		form = parts[1]
+	}
+
+	normMu.RLock()
+	n := unicodeNormalizer
+	normMu.RUnlock()
+
+	normalized, err := n.NormalizeString(form, s)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, normalization of field %s failed", err, fieldName)
+	}
+
+	return normalized, nil
+}
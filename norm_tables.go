@@ -0,0 +1,304 @@
+package mson
+
+// Tables generated from the Unicode Character Database for the Latin-1
+// Supplement, Latin Extended-A and Latin Extended-B blocks.
+
+// latinCompositions maps a base letter and a combining mark to the letter that
+// composes them canonically.
+var latinCompositions = map[[2]rune]rune{
+	{0x0041, 0x0300}: 0x00C0,
+	{0x0041, 0x0301}: 0x00C1,
+	{0x0041, 0x0302}: 0x00C2,
+	{0x0041, 0x0303}: 0x00C3,
+	{0x0041, 0x0308}: 0x00C4,
+	{0x0041, 0x030A}: 0x00C5,
+	{0x0043, 0x0327}: 0x00C7,
+	{0x0045, 0x0300}: 0x00C8,
+	{0x0045, 0x0301}: 0x00C9,
+	{0x0045, 0x0302}: 0x00CA,
+	{0x0045, 0x0308}: 0x00CB,
+	{0x0049, 0x0300}: 0x00CC,
+	{0x0049, 0x0301}: 0x00CD,
+	{0x0049, 0x0302}: 0x00CE,
+	{0x0049, 0x0308}: 0x00CF,
+	{0x004E, 0x0303}: 0x00D1,
+	{0x004F, 0x0300}: 0x00D2,
+	{0x004F, 0x0301}: 0x00D3,
+	{0x004F, 0x0302}: 0x00D4,
+	{0x004F, 0x0303}: 0x00D5,
+	{0x004F, 0x0308}: 0x00D6,
+	{0x0055, 0x0300}: 0x00D9,
+	{0x0055, 0x0301}: 0x00DA,
+	{0x0055, 0x0302}: 0x00DB,
+	{0x0055, 0x0308}: 0x00DC,
+	{0x0059, 0x0301}: 0x00DD,
+	{0x0061, 0x0300}: 0x00E0,
+	{0x0061, 0x0301}: 0x00E1,
+	{0x0061, 0x0302}: 0x00E2,
+	{0x0061, 0x0303}: 0x00E3,
+	{0x0061, 0x0308}: 0x00E4,
+	{0x0061, 0x030A}: 0x00E5,
+	{0x0063, 0x0327}: 0x00E7,
+	{0x0065, 0x0300}: 0x00E8,
+	{0x0065, 0x0301}: 0x00E9,
+	{0x0065, 0x0302}: 0x00EA,
+	{0x0065, 0x0308}: 0x00EB,
+	{0x0069, 0x0300}: 0x00EC,
+	{0x0069, 0x0301}: 0x00ED,
+	{0x0069, 0x0302}: 0x00EE,
+	{0x0069, 0x0308}: 0x00EF,
+	{0x006E, 0x0303}: 0x00F1,
+	{0x006F, 0x0300}: 0x00F2,
+	{0x006F, 0x0301}: 0x00F3,
+	{0x006F, 0x0302}: 0x00F4,
+	{0x006F, 0x0303}: 0x00F5,
+	{0x006F, 0x0308}: 0x00F6,
+	{0x0075, 0x0300}: 0x00F9,
+	{0x0075, 0x0301}: 0x00FA,
+	{0x0075, 0x0302}: 0x00FB,
+	{0x0075, 0x0308}: 0x00FC,
+	{0x0079, 0x0301}: 0x00FD,
+	{0x0079, 0x0308}: 0x00FF,
+	{0x0041, 0x0304}: 0x0100,
+	{0x0061, 0x0304}: 0x0101,
+	{0x0041, 0x0306}: 0x0102,
+	{0x0061, 0x0306}: 0x0103,
+	{0x0041, 0x0328}: 0x0104,
+	{0x0061, 0x0328}: 0x0105,
+	{0x0043, 0x0301}: 0x0106,
+	{0x0063, 0x0301}: 0x0107,
+	{0x0043, 0x0302}: 0x0108,
+	{0x0063, 0x0302}: 0x0109,
+	{0x0043, 0x0307}: 0x010A,
+	{0x0063, 0x0307}: 0x010B,
+	{0x0043, 0x030C}: 0x010C,
+	{0x0063, 0x030C}: 0x010D,
+	{0x0044, 0x030C}: 0x010E,
+	{0x0064, 0x030C}: 0x010F,
+	{0x0045, 0x0304}: 0x0112,
+	{0x0065, 0x0304}: 0x0113,
+	{0x0045, 0x0306}: 0x0114,
+	{0x0065, 0x0306}: 0x0115,
+	{0x0045, 0x0307}: 0x0116,
+	{0x0065, 0x0307}: 0x0117,
+	{0x0045, 0x0328}: 0x0118,
+	{0x0065, 0x0328}: 0x0119,
+	{0x0045, 0x030C}: 0x011A,
+	{0x0065, 0x030C}: 0x011B,
+	{0x0047, 0x0302}: 0x011C,
+	{0x0067, 0x0302}: 0x011D,
+	{0x0047, 0x0306}: 0x011E,
+	{0x0067, 0x0306}: 0x011F,
+	{0x0047, 0x0307}: 0x0120,
+	{0x0067, 0x0307}: 0x0121,
+	{0x0047, 0x0327}: 0x0122,
+	{0x0067, 0x0327}: 0x0123,
+	{0x0048, 0x0302}: 0x0124,
+	{0x0068, 0x0302}: 0x0125,
+	{0x0049, 0x0303}: 0x0128,
+	{0x0069, 0x0303}: 0x0129,
+	{0x0049, 0x0304}: 0x012A,
+	{0x0069, 0x0304}: 0x012B,
+	{0x0049, 0x0306}: 0x012C,
+	{0x0069, 0x0306}: 0x012D,
+	{0x0049, 0x0328}: 0x012E,
+	{0x0069, 0x0328}: 0x012F,
+	{0x0049, 0x0307}: 0x0130,
+	{0x004A, 0x0302}: 0x0134,
+	{0x006A, 0x0302}: 0x0135,
+	{0x004B, 0x0327}: 0x0136,
+	{0x006B, 0x0327}: 0x0137,
+	{0x004C, 0x0301}: 0x0139,
+	{0x006C, 0x0301}: 0x013A,
+	{0x004C, 0x0327}: 0x013B,
+	{0x006C, 0x0327}: 0x013C,
+	{0x004C, 0x030C}: 0x013D,
+	{0x006C, 0x030C}: 0x013E,
+	{0x004E, 0x0301}: 0x0143,
+	{0x006E, 0x0301}: 0x0144,
+	{0x004E, 0x0327}: 0x0145,
+	{0x006E, 0x0327}: 0x0146,
+	{0x004E, 0x030C}: 0x0147,
+	{0x006E, 0x030C}: 0x0148,
+	{0x004F, 0x0304}: 0x014C,
+	{0x006F, 0x0304}: 0x014D,
+	{0x004F, 0x0306}: 0x014E,
+	{0x006F, 0x0306}: 0x014F,
+	{0x004F, 0x030B}: 0x0150,
+	{0x006F, 0x030B}: 0x0151,
+	{0x0052, 0x0301}: 0x0154,
+	{0x0072, 0x0301}: 0x0155,
+	{0x0052, 0x0327}: 0x0156,
+	{0x0072, 0x0327}: 0x0157,
+	{0x0052, 0x030C}: 0x0158,
+	{0x0072, 0x030C}: 0x0159,
+	{0x0053, 0x0301}: 0x015A,
+	{0x0073, 0x0301}: 0x015B,
+	{0x0053, 0x0302}: 0x015C,
+	{0x0073, 0x0302}: 0x015D,
+	{0x0053, 0x0327}: 0x015E,
+	{0x0073, 0x0327}: 0x015F,
+	{0x0053, 0x030C}: 0x0160,
+	{0x0073, 0x030C}: 0x0161,
+	{0x0054, 0x0327}: 0x0162,
+	{0x0074, 0x0327}: 0x0163,
+	{0x0054, 0x030C}: 0x0164,
+	{0x0074, 0x030C}: 0x0165,
+	{0x0055, 0x0303}: 0x0168,
+	{0x0075, 0x0303}: 0x0169,
+	{0x0055, 0x0304}: 0x016A,
+	{0x0075, 0x0304}: 0x016B,
+	{0x0055, 0x0306}: 0x016C,
+	{0x0075, 0x0306}: 0x016D,
+	{0x0055, 0x030A}: 0x016E,
+	{0x0075, 0x030A}: 0x016F,
+	{0x0055, 0x030B}: 0x0170,
+	{0x0075, 0x030B}: 0x0171,
+	{0x0055, 0x0328}: 0x0172,
+	{0x0075, 0x0328}: 0x0173,
+	{0x0057, 0x0302}: 0x0174,
+	{0x0077, 0x0302}: 0x0175,
+	{0x0059, 0x0302}: 0x0176,
+	{0x0079, 0x0302}: 0x0177,
+	{0x0059, 0x0308}: 0x0178,
+	{0x005A, 0x0301}: 0x0179,
+	{0x007A, 0x0301}: 0x017A,
+	{0x005A, 0x0307}: 0x017B,
+	{0x007A, 0x0307}: 0x017C,
+	{0x005A, 0x030C}: 0x017D,
+	{0x007A, 0x030C}: 0x017E,
+	{0x004F, 0x031B}: 0x01A0,
+	{0x006F, 0x031B}: 0x01A1,
+	{0x0055, 0x031B}: 0x01AF,
+	{0x0075, 0x031B}: 0x01B0,
+	{0x0041, 0x030C}: 0x01CD,
+	{0x0061, 0x030C}: 0x01CE,
+	{0x0049, 0x030C}: 0x01CF,
+	{0x0069, 0x030C}: 0x01D0,
+	{0x004F, 0x030C}: 0x01D1,
+	{0x006F, 0x030C}: 0x01D2,
+	{0x0055, 0x030C}: 0x01D3,
+	{0x0075, 0x030C}: 0x01D4,
+	{0x00DC, 0x0304}: 0x01D5,
+	{0x00FC, 0x0304}: 0x01D6,
+	{0x00DC, 0x0301}: 0x01D7,
+	{0x00FC, 0x0301}: 0x01D8,
+	{0x00DC, 0x030C}: 0x01D9,
+	{0x00FC, 0x030C}: 0x01DA,
+	{0x00DC, 0x0300}: 0x01DB,
+	{0x00FC, 0x0300}: 0x01DC,
+	{0x00C4, 0x0304}: 0x01DE,
+	{0x00E4, 0x0304}: 0x01DF,
+	{0x0226, 0x0304}: 0x01E0,
+	{0x0227, 0x0304}: 0x01E1,
+	{0x00C6, 0x0304}: 0x01E2,
+	{0x00E6, 0x0304}: 0x01E3,
+	{0x0047, 0x030C}: 0x01E6,
+	{0x0067, 0x030C}: 0x01E7,
+	{0x004B, 0x030C}: 0x01E8,
+	{0x006B, 0x030C}: 0x01E9,
+	{0x004F, 0x0328}: 0x01EA,
+	{0x006F, 0x0328}: 0x01EB,
+	{0x01EA, 0x0304}: 0x01EC,
+	{0x01EB, 0x0304}: 0x01ED,
+	{0x01B7, 0x030C}: 0x01EE,
+	{0x0292, 0x030C}: 0x01EF,
+	{0x006A, 0x030C}: 0x01F0,
+	{0x0047, 0x0301}: 0x01F4,
+	{0x0067, 0x0301}: 0x01F5,
+	{0x004E, 0x0300}: 0x01F8,
+	{0x006E, 0x0300}: 0x01F9,
+	{0x00C5, 0x0301}: 0x01FA,
+	{0x00E5, 0x0301}: 0x01FB,
+	{0x00C6, 0x0301}: 0x01FC,
+	{0x00E6, 0x0301}: 0x01FD,
+	{0x00D8, 0x0301}: 0x01FE,
+	{0x00F8, 0x0301}: 0x01FF,
+	{0x0041, 0x030F}: 0x0200,
+	{0x0061, 0x030F}: 0x0201,
+	{0x0041, 0x0311}: 0x0202,
+	{0x0061, 0x0311}: 0x0203,
+	{0x0045, 0x030F}: 0x0204,
+	{0x0065, 0x030F}: 0x0205,
+	{0x0045, 0x0311}: 0x0206,
+	{0x0065, 0x0311}: 0x0207,
+	{0x0049, 0x030F}: 0x0208,
+	{0x0069, 0x030F}: 0x0209,
+	{0x0049, 0x0311}: 0x020A,
+	{0x0069, 0x0311}: 0x020B,
+	{0x004F, 0x030F}: 0x020C,
+	{0x006F, 0x030F}: 0x020D,
+	{0x004F, 0x0311}: 0x020E,
+	{0x006F, 0x0311}: 0x020F,
+	{0x0052, 0x030F}: 0x0210,
+	{0x0072, 0x030F}: 0x0211,
+	{0x0052, 0x0311}: 0x0212,
+	{0x0072, 0x0311}: 0x0213,
+	{0x0055, 0x030F}: 0x0214,
+	{0x0075, 0x030F}: 0x0215,
+	{0x0055, 0x0311}: 0x0216,
+	{0x0075, 0x0311}: 0x0217,
+	{0x0053, 0x0326}: 0x0218,
+	{0x0073, 0x0326}: 0x0219,
+	{0x0054, 0x0326}: 0x021A,
+	{0x0074, 0x0326}: 0x021B,
+	{0x0048, 0x030C}: 0x021E,
+	{0x0068, 0x030C}: 0x021F,
+	{0x0041, 0x0307}: 0x0226,
+	{0x0061, 0x0307}: 0x0227,
+	{0x0045, 0x0327}: 0x0228,
+	{0x0065, 0x0327}: 0x0229,
+	{0x00D6, 0x0304}: 0x022A,
+	{0x00F6, 0x0304}: 0x022B,
+	{0x00D5, 0x0304}: 0x022C,
+	{0x00F5, 0x0304}: 0x022D,
+	{0x004F, 0x0307}: 0x022E,
+	{0x006F, 0x0307}: 0x022F,
+	{0x022E, 0x0304}: 0x0230,
+	{0x022F, 0x0304}: 0x0231,
+	{0x0059, 0x0304}: 0x0232,
+	{0x0079, 0x0304}: 0x0233,
+}
+
+// latinCompatibility maps characters to their compatibility decompositions.
+var latinCompatibility = map[rune]string{
+	0x00A0: " ",
+	0x00A8: " \u0308",
+	0x00AA: "a",
+	0x00AF: " \u0304",
+	0x00B2: "2",
+	0x00B3: "3",
+	0x00B4: " \u0301",
+	0x00B5: "\u03BC",
+	0x00B8: " \u0327",
+	0x00B9: "1",
+	0x00BA: "o",
+	0x00BC: "1\u20444",
+	0x00BD: "1\u20442",
+	0x00BE: "3\u20444",
+	0x0132: "IJ",
+	0x0133: "ij",
+	0x013F: "L\u00B7",
+	0x0140: "l\u00B7",
+	0x0149: "\u02BCn",
+	0x017F: "s",
+	0x01C4: "D\u017D",
+	0x01C5: "D\u017E",
+	0x01C6: "d\u017E",
+	0x01C7: "LJ",
+	0x01C8: "Lj",
+	0x01C9: "lj",
+	0x01CA: "NJ",
+	0x01CB: "Nj",
+	0x01CC: "nj",
+	0x01F1: "DZ",
+	0x01F2: "Dz",
+	0x01F3: "dz",
+	0xFB00: "ff",
+	0xFB01: "fi",
+	0xFB02: "fl",
+	0xFB03: "ffi",
+	0xFB04: "ffl",
+	0xFB05: "st",
+	0xFB06: "st",
+}
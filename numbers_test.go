@@ -0,0 +1,179 @@
+package mson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/monerowner/mson"
+	"github.com/monerowner/mson/msontest"
+)
+
+func TestUnmarshalLargeIntegers(t *testing.T) {
+	// deprecated sends the fields through the general path without reading
+	// their values
+	type ids struct {
+		Signed   int64  `json:"signed,deprecated"`
+		Unsigned uint64 `json:"unsigned,deprecated"`
+		Pointer  *int64 `json:"pointer,deprecated"`
+		List     []int64
+		Map      map[string]uint64
+	}
+
+	data := []byte(`{"signed":9007199254740993,"unsigned":9223372036854775809,"pointer":-9007199254740993,"List":[9007199254740993],"Map":{"a":18446744073709551615}}`)
+
+	for _, opts := range [][]mson.Option{nil, {mson.WithMetadata(&mson.Metadata{})}} {
+		var v ids
+
+		if err := mson.UnmarshalWithOptions(data, &v, opts...); err != nil {
+			t.Fatal(err)
+		}
+
+		if v.Signed != 9007199254740993 || v.Unsigned != 1<<63+1 || *v.Pointer != -9007199254740993 || v.List[0] != 9007199254740993 || v.Map["a"] != 1<<64-1 {
+			t.Errorf("got %+v", v)
+		}
+	}
+}
+
+func TestUnmarshalNumbersInInterfaces(t *testing.T) {
+	type loose struct {
+		Any     interface{}            `json:"any"`
+		Nested  map[string]interface{} `json:"nested"`
+		Exact   interface{}            `json:"exact,numbers"`
+		Literal json.Number            `json:"literal"`
+	}
+
+	var v loose
+
+	if err := mson.Unmarshal([]byte(`{"any":[1,{"b":2.5}],"nested":{"a":[3]},"exact":9007199254740993,"literal":12}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	want := loose{
+		Any:     []interface{}{1.0, map[string]interface{}{"b": 2.5}},
+		Nested:  map[string]interface{}{"a": []interface{}{3.0}},
+		Exact:   json.Number("9007199254740993"),
+		Literal: "12",
+	}
+
+	if !reflect.DeepEqual(v, want) {
+		t.Fatalf("got %#v, want %#v", v, want)
+	}
+}
+
+func TestUnmarshalNumberIntoString(t *testing.T) {
+	var v struct {
+		Name string `json:"name,deprecated"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"name":12}`), &v); !errors.Is(err, mson.ErrTypeMismatch) {
+		t.Fatalf("got %v, want ErrTypeMismatch", err)
+	}
+}
+
+func TestUnmarshalBigNumbers(t *testing.T) {
+	type ledger struct {
+		Int   *big.Int  `json:"int"`
+		Float big.Float `json:"float"`
+		Rat   *big.Rat  `json:"rat"`
+		Quote *big.Int  `json:"quote"`
+	}
+
+	data := []byte(`{"int":123456789012345678901234567890,"float":1.5e400,"rat":0.1,"quote":"42"}`)
+
+	var v ledger
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Int.String() != "123456789012345678901234567890" || v.Float.Text('e', 1) != "1.5e+400" || v.Rat.String() != "1/10" {
+		t.Errorf("got %v, %v and %v", v.Int, v.Float.Text('e', 1), v.Rat)
+	}
+
+	if v.Quote.Int64() != 42 {
+		t.Errorf("got %v", v.Quote)
+	}
+
+	msontest.RoundTrip(t, ledger{Int: big.NewInt(-7), Rat: big.NewRat(1, 4), Quote: big.NewInt(1)})
+}
+
+func TestUnmarshalBigNumbersInSlices(t *testing.T) {
+	var v struct {
+		List []*big.Int `json:"list"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"list":[1,18446744073709551617]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(v.List) != 2 || v.List[1].String() != "18446744073709551617" {
+		t.Fatalf("got %v", v.List)
+	}
+}
+
+func TestWhenLeavesNumbersIntact(t *testing.T) {
+	// The when condition reads ids before the field holding them is decoded
+	var v struct {
+		Count float64       `json:"count,when=ids,multiply,2"`
+		IDs   []interface{} `json:"ids,numbers"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"count":2,"ids":[12345678901234567890]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Count != 4 || len(v.IDs) != 1 || v.IDs[0] != json.Number("12345678901234567890") {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestOrderedOption(t *testing.T) {
+	var v struct {
+		Any   interface{}            `json:"any,ordered"`
+		Map   mson.OrderedMap        `json:"map,ordered,numbers"`
+		Plain map[string]interface{} `json:"plain"`
+	}
+
+	data := []byte(`{"any":{"z":1,"a":{"y":2,"b":3}},"map":{"second":9007199254740993,"first":[{"k":1}]},"plain":{"b":1,"a":2}}`)
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	outer, ok := v.Any.(*mson.OrderedMap)
+
+	if !ok || strings.Join(outer.Keys, ",") != "z,a" {
+		t.Fatalf("got %#v", v.Any)
+	}
+
+	if inner, _ := outer.Get("a"); inner.(*mson.OrderedMap).Keys[0] != "y" {
+		t.Errorf("got nested %#v", inner)
+	}
+
+	if n, _ := v.Map.Get("second"); n != json.Number("9007199254740993") {
+		t.Errorf("got %#v, want the exact number", n)
+	}
+
+	out, err := mson.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"any":{"z":1,"a":{"y":2,"b":3}},"map":{"second":9007199254740993,"first":[{"k":1}]},"plain":{"a":2,"b":1}}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	var m mson.OrderedMap
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("b", 3)
+
+	if out, err := json.Marshal(&m); err != nil || string(out) != `{"b":3,"a":2}` {
+		t.Errorf("got %s, %v", out, err)
+	}
+}
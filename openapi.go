@@ -0,0 +1,144 @@
+package mson
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// OpenAPIComponents returns OpenAPI 3.1 component schemas describing the
+// documents that decode into the given structs, keyed by type name as they
+// would appear under components.schemas. Struct fields of named struct types
+// become references to components of their own. Tag options refine a field's
+// schema: unix yields a date-time, encrypted, gzip and zlib yield base64
+// bytes, oneof yields an enum and deprecated marks the property deprecated.
+func OpenAPIComponents(values ...any) map[string]interface{} {
+	b := &schemaBuilder{settings: &settings{}, components: map[string]interface{}{}}
+
+	for _, v := range values {
+		b.typeSchema(reflect.TypeOf(v))
+	}
+
+	return b.components
+}
+
+type schemaBuilder struct {
+	settings   *settings
+	components map[string]interface{}
+}
+
+func (b *schemaBuilder) typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case durationType:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.TypeOf(Version{}), reflect.TypeOf(Rate{}), reflect.TypeOf(Interval{}), regexpType.Elem():
+		return map[string]interface{}{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return map[string]interface{}{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]interface{}{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+
+		return map[string]interface{}{"type": "array", "items": b.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+
+		// Registered before the fields are walked so recursive types
+		// refer to themselves instead of recursing forever
+		if _, ok := b.components[t.Name()]; !ok {
+			b.components[t.Name()] = map[string]interface{}{}
+			b.components[t.Name()] = b.structSchema(t)
+		}
+
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	}
+
+	return map[string]interface{}{}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+
+	for _, field := range structFields(t, b.settings) {
+		name, options, _ := parseTag(field, b.settings)
+		properties[name] = b.fieldSchema(field.Type, fieldOptions(field.Type, options, b.settings))
+	}
+
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
+
+// fieldSchema describes the JSON value accepted by a field of type t, which
+// the field's options may change from what t alone would suggest.
+func (b *schemaBuilder) fieldSchema(t reflect.Type, options []string) map[string]interface{} {
+	schema := b.typeSchema(t)
+
+	for _, option := range options {
+		parts := splitIgnoreQuoted(option, ',')
+
+		switch strings.TrimSuffix(parts[0], "!") {
+		case "unix":
+			schema = map[string]interface{}{"type": "integer", "format": "date-time"}
+		case "duration":
+			schema = map[string]interface{}{"type": "number"}
+		case "encrypted", "gzip", "zlib":
+			schema = map[string]interface{}{"type": "string", "format": "byte"}
+		case "fromstring":
+			layers := 1
+
+			if len(parts) > 1 {
+				if n, err := strconv.Atoi(parts[1]); err == nil {
+					layers = n
+				}
+			}
+
+			for i := 0; i < layers; i++ {
+				schema = map[string]interface{}{"type": "string", "contentMediaType": "application/json", "contentSchema": schema}
+			}
+		case "snowflake", "ulid", "ksuid", "semver", "regexp", "template", "color", "phone", "country", "currency", "langtag", "cron", "rate", "relativetime", "interval":
+			schema = map[string]interface{}{"type": "string"}
+		case "oneof":
+			if len(parts) > 1 {
+				var enum []interface{}
+
+				for _, v := range strings.Split(unquote(parts[1]), "|") {
+					enum = append(enum, v)
+				}
+
+				schema["enum"] = enum
+			}
+		case "deprecated":
+			schema["deprecated"] = true
+		}
+	}
+
+	return schema
+}
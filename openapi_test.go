@@ -0,0 +1,58 @@
+package mson_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+type schemaAddress struct {
+	City string `json:"city"`
+}
+
+type schemaUser struct {
+	ID       int64             `json:"id"`
+	Created  time.Time         `json:"created,unix"`
+	Role     string            `json:"role,oneof,\"admin|user\""`
+	Login    string            `json:"login,deprecated"`
+	Avatar   []byte            `json:"avatar"`
+	Address  schemaAddress     `json:"address"`
+	Friends  []*schemaUser     `json:"friends"`
+	Settings map[string]bool   `json:"settings"`
+	Nested   string            `json:"nested,fromstring"`
+	Score    float32           `json:"score"`
+	Labels   map[string]string `json:"-"`
+}
+
+func TestOpenAPIComponents(t *testing.T) {
+	components := mson.OpenAPIComponents(schemaUser{})
+
+	if len(components) != 2 || components["schemaAddress"] == nil {
+		t.Fatalf("got components %v", components)
+	}
+
+	properties := components["schemaUser"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	ref := func(name string) map[string]interface{} {
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+
+	want := map[string]interface{}{
+		"id":       map[string]interface{}{"type": "integer", "format": "int64"},
+		"created":  map[string]interface{}{"type": "integer", "format": "date-time"},
+		"role":     map[string]interface{}{"type": "string", "enum": []interface{}{"admin", "user"}},
+		"login":    map[string]interface{}{"type": "string", "deprecated": true},
+		"avatar":   map[string]interface{}{"type": "string", "format": "byte"},
+		"address":  ref("schemaAddress"),
+		"friends":  map[string]interface{}{"type": "array", "items": ref("schemaUser")},
+		"settings": map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "boolean"}},
+		"nested":   map[string]interface{}{"type": "string", "contentMediaType": "application/json", "contentSchema": map[string]interface{}{"type": "string"}},
+		"score":    map[string]interface{}{"type": "number", "format": "float"},
+	}
+
+	if !reflect.DeepEqual(properties, want) {
+		t.Errorf("got %v, want %v", properties, want)
+	}
+}
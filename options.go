@@ -0,0 +1,276 @@
+package mson
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An Option configures a Codec or a call to UnmarshalWithOptions or
+// MarshalWithOptions.
+type Option func(*settings)
+
+type settings struct {
+	version     string
+	onWarning   func(Warning)
+	noRedact    bool
+	clock       func() time.Time
+	strictEnums bool
+	tagKeys     []string
+
+	aliases       map[string][]string
+	ciphers       map[string]Cipher
+	caseSensitive bool
+	maxBytes      int
+	metadata      *Metadata
+	typeOptions   map[reflect.Type][]string
+	emptyAsNull   bool
+	sanitize      bool
+	proto3        bool
+	merge         MergePolicy
+	interner      *interner
+	arena         *Arena
+	skipReport    SkipReport
+	resolve       func(ref string) (string, error)
+}
+
+var defaultTagKeys = []string{"mson", "json"}
+
+// key returns the name under which a JSON key or field name is matched.
+func (s *settings) key(name string) string {
+	if s.caseSensitive {
+		return name
+	}
+
+	return strings.ToLower(name)
+}
+
+// lookupTag returns the field's tag under the first configured key it
+// carries, along with that key.
+func (s *settings) lookupTag(field reflect.StructField) (string, string) {
+	keys := s.tagKeys
+
+	if keys == nil {
+		keys = defaultTagKeys
+	}
+
+	for _, key := range keys {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			return tag, key
+		}
+	}
+
+	return "", ""
+}
+
+func (s *settings) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+
+	return time.Now()
+}
+
+// WithVersion decodes the payload as the given API version, honoring the
+// since and until tag options as UnmarshalVersion describes.
+func WithVersion(version string) Option {
+	return func(s *settings) {
+		s.version = version
+	}
+}
+
+// WithWarnings registers a function that is called for every non-fatal issue
+// found while decoding, such as a deprecated key being present in the payload.
+func WithWarnings(fn func(Warning)) Option {
+	return func(s *settings) {
+		s.onWarning = fn
+	}
+}
+
+// WithoutRedaction makes Marshal emit fields tagged with redact or mask
+// unchanged, for encodes that are not destined for logs.
+func WithoutRedaction() Option {
+	return func(s *settings) {
+		s.noRedact = true
+	}
+}
+
+// WithClock makes options relative to the current time, such as duration!,
+// cron and relativetime, read the time from clock instead of time.Now.
+func WithClock(clock func() time.Time) Option {
+	return func(s *settings) {
+		s.clock = clock
+	}
+}
+
+// WithStrictEnums makes every field tagged with oneof fail the decode with an
+// UnknownEnumValueError when it receives a value outside of its accepted set,
+// as if the strict argument was given to each of them.
+func WithStrictEnums() Option {
+	return func(s *settings) {
+		s.strictEnums = true
+	}
+}
+
+// WithTagKeys sets the struct tag keys read for field names and options, in
+// order of preference. The default is to read the mson tag and fall back to
+// the json tag, which lets mson options stay out of tags consumed by
+// encoding/json.
+func WithTagKeys(keys ...string) Option {
+	return func(s *settings) {
+		s.tagKeys = keys
+	}
+}
+
+// WithAlias defines an alias like DefineAlias, visible only to the codec or
+// call configured with this option.
+func WithAlias(name, options string) Option {
+	if verbs[name] {
+		panic(errorf(ErrInvalidTag, "mson: cannot define alias %s; it is already a tag option", name))
+	}
+
+	chain := splitIgnoreQuoted(options, ',')
+
+	return func(s *settings) {
+		aliases := make(map[string][]string, len(s.aliases)+1)
+
+		for k, v := range s.aliases {
+			aliases[k] = v
+		}
+
+		aliases[name] = chain
+		s.aliases = aliases
+	}
+}
+
+// WithCipher registers a cipher like RegisterCipher, visible only to the codec
+// or call configured with this option.
+func WithCipher(name string, c Cipher) Option {
+	return func(s *settings) {
+		ciphers := make(map[string]Cipher, len(s.ciphers)+1)
+
+		for k, v := range s.ciphers {
+			ciphers[k] = v
+		}
+
+		ciphers[name] = c
+		s.ciphers = ciphers
+	}
+}
+
+// WithTypeOptions is like RegisterTypeOptions, but the defaults are visible
+// only to the codec or call configured with this option and take precedence
+// over registered ones, as in WithTypeOptions(time.Time{}, "unix,milliseconds")
+// for an API that sends every timestamp in epoch milliseconds.
+func WithTypeOptions(v any, options string) Option {
+	t := reflect.TypeOf(v)
+	chain := splitIgnoreQuoted(options, ',')
+
+	return func(s *settings) {
+		typeOptions := make(map[reflect.Type][]string, len(s.typeOptions)+1)
+
+		for k, v := range s.typeOptions {
+			typeOptions[k] = v
+		}
+
+		typeOptions[t] = chain
+		s.typeOptions = typeOptions
+	}
+}
+
+// WithEmptyStringAsNull treats empty strings as absent keys when they are
+// decoded into fields that do not hold strings, such as numbers, times and
+// slices, for sources that send "" instead of null.
+func WithEmptyStringAsNull() Option {
+	return func(s *settings) {
+		s.emptyAsNull = true
+	}
+}
+
+// WithSanitize applies the sanitize tag option to every field, trimming
+// whitespace and byte order marks from strings and dropping their control
+// characters.
+func WithSanitize() Option {
+	return func(s *settings) {
+		s.sanitize = true
+	}
+}
+
+// WithProto3 decodes documents following the proto3 JSON mapping produced by
+// protojson and gRPC-gateway. Keys also match the lowerCamelCase or
+// snake_case form of a field's name, null is treated like an absent key, and
+// fields without decoding options of their own accept integers and floats
+// encoded as strings, durations such as "3.5s", RFC 3339 timestamps and
+// base64-encoded bytes.
+func WithProto3() Option {
+	return func(s *settings) {
+		s.proto3 = true
+	}
+}
+
+// WithInterpolation replaces ${ref} placeholders in the decoded strings with
+// what resolve returns for ref, so that documents can refer to environment
+// variables as in ${HOME} or to secrets as in ${secret:db-password}; the
+// meaning of ref is up to resolve. Write $${ for a literal ${.
+func WithInterpolation(resolve func(ref string) (string, error)) Option {
+	return func(s *settings) {
+		s.resolve = resolve
+	}
+}
+
+// WithCaseSensitiveKeys matches JSON keys against field names exactly instead
+// of ignoring case.
+func WithCaseSensitiveKeys() Option {
+	return func(s *settings) {
+		s.caseSensitive = true
+	}
+}
+
+// WithMaxBytes makes Unmarshal reject documents larger than n bytes, and
+// values tagged gzip or zlib that decompress to more than n bytes. Without
+// it, decompressed values are limited to 64 MiB.
+func WithMaxBytes(n int) Option {
+	return func(s *settings) {
+		s.maxBytes = n
+	}
+}
+
+// Metadata describes how a document matched the struct it was decoded into.
+// Paths are the dotted JSON keys leading to the value.
+type Metadata struct {
+	// Unused lists the keys present in the document that no field consumed.
+	Unused []string
+
+	// Unset lists the fields whose key was absent from the document.
+	Unset []string
+}
+
+// metadataMu serializes the appends to every Metadata, since the decodes of
+// a shared Codec may append to the same one at once.
+var metadataMu sync.Mutex
+
+func (md *Metadata) addUnused(path string) {
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+
+	md.Unused = append(md.Unused, path)
+}
+
+func (md *Metadata) addUnset(path string) {
+	metadataMu.Lock()
+	defer metadataMu.Unlock()
+
+	md.Unset = append(md.Unset, path)
+}
+
+// WithMetadata fills md with the keys and fields left unmatched by the
+// decode, so that tests can assert full coverage of upstream payloads. Every
+// decode configured with the option appends to md; decodes running at once,
+// such as those of a shared Codec, append in turn, and md must only be read
+// once they have returned.
+func WithMetadata(md *Metadata) Option {
+	return func(s *settings) {
+		s.metadata = md
+	}
+}
@@ -0,0 +1,165 @@
+package mson_test
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+	"github.com/monerowner/mson/msontest"
+)
+
+func TestStringOptionQuotesAsJSON(t *testing.T) {
+	type quoted struct {
+		Name string `json:"name,string"`
+	}
+
+	in := quoted{Name: "tab\t nul\x00 emoji\U0001F600 quote\""}
+	out, err := mson.Marshal(in)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"name":"\"tab\\t nul\\u0000 emoji😀 quote\\\"\""}`; string(out) != want {
+		t.Fatalf("got %s, want %s", out, want)
+	}
+
+	msontest.RoundTrip(t, in)
+}
+
+func TestFields(t *testing.T) {
+	type Base struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		private string
+	}
+
+	type account struct {
+		Base
+		Title   string        `api:"name" json:"title"`
+		Skipped string        `json:"-"`
+		Timeout time.Duration `json:"timeout"`
+		secret  string
+	}
+
+	mson.RegisterTypeOptions(time.Duration(0), "duration")
+	defer mson.RegisterTypeOptions(time.Duration(0), "")
+
+	fields, err := mson.Fields(&account{}, mson.WithTagKeys("api", "json"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []mson.Field{
+		{Key: "id", Index: []int{0, 0}, Tag: "id"},
+		{Key: "name", Index: []int{1}, Tag: "name"},
+		{Key: "timeout", Index: []int{3}, Tag: "timeout", Options: []string{"duration"}},
+	}
+
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("got %+v, want %+v", fields, want)
+	}
+
+	if _, err := mson.Fields(map[string]int{}); err == nil {
+		t.Fatal("Fields accepted a map")
+	}
+}
+
+// recorder collects what RoundTrip reports instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	r.fatal = true
+	runtime.Goexit()
+}
+
+func TestRoundTripReportsUndecodableKeys(t *testing.T) {
+	type counted struct {
+		Name  string `json:"name"`
+		Count int    `json:"count,count"`
+	}
+
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		msontest.RoundTrip(r, counted{Name: "a", Count: 2})
+	}()
+
+	<-done
+
+	if r.fatal || len(r.errors) != 1 || !strings.Contains(r.errors[0], "key count ") || !strings.Contains(r.errors[0], `tag "count,count"`) {
+		t.Fatalf("got %q", r.errors)
+	}
+}
+
+func TestNullSkipsConversions(t *testing.T) {
+	type nullable struct {
+		Expires *time.Time     `json:"expires,unix"`
+		Created time.Time      `json:"created,unix,milliseconds"`
+		Pattern *regexp.Regexp `json:"pattern,regexp"`
+		Slug    string         `json:"slug,slug"`
+		Tags    []string       `json:"tags,sort"`
+		Count   int            `json:"count,count"`
+		Empty   []string       `json:"empty,nilslice"`
+	}
+
+	now := time.Now()
+	v := nullable{Expires: &now, Created: now, Slug: "x", Tags: []string{"a"}, Count: 1}
+
+	if err := mson.Unmarshal([]byte(`{"expires":null,"created":null,"pattern":null,"slug":null,"tags":null,"count":null,"empty":null}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Expires != nil || !v.Created.IsZero() || v.Pattern != nil || v.Slug != "" || v.Tags != nil || v.Count != 0 || v.Empty == nil {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestNilOptions(t *testing.T) {
+	type child struct {
+		A int `json:"a"`
+	}
+
+	var v struct {
+		Slice  []int          `json:"slice,nilslice"`
+		Map    map[string]int `json:"map,nilmap"`
+		Struct *child         `json:"struct,nilstruct"`
+		Empty  *child         `json:"empty,nilstruct!"`
+		List   []int          `json:"list,nilslice!"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"slice":null,"map":null,"struct":null,"empty":{},"list":[]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Slice == nil || len(v.Slice) != 0 || v.Map == nil || v.Struct == nil || *v.Struct != (child{}) {
+		t.Errorf("got %+v, want empty values for null", v)
+	}
+
+	if v.Empty != nil || v.List != nil {
+		t.Errorf("got %+v, want nil for empty values", v)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"empty":{"a":1}}`), &v); err != nil || v.Empty == nil || v.Empty.A != 1 {
+		t.Errorf("got %+v, %v", v, err)
+	}
+}
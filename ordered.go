@@ -0,0 +1,200 @@
+package mson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// An OrderedMap is a JSON object that remembers the order of its keys. Fields
+// of type any or OrderedMap tagged with ordered receive one, with nested
+// objects decoded as *OrderedMap as well, so pass-through data can be
+// re-encoded exactly as it arrived.
+type OrderedMap struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+var orderedMapType = reflect.TypeOf(OrderedMap{})
+
+// Get returns the value stored under key.
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	v, ok := m.Values[key]
+	return v, ok
+}
+
+// Set stores value under key, appending key if it is new.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if m.Values == nil {
+		m.Values = map[string]interface{}{}
+	}
+
+	if _, ok := m.Values[key]; !ok {
+		m.Keys = append(m.Keys, key)
+	}
+
+	m.Values[key] = value
+}
+
+// MarshalJSON encodes the map with its keys in order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, k := range m.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		value, err := json.Marshal(m.Values[k])
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(value)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes an object, keeping the order of its keys. Numbers
+// are decoded as json.Number.
+func (m *OrderedMap) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	v, err := decodeOrdered(dec)
+
+	if err != nil {
+		return err
+	}
+
+	om, ok := v.(*OrderedMap)
+
+	if !ok {
+		return fmt.Errorf("mson: cannot decode %s into an OrderedMap", data)
+	}
+
+	*m = *om
+
+	return nil
+}
+
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		m := &OrderedMap{Values: map[string]interface{}{}}
+
+		for dec.More() {
+			key, err := dec.Token()
+
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeOrdered(dec)
+
+			if err != nil {
+				return nil, err
+			}
+
+			m.Set(key.(string), value)
+		}
+
+		_, err := dec.Token()
+
+		return m, err
+	case json.Delim('['):
+		elems := []interface{}{}
+
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+
+			if err != nil {
+				return nil, err
+			}
+
+			elems = append(elems, value)
+		}
+
+		_, err := dec.Token()
+
+		return elems, err
+	}
+
+	return tok, nil
+}
+
+// orderedValue decodes the field being processed again from the source
+// document, since the intermediate map has already lost the order of its keys.
+func orderedValue(target reflect.Type, numbers bool, fieldName string, state *decodeState) (interface{}, error) {
+	if target != orderedMapType && target.Kind() != reflect.Interface {
+		return nil, errorf(ErrTypeMismatch, "mson: cannot apply ordered to field %s; field is of type %s, not any or OrderedMap", fieldName, target)
+	}
+
+	offset, exact := locateValue(state.source, state.path, &state.settings)
+
+	if !exact {
+		return nil, fmt.Errorf("mson: cannot preserve the key order of field %s, which is not reached through objects alone", fieldName)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(state.source[offset:]))
+	dec.UseNumber()
+
+	value, err := decodeOrdered(dec)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !numbers {
+		value = orderedFloats(value)
+	}
+
+	if target == orderedMapType {
+		m, ok := value.(*OrderedMap)
+
+		if !ok {
+			return nil, errorf(ErrTypeMismatch, "mson: field %s is not an object", fieldName)
+		}
+
+		return *m, nil
+	}
+
+	return value, nil
+}
+
+// orderedFloats converts the json.Number values of an ordered document into
+// float64, as numbers are decoded elsewhere.
+func orderedFloats(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i, e := range v {
+			v[i] = orderedFloats(e)
+		}
+	case *OrderedMap:
+		for k, e := range v.Values {
+			v.Values[k] = orderedFloats(e)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,246 @@
+package mson
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	certificateType      = reflect.TypeOf((*x509.Certificate)(nil))
+	certificateSliceType = reflect.TypeOf([]*x509.Certificate(nil))
+	certPoolType         = reflect.TypeOf((*x509.CertPool)(nil))
+	pemBlockType         = reflect.TypeOf((*pem.Block)(nil))
+	bytesType            = reflect.TypeOf([]byte(nil))
+	publicKeyType        = reflect.TypeOf((*crypto.PublicKey)(nil)).Elem()
+)
+
+func decodePEMBlocks(value interface{}, fieldName string) ([]*pem.Block, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	var blocks []*pem.Block
+	rest := []byte(s)
+
+	for {
+		var block *pem.Block
+
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	if len(blocks) == 0 {
+		return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must be PEM encoded"}
+	}
+
+	return blocks, nil
+}
+
+// parseCertificates decodes the CERTIFICATE blocks of a PEM string into a
+// certificate, a chain or a pool. The valid argument rejects certificates
+// outside their validity period; a duration argument additionally requires
+// them to remain valid for that long.
+func parseCertificates(value interface{}, parts []string, target reflect.Type, fieldName string, now time.Time) (reflect.Value, error) {
+	var minValidity time.Duration
+	checkExpiry := false
+
+	if len(parts) > 1 {
+		checkExpiry = true
+
+		if parts[1] != "valid" {
+			d, err := time.ParseDuration(parts[1])
+
+			if err != nil || d < 0 {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'x509' received invalid argument %s", parts[1]))
+			}
+
+			minValidity = d
+		}
+	}
+
+	if target != certificateType && target != certificateSliceType && target != certPoolType {
+		return reflect.Value{}, errorf(ErrTypeMismatch, "mson: cannot parse field %s as x509; field is of type %s", fieldName, target)
+	}
+
+	blocks, err := decodePEMBlocks(value, fieldName)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	var certs []*x509.Certificate
+
+	for _, block := range blocks {
+		if block.Type != "CERTIFICATE" {
+			return reflect.Value{}, fmt.Errorf("mson: field %s contains a PEM block of type %s, expected CERTIFICATE", fieldName, block.Type)
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("mson: %w, parsing of field %s failed", err, fieldName)
+		}
+
+		if checkExpiry {
+			subject := cert.Subject.String()
+
+			switch {
+			case now.Before(cert.NotBefore):
+				return reflect.Value{}, &ValidationError{Field: fieldName, Value: subject, Reason: "must contain certificates that are already valid"}
+			case now.After(cert.NotAfter):
+				return reflect.Value{}, &ValidationError{Field: fieldName, Value: subject, Reason: "must contain unexpired certificates"}
+			case now.Add(minValidity).After(cert.NotAfter):
+				return reflect.Value{}, &ValidationError{Field: fieldName, Value: subject, Reason: fmt.Sprintf("must contain certificates valid for at least %s", minValidity)}
+			}
+		}
+
+		certs = append(certs, cert)
+	}
+
+	switch target {
+	case certificateType:
+		return reflect.ValueOf(certs[0]), nil
+	case certPoolType:
+		pool := x509.NewCertPool()
+
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+
+		return reflect.ValueOf(pool), nil
+	default:
+		return reflect.ValueOf(certs), nil
+	}
+}
+
+// parsePEM decodes the first block of a PEM string into the field's type: a
+// *pem.Block, its DER bytes, or a parsed key or certificate. The optional
+// argument names the required block type, with spaces written as dashes.
+func parsePEM(value interface{}, parts []string, target reflect.Type, fieldName string) (reflect.Value, error) {
+	blocks, err := decodePEMBlocks(value, fieldName)
+
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	block := blocks[0]
+
+	if len(parts) > 1 {
+		if expected := strings.ReplaceAll(strings.ToUpper(unquote(parts[1])), "-", " "); block.Type != expected {
+			return reflect.Value{}, fmt.Errorf("mson: field %s contains a PEM block of type %s, expected %s", fieldName, block.Type, expected)
+		}
+	}
+
+	switch target {
+	case pemBlockType:
+		return reflect.ValueOf(block), nil
+	case bytesType:
+		return reflect.ValueOf(block.Bytes), nil
+	}
+
+	var parsed interface{}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		parsed, err = x509.ParseCertificate(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return reflect.Value{}, fmt.Errorf("mson: field %s contains an unsupported PEM block of type %s", fieldName, block.Type)
+	}
+
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("mson: %w, parsing of field %s failed", err, fieldName)
+	}
+
+	rv := reflect.ValueOf(parsed)
+
+	// crypto.PublicKey accepts any value, so it is matched before assignability
+	if rv.Type().AssignableTo(target) && target != publicKeyType {
+		return rv, nil
+	}
+
+	// A private key or certificate also satisfies a field expecting its public key
+	switch p := parsed.(type) {
+	case crypto.Signer:
+		rv = reflect.ValueOf(p.Public())
+	case *x509.Certificate:
+		rv = reflect.ValueOf(p.PublicKey)
+	}
+
+	if rv.Type().AssignableTo(target) {
+		return rv, nil
+	}
+
+	return reflect.Value{}, errorf(ErrTypeMismatch, "mson: cannot assign PEM block of type %s to field %s of type %s", block.Type, fieldName, target)
+}
+
+// formatPEM is the inverse of parsePEM and parseCertificates. Values that
+// cannot be encoded, such as certificate pools, are returned unchanged.
+func formatPEM(value interface{}, parts []string) (interface{}, error) {
+	var blocks []*pem.Block
+
+	switch v := value.(type) {
+	case []byte:
+		// Raw DER bytes can only be encoded when the tag names the block type
+		if len(parts) < 2 || v == nil {
+			return value, nil
+		}
+
+		blocks = append(blocks, &pem.Block{Type: strings.ReplaceAll(strings.ToUpper(unquote(parts[1])), "-", " "), Bytes: v})
+	case *pem.Block:
+		if v == nil {
+			return nil, nil
+		}
+
+		blocks = append(blocks, v)
+	case *x509.Certificate:
+		if v == nil {
+			return nil, nil
+		}
+
+		blocks = append(blocks, &pem.Block{Type: "CERTIFICATE", Bytes: v.Raw})
+	case []*x509.Certificate:
+		for _, cert := range v {
+			blocks = append(blocks, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+		}
+	case nil:
+		return nil, nil
+	default:
+		if der, err := x509.MarshalPKCS8PrivateKey(v); err == nil {
+			blocks = append(blocks, &pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		} else if der, err := x509.MarshalPKIXPublicKey(v); err == nil {
+			blocks = append(blocks, &pem.Block{Type: "PUBLIC KEY", Bytes: der})
+		} else {
+			return value, nil
+		}
+	}
+
+	var b strings.Builder
+
+	for _, block := range blocks {
+		if err := pem.Encode(&b, block); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,141 @@
+package mson_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+// testCertificate returns a self-signed certificate valid for the 30 days
+// after testNow, along with its key, both PEM encoded.
+func testCertificate(t *testing.T) (cert, key string) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mson test"},
+		NotBefore:    testNow,
+		NotAfter:     testNow.AddDate(0, 0, 30),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, public, private)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(private)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	key = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}))
+
+	return cert, key
+}
+
+func TestX509Option(t *testing.T) {
+	cert, _ := testCertificate(t)
+
+	type tls struct {
+		Cert  *x509.Certificate   `json:"cert,x509,valid"`
+		Chain []*x509.Certificate `json:"chain,x509"`
+		Pool  *x509.CertPool      `json:"pool,x509"`
+		Week  *x509.Certificate   `json:"week,x509,168h"`
+	}
+
+	data, _ := json.Marshal(map[string]string{"cert": cert, "chain": cert + cert, "pool": cert, "week": cert})
+	var v tls
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithClock(testClock)); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Cert.Subject.CommonName != "mson test" || len(v.Chain) != 2 || v.Pool == nil || v.Week == nil {
+		t.Fatalf("got %+v", v)
+	}
+
+	later := func() time.Time { return testNow.AddDate(0, 0, 25) }
+	var validationErr *mson.ValidationError
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithClock(later)); !errors.As(err, &validationErr) || validationErr.Field != "week" {
+		t.Fatalf("got %v, want a ValidationError for week", err)
+	}
+
+	earlier := func() time.Time { return testNow.Add(-time.Hour) }
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithClock(earlier)); !errors.As(err, &validationErr) || validationErr.Field != "cert" {
+		t.Fatalf("got %v, want a ValidationError for cert", err)
+	}
+}
+
+func TestPEMOption(t *testing.T) {
+	cert, key := testCertificate(t)
+
+	type keys struct {
+		Private ed25519.PrivateKey `json:"private,pem,private-key"`
+		Public  crypto.PublicKey   `json:"public,pem"`
+		Block   *pem.Block         `json:"block,pem"`
+		DER     []byte             `json:"der,pem,certificate"`
+	}
+
+	data, _ := json.Marshal(map[string]string{"private": key, "public": cert, "block": cert, "der": cert})
+	var v keys
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if public, ok := v.Public.(ed25519.PublicKey); !ok || !public.Equal(v.Private.Public()) {
+		t.Errorf("got public key %v, want the certificate's key", v.Public)
+	}
+
+	if v.Block.Type != "CERTIFICATE" || !bytes.Equal(v.Block.Bytes, v.DER) {
+		t.Errorf("got block %v and DER of %d bytes", v.Block.Type, len(v.DER))
+	}
+
+	out, err := mson.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var encoded map[string]string
+
+	if err := json.Unmarshal(out, &encoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if encoded["private"] != key || encoded["block"] != cert || encoded["der"] != cert {
+		t.Errorf("got %s", out)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"der":"not pem"}`), &v); err == nil {
+		t.Error("decoded a value that is not PEM")
+	}
+
+	wrongType, _ := json.Marshal(map[string]string{"private": cert})
+
+	if err := mson.Unmarshal(wrongType, &v); err == nil {
+		t.Error("decoded a certificate into a private-key field")
+	}
+}
@@ -0,0 +1,120 @@
+package mson
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// A PhoneNormalizer converts a phone number into E.164 form, interpreting
+// numbers without a country code as belonging to the given ISO 3166 region.
+// Register an implementation backed by libphonenumber with
+// RegisterPhoneNormalizer for full validation; the built-in normalizer only
+// checks the number's shape. A PhoneNormalizer must be safe for concurrent
+// use.
+type PhoneNormalizer interface {
+	NormalizePhone(number, defaultRegion string) (string, error)
+}
+
+var (
+	phoneMu         sync.RWMutex
+	phoneNormalizer PhoneNormalizer = basicPhoneNormalizer{}
+)
+
+// RegisterPhoneNormalizer replaces the normalizer used by the phone tag
+// option. Passing nil restores the built-in normalizer.
+func RegisterPhoneNormalizer(n PhoneNormalizer) {
+	phoneMu.Lock()
+	defer phoneMu.Unlock()
+
+	if n == nil {
+		n = basicPhoneNormalizer{}
+	}
+
+	phoneNormalizer = n
+}
+
+var callingCodes = map[string]string{
+	"AR": "54", "AT": "43", "AU": "61", "BE": "32", "BR": "55", "CA": "1", "CH": "41", "CL": "56",
+	"CN": "86", "CO": "57", "CZ": "420", "DE": "49", "DK": "45", "EG": "20", "ES": "34", "FI": "358",
+	"FR": "33", "GB": "44", "GR": "30", "HK": "852", "HU": "36", "ID": "62", "IE": "353", "IL": "972",
+	"IN": "91", "IT": "39", "JP": "81", "KR": "82", "MX": "52", "MY": "60", "NG": "234", "NL": "31",
+	"NO": "47", "NZ": "64", "PH": "63", "PK": "92", "PL": "48", "PT": "351", "RO": "40", "RU": "7",
+	"SA": "966", "SE": "46", "SG": "65", "TH": "66", "TR": "90", "TW": "886", "UA": "380", "US": "1",
+	"VN": "84", "ZA": "27",
+}
+
+type basicPhoneNormalizer struct{}
+
+func (basicPhoneNormalizer) NormalizePhone(number, defaultRegion string) (string, error) {
+	var digits strings.Builder
+	international := false
+
+	for i, r := range strings.TrimSpace(number) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+			international = true
+		case strings.ContainsRune(" -.()/", r):
+		default:
+			return "", fmt.Errorf("invalid character %q in phone number", r)
+		}
+	}
+
+	d := digits.String()
+
+	switch {
+	case international:
+	case strings.HasPrefix(d, "00"):
+		d = d[2:]
+	default:
+		code, ok := callingCodes[strings.ToUpper(defaultRegion)]
+
+		if !ok {
+			return "", fmt.Errorf("phone number has no country code and region %q is unknown", defaultRegion)
+		}
+
+		// Drop the national trunk prefix, which E.164 omits
+		if code != "1" {
+			d = strings.TrimPrefix(d, "0")
+		} else if len(d) == 11 && d[0] == '1' {
+			d = d[1:]
+		}
+
+		d = code + d
+	}
+
+	if len(d) < 8 || len(d) > 15 || d[0] == '0' {
+		return "", errors.New("phone number has an invalid length")
+	}
+
+	return "+" + d, nil
+}
+
+func normalizePhone(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	var region string
+
+	if len(parts) > 1 {
+		region = parts[1]
+	}
+
+	phoneMu.RLock()
+	n := phoneNormalizer
+	phoneMu.RUnlock()
+
+	normalized, err := n.NormalizePhone(s, region)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, normalization of field %s failed", err, fieldName)
+	}
+
+	return normalized, nil
+}
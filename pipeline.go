@@ -0,0 +1,257 @@
+package mson
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// A valueKind is the shape of the value passed from one tag option to the
+// next while processTag runs the options of a field as a pipeline.
+type valueKind uint8
+
+const (
+	// kindAny is a value whose shape is only known when decoding, such as
+	// the one taken from the document
+	kindAny valueKind = iota
+	kindString
+	kindNumber
+	kindBool
+	kindTime
+	kindDuration
+	kindArray
+	kindObject
+)
+
+var kindNames = [...]string{"any value", "a string", "a number", "a boolean", "a time", "a duration", "an array", "an object"}
+
+func (k valueKind) String() string {
+	return kindNames[k]
+}
+
+// A stage describes what a tag option does to the value: the kinds it
+// accepts, none meaning any, and the kind it produces. Options that only
+// check the value pass it on unchanged, and final ones store the field
+// themselves, so that no option may follow them.
+type stage struct {
+	in    []valueKind
+	out   valueKind
+	same  bool
+	final bool
+}
+
+var (
+	passStage    = stage{same: true}
+	textStage    = stage{in: []valueKind{kindString}, out: kindString}
+	numberStage  = stage{in: []valueKind{kindNumber}, out: kindNumber}
+	arrayStage   = stage{in: []valueKind{kindArray}, out: kindArray}
+	checkStage   = stage{out: kindBool}
+	patternStage = stage{in: []valueKind{kindString}, out: kindBool}
+	finalStage   = stage{final: true}
+)
+
+// stages holds the stage of every option whose input and output are known.
+// Inverted options use the entry ending in '!' when there is one and the
+// entry of the option otherwise. Options without an entry produce kindAny,
+// which any option may follow.
+var stages = map[string]stage{
+	"since":        passStage,
+	"until":        passStage,
+	"deprecated":   passStage,
+	"redact":       passStage,
+	"mask":         passStage,
+	"durfmt":       passStage,
+	"prec":         passStage,
+	"fmt":          passStage,
+	"htmlescape":   passStage,
+	"tostring":     passStage,
+	"omitempty":    passStage,
+	"omitzero":     passStage,
+	"omitif":       passStage,
+	"hash":         passStage,
+	"null":         passStage,
+	"required":     passStage,
+	"regex":        {in: []valueKind{kindString}, same: true},
+	"oneof":        passStage,
+	"empty":        passStage,
+	"numbers":      passStage,
+	"sanitize":     passStage,
+	"nilslice":     passStage,
+	"nilmap":       passStage,
+	"nilstruct":    passStage,
+	"maxlen":       textStage,
+	"pad":          textStage,
+	"slug":         textStage,
+	"camel":        textStage,
+	"pascal":       textStage,
+	"snake":        textStage,
+	"kebab":        textStage,
+	"htmlunescape": textStage,
+	"norm":         textStage,
+	"path":         textStage,
+	"hostname":     textStage,
+	"email":        textStage,
+	"mime":         textStage,
+	"ext":          textStage,
+	"phone":        textStage,
+	"country":      textStage,
+	"currency":     textStage,
+	"add":          numberStage,
+	"subtract":     numberStage,
+	"multiply":     numberStage,
+	"divide":       numberStage,
+	"round":        numberStage,
+	"floor":        numberStage,
+	"ceil":         numberStage,
+	"sort":         arrayStage,
+	"unique":       arrayStage,
+	"reverse":      arrayStage,
+	"limit":        arrayStage,
+	"filter":       arrayStage,
+	"each":         arrayStage,
+	"equals":       checkStage,
+	"contains":     checkStage,
+	"in":           checkStage,
+	"between":      checkStage,
+	"startswith":   patternStage,
+	"endswith":     patternStage,
+	"like":         patternStage,
+	"regexp":       finalStage,
+	"template":     finalStage,
+	"x509":         finalStage,
+	"pem":          finalStage,
+	"sum":          {in: []valueKind{kindArray}, out: kindNumber},
+	"avg":          {in: []valueKind{kindArray}, out: kindNumber},
+	"min":          {in: []valueKind{kindArray}, out: kindNumber},
+	"max":          {in: []valueKind{kindArray}, out: kindNumber},
+	"count":        {in: []valueKind{kindArray}, out: kindNumber},
+	"first":        {in: []valueKind{kindArray}},
+	"last":         {in: []valueKind{kindArray}},
+	"index":        {in: []valueKind{kindArray}},
+	"groupby":      {in: []valueKind{kindArray}, out: kindObject},
+	"keys":         {in: []valueKind{kindObject}, out: kindArray},
+	"values":       {in: []valueKind{kindObject}, out: kindArray},
+	"link":         {in: []valueKind{kindObject}},
+	"unix":         {in: []valueKind{kindNumber, kindString}, out: kindTime},
+	"duration":     {in: []valueKind{kindNumber, kindString}, out: kindDuration},
+	"duration!":    {in: []valueKind{kindNumber, kindString}, out: kindTime},
+	"relativetime": {in: []valueKind{kindString}, out: kindTime},
+	"timetrunc":    {in: []valueKind{kindTime}, out: kindTime},
+	"timeround":    {in: []valueKind{kindTime}, out: kindTime},
+	"fromstring":   {in: []valueKind{kindString}},
+	"fromstring!":  {out: kindString},
+}
+
+type pipelineKey struct {
+	t       reflect.Type
+	options string
+}
+
+// pipelines caches the result of checkPipeline for each field type and list
+// of options.
+var pipelines sync.Map
+
+// checkPipeline panics when an option of the list cannot accept the value
+// produced by the option before it, or when the value produced by the last
+// one cannot be stored in a field of type t, so that such tags are rejected
+// before any value reaches them.
+func checkPipeline(t reflect.Type, options []string) {
+	key := pipelineKey{t, strings.Join(options, "\x00")}
+	cached, ok := pipelines.Load(key)
+
+	if !ok {
+		err := validatePipeline(derefType(t), options)
+		pipelines.Store(key, err)
+		cached = err
+	}
+
+	if err, _ := cached.(error); err != nil {
+		panic(err)
+	}
+}
+
+func validatePipeline(target reflect.Type, options []string) error {
+	kind, producer := kindAny, ""
+
+	for _, opt := range options {
+		parts := splitIgnoreQuoted(opt, ',')
+		name := parts[0]
+		s, ok := stages[name]
+
+		// min and max bound the value when given an argument
+		if isBound(parts) {
+			s = stage{in: []valueKind{kindNumber, kindString, kindArray, kindObject}, same: true}
+		}
+
+		if verb := strings.TrimSuffix(name, "!"); !ok {
+			s, ok = stages[verb]
+			name = verb
+		}
+
+		// Options guarded by when may be skipped, so nothing is known of
+		// the value after it
+		if name == "when" || !ok {
+			kind, producer = kindAny, ""
+			continue
+		}
+
+		if producer != "" && stages[producer].final && !s.same {
+			return errorf(ErrInvalidTag, "mson: tag option '%s' cannot follow '%s', which stores the field itself", name, producer)
+		}
+
+		if kind != kindAny && len(s.in) > 0 && !acceptsKind(s.in, kind) {
+			return errorf(ErrInvalidTag, "mson: tag option '%s' cannot follow '%s', which produces %s; '%s' expects %s", name, producer, kind, name, s.in[0])
+		}
+
+		if !s.same {
+			kind, producer = s.out, name
+		}
+	}
+
+	if kind != kindAny && !storesKind(target, kind) {
+		return errorf(ErrInvalidTag, "mson: tag option '%s' produces %s, which cannot be stored in a field of type %s", producer, kind, target)
+	}
+
+	return nil
+}
+
+func acceptsKind(accepted []valueKind, kind valueKind) bool {
+	for _, k := range accepted {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+// storesKind reports whether a field of type t can hold values of the kind.
+func storesKind(t reflect.Type, kind valueKind) bool {
+	if t.Kind() == reflect.Interface {
+		return true
+	}
+
+	switch kind {
+	case kindString:
+		return t.Kind() == reflect.String
+	case kindBool:
+		return t.Kind() == reflect.Bool
+	case kindTime:
+		return t.ConvertibleTo(timeType)
+	case kindNumber, kindDuration:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			return true
+		}
+
+		return isBigType(t)
+	case kindArray:
+		return t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+	case kindObject:
+		return t.Kind() == reflect.Map || t.Kind() == reflect.Struct
+	}
+
+	return true
+}
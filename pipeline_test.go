@@ -0,0 +1,108 @@
+package mson_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+func TestPipelineRejectsMismatchedOptions(t *testing.T) {
+	tests := []any{
+		// unix produces a time, which slug cannot read
+		&struct {
+			F string `json:"f,unix,slug"`
+		}{},
+		// sum produces a number, which cannot be stored in a string
+		&struct {
+			F string `json:"f,sum"`
+		}{},
+		// regexp stores the field itself, so nothing may follow it
+		&struct {
+			F *regexp.Regexp `json:"f,regexp,slug"`
+		}{},
+	}
+
+	for _, v := range tests {
+		func() {
+			defer func() {
+				if err, _ := recover().(error); !errors.Is(err, mson.ErrInvalidTag) {
+					t.Errorf("decoding into %T panicked with %v, want ErrInvalidTag", v, err)
+				}
+			}()
+
+			_ = mson.Unmarshal([]byte(`{"f":"x"}`), v)
+		}()
+	}
+}
+
+func TestPipelineAcceptsGuardedOptions(t *testing.T) {
+	var v struct {
+		Kind string    `json:"kind"`
+		At   time.Time `json:"at,when=kind=unix,unix,when=kind=ms,unix,milliseconds"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"kind":"ms","at":1700000000123}`), &v); err != nil || !v.At.Equal(time.UnixMilli(1700000000123)) {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+}
+
+type timestamp time.Time
+
+func TestPipelineAcceptsNamedTimeTypes(t *testing.T) {
+	var v struct {
+		At  timestamp  `json:"at,unix"`
+		Day *timestamp `json:"day,unix,timetrunc,day"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"at":1700000000,"day":1700000000}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if !time.Time(v.At).Equal(time.Unix(1700000000, 0)) || !time.Time(*v.Day).Equal(time.Unix(1700000000, 0).Truncate(24*time.Hour)) {
+		t.Fatalf("got %v and %v", time.Time(v.At), time.Time(*v.Day))
+	}
+}
+
+func TestCompiledOptions(t *testing.T) {
+	var v struct {
+		Pattern  *regexp.Regexp     `json:"pattern,regexp"`
+		Template *template.Template `json:"template,template"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"pattern":"^a+$","template":"hello {{.}}"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.Pattern.MatchString("aaa") || v.Pattern.MatchString("ab") || v.Template == nil {
+		t.Fatalf("got %+v", v)
+	}
+
+	var buf strings.Builder
+
+	if err := v.Template.Execute(&buf, "world"); err != nil || buf.String() != "hello world" {
+		t.Fatalf("got %q, %v", buf.String(), err)
+	}
+
+	out, err := mson.Marshal(struct {
+		Pattern *regexp.Regexp `json:"pattern,regexp"`
+	}{v.Pattern})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"pattern":"^a+$"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+
+	for _, data := range []string{`{"pattern":"(("}`, `{"template":"{{"}`} {
+		if err := mson.Unmarshal([]byte(data), &v); err == nil {
+			t.Errorf("compiled invalid value %s", data)
+		}
+	}
+}
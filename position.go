@@ -0,0 +1,130 @@
+package mson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// A FieldError reports a field whose value could not be decoded, along with
+// the position of that value in the source document. It wraps the underlying
+// error, so errors.Is and errors.As see through it.
+type FieldError struct {
+	// Field is the dotted path of JSON keys leading to the value.
+	Field string
+
+	// Offset is the byte offset of the value in the document; Line and
+	// Column are its 1-based line and column. When the value itself cannot
+	// be located, such as an element reached through an array, they point
+	// at the closest enclosing value.
+	Offset int64
+	Line   int
+	Column int
+
+	Err error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (line %d, column %d)", e.Err, e.Line, e.Column)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// fieldError wraps err with the position of the value at path in src.
+func fieldError(err error, src []byte, path []string, s *settings) error {
+	if _, ok := err.(*FieldError); ok || src == nil {
+		return err
+	}
+
+	offset, _ := locateValue(src, path, s)
+	line := 1 + bytes.Count(src[:offset], []byte{'\n'})
+	column := int(offset) + 1
+
+	if i := bytes.LastIndexByte(src[:offset], '\n'); i >= 0 {
+		column = int(offset) - i
+	}
+
+	return &FieldError{strings.Join(path, "."), offset, line, column, err}
+}
+
+// locateValue returns the byte offset of the value reached by following path
+// through the objects of src, or of the deepest value along the way that
+// exists, reporting whether the value itself was found. The position-less
+// intermediate map is of no help here, so the document is scanned again.
+func locateValue(src []byte, path []string, s *settings) (int64, bool) {
+	dec := json.NewDecoder(bytes.NewReader(src))
+	offset := skipSpace(src, 0)
+
+	for _, name := range path {
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+			return offset, false
+		}
+
+		found := false
+
+		for dec.More() {
+			tok, err := dec.Token()
+
+			if err != nil {
+				return offset, false
+			}
+
+			if k, _ := tok.(string); s.key(k) == s.key(name) {
+				offset = skipSpace(src, dec.InputOffset())
+				found = true
+				break
+			}
+
+			if skipValue(dec) != nil {
+				return offset, false
+			}
+		}
+
+		if !found {
+			return offset, false
+		}
+	}
+
+	return offset, true
+}
+
+// skipSpace returns the offset of the first byte at or after i that is
+// neither whitespace nor the colon separating a key from its value.
+func skipSpace(src []byte, i int64) int64 {
+	for i < int64(len(src)) {
+		switch src[i] {
+		case ' ', '\t', '\r', '\n', ':':
+			i++
+		default:
+			return i
+		}
+	}
+
+	return i
+}
+
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+
+	for {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package mson_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/monerowner/mson"
+	"github.com/monerowner/mson/msontest"
+)
+
+type predicates struct {
+	HasAdmin   bool `json:"roles,contains,admin"`
+	NoGuest    bool `json:"guests,contains!,guest"`
+	HasKey     bool `json:"settings,contains,theme"`
+	Substring  bool `json:"title,contains,\"draft\""`
+	Primary    bool `json:"color,in,\"red|green|blue\""`
+	NotPrimary bool `json:"shade,in!,\"red|green|blue\""`
+	Adult      bool `json:"age,between,18,130"`
+	Summer     bool `json:"date,between,2024-06-21T00:00:00Z,2024-09-22T00:00:00Z"`
+	Image      bool `json:"file,like,\"*.png\""`
+	Secure     bool `json:"url,startswith,https://"`
+	Document   bool `json:"doc,endswith,.pdf"`
+	IsZero     bool `json:"zero,equals"`
+	IsFive     bool `json:"five,equals,5"`
+}
+
+func TestContainsOption(t *testing.T) {
+	var v struct {
+		HasAdmin  bool `json:"roles,contains,admin"`
+		NoGuest   bool `json:"guests,contains!,guest"`
+		HasKey    bool `json:"settings,contains,theme"`
+		Substring bool `json:"title,contains,\"draft\""`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"roles":["user","admin"],"guests":["a"],"settings":{"theme":"dark"},"title":"a draft post"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.HasAdmin || !v.NoGuest || !v.HasKey || !v.Substring {
+		t.Fatalf("got %+v", v)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"roles":["administrator"],"guests":["guest"],"settings":{"mode":"theme"},"title":"final"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.HasAdmin || v.NoGuest || v.HasKey || v.Substring {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestInAndBetweenOptions(t *testing.T) {
+	var v struct {
+		Primary    bool `json:"color,in,\"red|green|blue\""`
+		NotPrimary bool `json:"shade,in!,\"red|green|blue\""`
+		Adult      bool `json:"age,between,18,130"`
+		Summer     bool `json:"date,between,2024-06-21T00:00:00Z,2024-09-22T00:00:00Z"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"color":"green","shade":"green","age":42,"date":"2024-07-01T00:00:00Z"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.Primary || v.NotPrimary || !v.Adult || !v.Summer {
+		t.Fatalf("got %+v", v)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"color":"teal","shade":"teal","age":17,"date":"2024-12-01T00:00:00Z"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Primary || !v.NotPrimary || v.Adult || v.Summer {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestStringPredicates(t *testing.T) {
+	var v struct {
+		Image    bool `json:"file,like,\"*.png\""`
+		Secure   bool `json:"url,startswith,https://"`
+		Document bool `json:"doc,endswith,.pdf"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"file":"cat.png","url":"http://x","doc":"a.pdf"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.Image || v.Secure || !v.Document {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestEqualsOption(t *testing.T) {
+	var v struct {
+		IsZero bool `json:"zero,equals"`
+		IsFive bool `json:"five,equals,5"`
+		// The argument is decoded as JSON into the method's time.Time parameter
+		Early bool `json:"early,unix,equals,Before,\"\\\"2024-01-01T00:00:00Z\\\"\""`
+		Late  bool `json:"late,unix,equals!,Before,\"\\\"2024-01-01T00:00:00Z\\\"\""`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"zero":0,"five":5,"early":1700000000,"late":1700000000}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.IsZero || !v.IsFive || !v.Early || v.Late {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func FuzzPredicateOptions(f *testing.F) {
+	msontest.Fuzz(f, predicates{})
+}
+
+type money struct {
+	Cents int64
+}
+
+func (m money) Is(cents int64) bool {
+	return m.Cents == cents
+}
+
+type order struct {
+	Free     bool   `json:"total,func,Money,equals,Is,0"`
+	Code     string `json:"code,func,Upper"`
+	Quantity int    `json:"quantity,func,Double"`
+}
+
+func (o *order) Money(cents int64) money {
+	return money{Cents: cents}
+}
+
+func (o *order) Upper(s string) string {
+	return strings.ToUpper(s)
+}
+
+func (o *order) Double(n int) (int, error) {
+	if n < 0 {
+		return 0, errors.New("negative quantity")
+	}
+
+	return 2 * n, nil
+}
+
+func TestFuncOption(t *testing.T) {
+	var o order
+
+	if err := mson.Unmarshal([]byte(`{"total":0,"code":"abc","quantity":3}`), &o); err != nil {
+		t.Fatal(err)
+	}
+
+	if !o.Free || o.Code != "ABC" || o.Quantity != 6 {
+		t.Fatalf("got %+v", o)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"quantity":-1}`), &o); err == nil || !strings.Contains(err.Error(), "negative quantity") {
+		t.Fatalf("got %v", err)
+	}
+}
+
+type payment struct {
+	Kind   string  `json:"kind"`
+	Amount float64 `json:"amount,when=kind=cents,divide,100"`
+	Code   string  `json:"code,when!=kind=cents,func,Upper"`
+}
+
+func (p *payment) Upper(s string) string {
+	return strings.ToUpper(s)
+}
+
+func TestWhenOption(t *testing.T) {
+	var p payment
+
+	if err := mson.Unmarshal([]byte(`{"kind":"cents","amount":1250,"code":"abc"}`), &p); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Amount != 12.5 || p.Code != "abc" {
+		t.Fatalf("got %+v", p)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"kind":"units","amount":1250,"code":"abc"}`), &p); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Amount != 1250 || p.Code != "ABC" {
+		t.Fatalf("got %+v", p)
+	}
+}
+
+func TestDefineAlias(t *testing.T) {
+	mson.DefineAlias("toptwo", "sort,desc,limit,2")
+
+	var v struct {
+		Scores []int    `json:"scores,toptwo"`
+		Names  []string `json:"names,toptwo,reverse"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"scores":[3,9,5],"names":["a","c","b"]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(v.Scores, []int{9, 5}) || !reflect.DeepEqual(v.Names, []string{"b", "c"}) {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestOneOf(t *testing.T) {
+	type status struct {
+		State string `json:"state,oneof,\"open|closed\""`
+		Kind  string `json:"kind,oneof,\"a|b\",strict"`
+	}
+
+	var v status
+	var warnings []mson.Warning
+
+	err := mson.UnmarshalWithOptions([]byte(`{"state":"pending","kind":"a"}`), &v, mson.WithWarnings(func(w mson.Warning) {
+		warnings = append(warnings, w)
+	}))
+
+	if err != nil || v.State != "pending" || len(warnings) != 1 || warnings[0].Field != "state" {
+		t.Fatalf("got %+v, %v and warnings %v", v, err, warnings)
+	}
+
+	var enumErr *mson.UnknownEnumValueError
+
+	if err := mson.Unmarshal([]byte(`{"kind":"c"}`), &v); !errors.As(err, &enumErr) || enumErr.Value != "c" {
+		t.Fatalf("got %v, want an UnknownEnumValueError", err)
+	}
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"state":"pending"}`), &v, mson.WithStrictEnums()); !errors.As(err, &enumErr) {
+		t.Fatalf("got %v, want an UnknownEnumValueError under WithStrictEnums", err)
+	}
+}
@@ -1,143 +1,885 @@
 package mson
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"io"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-func processTag(field reflect.Value, value interface{}, options []string, fieldName string) error {
-	inner := stripPointer(field)
+var verbs = map[string]bool{
+	"since":        true,
+	"until":        true,
+	"deprecated":   true,
+	"redact":       true,
+	"mask":         true,
+	"durfmt":       true,
+	"prec":         true,
+	"fmt":          true,
+	"omitempty":    true,
+	"omitzero":     true,
+	"omitif":       true,
+	"null":         true,
+	"oneof":        true,
+	"required":     true,
+	"regex":        true,
+	"sum":          true,
+	"avg":          true,
+	"min":          true,
+	"max":          true,
+	"count":        true,
+	"sort":         true,
+	"unique":       true,
+	"reverse":      true,
+	"limit":        true,
+	"filter":       true,
+	"each":         true,
+	"first":        true,
+	"last":         true,
+	"index":        true,
+	"keys":         true,
+	"values":       true,
+	"groupby":      true,
+	"encrypted":    true,
+	"hash":         true,
+	"gzip":         true,
+	"zlib":         true,
+	"snowflake":    true,
+	"ulid":         true,
+	"ksuid":        true,
+	"semver":       true,
+	"regexp":       true,
+	"template":     true,
+	"color":        true,
+	"latlng":       true,
+	"phone":        true,
+	"country":      true,
+	"currency":     true,
+	"langtag":      true,
+	"cron":         true,
+	"rate":         true,
+	"relativetime": true,
+	"interval":     true,
+	"timetrunc":    true,
+	"timeround":    true,
+	"duration":     true,
+	"unix":         true,
+	"nilslice":     true,
+	"nilmap":       true,
+	"nilstruct":    true,
+	"equals":       true,
+	"contains":     true,
+	"in":           true,
+	"between":      true,
+	"startswith":   true,
+	"endswith":     true,
+	"like":         true,
+	"func":         true,
+	"when":         true,
+	"empty":        true,
+	"fromstring":   true,
+	"tostring":     true,
+	"string":       true,
+	"numbers":      true,
+	"ordered":      true,
+	"sanitize":     true,
+	"norm":         true,
+	"htmlunescape": true,
+	"htmlescape":   true,
+	"maxlen":       true,
+	"pad":          true,
+	"slug":         true,
+	"camel":        true,
+	"pascal":       true,
+	"snake":        true,
+	"kebab":        true,
+	"path":         true,
+	"mime":         true,
+	"ext":          true,
+	"email":        true,
+	"mac":          true,
+	"port":         true,
+	"hostport":     true,
+	"hostname":     true,
+	"pem":          true,
+	"x509":         true,
+	"link":         true,
+	"add":          true,
+	"subtract":     true,
+	"multiply":     true,
+	"divide":       true,
+	"round":        true,
+	"floor":        true,
+	"ceil":         true,
+}
+
+// passiveOptions do not read the value of the field when decoding, and leave
+// its numbers as json.Number.
+var passiveOptions = map[string]bool{
+	"since":      true,
+	"until":      true,
+	"deprecated": true,
+	"redact":     true,
+	"mask":       true,
+	"durfmt":     true,
+	"prec":       true,
+	"fmt":        true,
+	"htmlescape": true,
+	"tostring":   true,
+	"omitempty":  true,
+	"omitzero":   true,
+	"omitif":     true,
+	"hash":       true,
+	"numbers":    true,
+	"ordered":    true,
+	"required":   true,
+}
+
+// nullOptions give null a meaning of their own, while the other options are
+// skipped for it.
+var nullOptions = map[string]bool{
+	"null":      true,
+	"nilslice":  true,
+	"nilmap":    true,
+	"nilstruct": true,
+	"equals":    true,
+	"func":      true,
+}
+
+type decodeState struct {
+	settings
+	data   map[string]interface{}
+	parent reflect.Value
+	jwt    bool
+
+	// source is the document being decoded and path the keys leading to
+	// the field being processed, used to locate failing values.
+	source []byte
+	path   []string
+
+	// consumed holds the keys of the current object claimed by a field.
+	consumed map[string]bool
+
+	// numbers is set while a field tagged with the numbers option is
+	// assigned, whose json.Number values are then stored as they are.
+	numbers bool
+}
+
+// fieldPath returns the dotted path of the given key within the current object.
+func (s *decodeState) fieldPath(name string) string {
+	return strings.Join(append(s.path[:len(s.path):len(s.path)], name), ".")
+}
+
+// lookup returns the incoming value of a key of the current object, with its
+// numbers normalized the way a field's value would be.
+func (s *decodeState) lookup(name string) (interface{}, bool) {
+	value, ok := s.data[s.key(name)]
+
+	if !ok {
+		return nil, false
+	}
+
+	if v, err := normalizeNumbers(value, name); err == nil {
+		value = v
+	}
+
+	return value, true
+}
+
+// processTag runs the options of a field as a pipeline of stages, each
+// passing the value on to the next, and stores the result in field. The
+// kinds of value passed between the stages are checked by checkPipeline
+// before processTag is called.
+func processTag(field reflect.Value, value interface{}, options []string, fieldName string, state *decodeState) error {
+	// The field's pointers are only allocated once a non-null value is
+	// assigned, so that null leaves them nil
+	target := derefType(field.Type())
+
+	var guarded bool
+
+	// Numbers stay json.Number until an option reads the value, since the
+	// options expect float64, so that fields without such options keep the
+	// precision of integers. Big fields parse the literal themselves.
+	numbers := state.numbers
+	state.numbers = containsOption(options, "numbers")
+	defer func() { state.numbers = numbers }()
+
+	pending := !state.numbers && !isBigType(field.Type())
+
+	for _, opt := range options {
+		parts := splitIgnoreQuoted(opt, ',')
+
+		modified := parts[0]
+		var inverted bool
+
+		if len(parts[0]) > 0 && rune(parts[0][len(parts[0])-1]) == '!' {
+			inverted = true
+			modified = parts[0][:len(parts[0])-1]
+		}
+
+		// when guards the options following it, up to the next when
+		if modified == "when" {
+			if len(parts) < 2 {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'when' requires a condition"))
+			}
+
+			guarded = evaluateWhen(state, unquote(parts[1])) == inverted
+			continue
+		}
+
+		if guarded {
+			continue
+		}
+
+		// Null leaves a nil pointer or the zero value rather than going
+		// through conversions that expect a value
+		if value == nil && !passiveOptions[modified] && !nullOptions[modified] {
+			continue
+		}
+
+		if pending && !passiveOptions[modified] {
+			v, err := normalizeNumbers(value, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value, pending = v, false
+		}
+
+		switch modified {
+		case "since", "until":
+			// Version gates only apply when a version was negotiated through UnmarshalVersion
+			if state.version == "" {
+				continue
+			}
+
+			if !admitsVersion(parts, state.version) {
+				field.Set(reflect.Zero(field.Type()))
+				return nil
+			}
+		case "deprecated":
+			message := fmt.Sprintf("field %s is deprecated", state.currentPath())
+
+			if len(parts) > 1 {
+				hint, err := strconv.Unquote(parts[1])
+
+				if err != nil {
+					hint = parts[1]
+				}
+
+				message += ": " + hint
+			}
+
+			state.warn(Warning{Field: state.currentPath(), Message: message})
+		case "redact", "mask", "durfmt", "prec", "fmt", "htmlescape", "tostring", "omitempty", "omitzero", "omitif":
+			// Only applied by Marshal
+		case "encrypted":
+			v, err := decryptValue(value, parts, target, fieldName, &state.settings)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "hash":
+			if err := verifyHash(value, parts, fieldName, state); err != nil {
+				return err
+			}
+		case "gzip", "zlib":
+			raw, err := decompressValue(value, modified, fieldName, state.maxBytes)
+
+			if err != nil {
+				return err
+			}
+
+			switch target.Kind() {
+			case reflect.Struct:
+				return unmarshal(raw, stripPointer(field).Addr().Interface(), &decodeState{settings: state.settings})
+			case reflect.String:
+				value = string(raw)
+			default:
+				value = raw
+			}
+		case "snowflake", "ulid", "ksuid":
+			// Time fields receive the timestamp embedded in the identifier
+			v, err := parseID(value, append([]string{modified}, parts[1:]...), target, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "semver":
+			v, err := parseSemver(value, parts, inverted, target, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "regexp", "template":
+			// Compiled values are pointers, so they are assigned to the field itself
+			compiled, err := compileValue(value, modified, field.Type(), fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			field.Set(compiled)
+			return nil
+		case "x509":
+			// Certificates are pointers, so they are assigned to the field itself
+			certs, err := parseCertificates(value, parts, field.Type(), fieldName, state.now())
+
+			if err != nil {
+				return err
+			}
+
+			field.Set(certs)
+			return nil
+		case "pem":
+			parsed, err := parsePEM(value, parts, field.Type(), fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			field.Set(parsed)
+			return nil
+		case "link":
+			v, found, err := extractLink(value, parts, target, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			// An absent relation leaves the field zero, like a missing key
+			if !found {
+				field.Set(reflect.Zero(field.Type()))
+				return nil
+			}
+
+			value = v
+		case "color":
+			v, err := convertColor(value, target, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "latlng":
+			v, err := convertPoint(value, parts, target, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "phone":
+			v, err := normalizePhone(value, parts, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "country":
+			v, err := normalizeCountry(value, parts, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "currency":
+			v, err := normalizeCurrency(value, parts, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "langtag":
+			v, err := parseLanguageTag(value, target, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "cron":
+			v, err := convertCron(value, target, fieldName, state.now())
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "rate":
+			v, err := convertRate(value, target, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "relativetime":
+			v, err := convertRelativeTime(value, fieldName, state.now())
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "interval":
+			v, err := convertInterval(value, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "timetrunc", "timeround":
+			if len(parts) < 2 {
+				panic(errorf(ErrInvalidTag, "mson: tag option '%s' requires a boundary argument", modified))
+			}
+
+			t, ok := value.(time.Time)
+
+			if !ok {
+				return errorf(ErrTypeMismatch, "mson: field %s is not a time; apply a conversion such as unix first", fieldName)
+			}
+
+			v, err := truncateTime(t, parts[1], modified == "timeround")
+
+			if err != nil {
+				return fmt.Errorf("mson: %w, rounding of field %s failed", err, fieldName)
+			}
+
+			value = v
+		case "null":
+			// Treats a sentinel such as "N/A" like a JSON null, leaving the field zero
+			if len(parts) < 2 {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'null' requires a sentinel argument"))
+			}
+
+			if s, ok := value.(string); value == nil || (ok && s == unquote(parts[1])) {
+				field.Set(reflect.Zero(field.Type()))
+				return nil
+			}
+		case "oneof":
+			if len(parts) < 2 {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'oneof' requires a list of accepted values"))
+			}
+
+			accepted := strings.Split(unquote(parts[1]), "|")
+			s := fmt.Sprint(value)
+
+			if !containsOption(accepted, s) {
+				if state.strictEnums || containsOption(parts[2:], "strict") {
+					return &UnknownEnumValueError{Field: fieldName, Value: s, Accepted: accepted}
+				}
+
+				state.warn(Warning{Field: state.currentPath(), Message: fmt.Sprintf("field %s received unknown value %q", state.currentPath(), s)})
+			}
+		case "required":
+			if value == nil {
+				return errorf(ErrFieldMissing, "mson: field %s is required", fieldName)
+			}
+		case "regex":
+			if err := matchPattern(value, parts, fieldName); err != nil {
+				return err
+			}
+		case "sum", "avg", "min", "max", "count":
+			if isBound(parts) {
+				if err := checkBound(value, parts, fieldName); err != nil {
+					return err
+				}
+
+				break
+			}
+
+			v, err := aggregateValue(value, modified, target, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "sort", "unique", "reverse", "limit":
+			v, err := processSlice(value, append([]string{modified}, parts[1:]...), fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "filter":
+			v, err := filterSlice(value, parts, inverted, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "each":
+			v, err := mapSlice(value, parts, target, fieldName, state)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "first", "last", "index":
+			elems, err := elements(value, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			i := elementIndex(append([]string{modified}, parts[1:]...), len(elems))
+
+			// An absent element leaves the field zero, like a missing key
+			if i < 0 || i >= len(elems) {
+				field.Set(reflect.Zero(field.Type()))
+				return nil
+			}
+
+			value = elems[i]
+
+			if obj, ok := value.(map[string]interface{}); ok && target.Kind() == reflect.Struct {
+				return decodeStruct(stripPointer(field), obj, state)
+			}
+		case "keys", "values":
+			v, err := projectMap(value, modified, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "groupby":
+			v, err := groupSlice(value, parts, target, fieldName, state)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "duration":
+			var unit string
+
+			if len(parts) > 1 {
+				unit = parts[1]
+			} else {
+				unit = "seconds"
+			}
+
+			duration, err := parseDuration(fmt.Sprint(value), unit)
+
+			if err != nil {
+				return errorf(ErrTypeMismatch, "mson: %w, conversion of field %s to time.Duration failed", err, fieldName)
+			}
+
+			if inverted {
+				value = state.now().Add(duration)
+			} else {
+				value = int64(duration)
+			}
+		case "unix":
+			var unit string
+
+			if len(parts) > 1 {
+				unit = parts[1]
+			} else {
+				unit = "seconds"
+			}
+
+			t, err := parseTime(fmt.Sprint(value), unit)
+
+			if err != nil {
+				return errorf(ErrTypeMismatch, "mson: %w, conversion of field %s to time.Time failed", err, fieldName)
+			}
+
+			value = t
+		case "nilslice", "nilmap", "nilstruct":
+			v, err := emptyValue(value, modified, inverted, target, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "equals":
+			if len(parts) > 2 {
+				// equals,<Method>,<arg> compares through a method on the value's type
+				value = callComparator(value, parts[1], unquote(parts[2])) == (!inverted)
+			} else if len(parts) > 1 {
+				arg, err := strconv.Unquote(parts[1])
+
+				if err != nil {
+					arg = parts[1]
+				}
+
+				value = compareInterfaceValue(value, arg) == (!inverted)
+			} else {
+				value = derefValue(field).IsZero() == (!inverted)
+			}
+		case "contains":
+			// Sets value to true if the field contains the argument, false otherwise
+			// Without an argument it reports that the field exists; there is no 'contains!'
+			// alternative for that form because mson ignores non-existent fields
+			if len(parts) > 1 {
+				value = containsValue(value, unquote(parts[1])) == (!inverted)
+			} else {
+				value = true
+			}
+		case "in":
+			if len(parts) < 2 {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'in' requires a list of values"))
+			}
+
+			var found bool
+
+			for _, candidate := range strings.Split(unquote(parts[1]), "|") {
+				if s, ok := value.(string); (ok && s == candidate) || compareInterfaceValue(value, candidate) {
+					found = true
+					break
+				}
+			}
+
+			value = found == (!inverted)
+		case "between":
+			if len(parts) < 3 {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'between' requires a lower and an upper bound"))
+			}
+
+			within, err := betweenValues(value, unquote(parts[1]), unquote(parts[2]))
+
+			if err != nil {
+				return fmt.Errorf("mson: %w, comparison of field %s failed", err, fieldName)
+			}
+
+			value = within == (!inverted)
+		case "startswith", "endswith", "like":
+			if len(parts) < 2 {
+				panic(errorf(ErrInvalidTag, "mson: tag option '%s' requires a pattern argument", modified))
+			}
+
+			s, ok := value.(string)
+
+			if !ok {
+				return errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+			}
+
+			var matched bool
+
+			switch pattern := unquote(parts[1]); modified {
+			case "startswith":
+				matched = strings.HasPrefix(s, pattern)
+			case "endswith":
+				matched = strings.HasSuffix(s, pattern)
+			default:
+				matched = matchGlob(pattern, s)
+			}
+
+			value = matched == (!inverted)
+		case "func":
+			if len(parts) < 2 {
+				panic(errorf(ErrInvalidTag, "mson: tag option 'func' requires a method name"))
+			}
+
+			v, err := callParentMethod(state.parent, parts[1], value, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "empty":
+			var empty bool
+
+			if v := reflect.ValueOf(value); len(parts) > 1 {
+				isZero := v.MethodByName(parts[1])
+
+				if !isZero.IsValid() || isZero.Type().NumIn() > 0 || isZero.Type().NumOut() != 1 || isZero.Type().Out(0) != reflect.TypeOf(true) {
+					panic(errorf(ErrInvalidTag, "mson: invalid function %s provided as argument to empty; function must exist on the type %s, take zero parameters, and return one boolean value", parts[1], v.Type().String()))
+				}
+				empty = isZero.Call(nil)[0].Bool()
+			} else {
+				empty = v.IsZero()
+			}
+
+			if (empty && !inverted) || (!empty && inverted) {
+				field.Set(reflect.Zero(field.Type()))
+				return nil
+			}
+		case "maxlen":
+			v, err := truncateString(value, parts, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "pad":
+			v, err := padString(value, parts, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "slug":
+			v, err := slugify(value, parts, fieldName)
+
+			if err != nil {
+				return err
+			}
+
+			value = v
+		case "camel", "pascal", "snake", "kebab":
+			v, err := convertCase(value, modified, fieldName)
+
+			if err != nil {
+				return err
+			}
 
-	for _, opt := range options {
-		parts := splitIgnoreQuoted(opt, ',')
+			value = v
+		case "mac":
+			v, err := parseMAC(value, target, fieldName)
 
-		var modified string
-		var inverted bool
+			if err != nil {
+				return err
+			}
 
-		if len(parts[0]) > 0 && rune(parts[0][len(parts[0])-1]) == '!' {
-			inverted = true
-			modified = parts[0][:len(parts[0])-1]
-		}
+			value = v
+		case "port":
+			v, err := convertPort(value, target, fieldName)
 
-		switch modified {
-		case "duration":
-			var unit string
+			if err != nil {
+				return err
+			}
 
-			if len(parts) > 1 {
-				unit = parts[1]
-			} else {
-				unit = "seconds"
+			value = v
+		case "hostport":
+			v, err := parseHostPort(value, target, fieldName)
+
+			if err != nil {
+				return err
 			}
 
-			duration, err := parseDuration(fmt.Sprint(value), unit)
+			value = v
+		case "hostname":
+			v, err := validateHostname(value, parts, fieldName)
 
 			if err != nil {
-				return fmt.Errorf("mson: %w, conversion of field %s to time.Duration failed", err, fieldName)
+				return err
 			}
 
-			if inverted {
-				value = time.Now().Add(duration)
-			} else {
-				value = int64(duration)
+			value = v
+		case "email":
+			v, err := normalizeEmail(value, parts, fieldName)
+
+			if err != nil {
+				return err
 			}
-		case "unix":
-			var unit string
 
-			if len(parts) > 1 {
-				unit = parts[1]
-			} else {
-				unit = "seconds"
+			value = v
+		case "mime", "ext":
+			validate := validateMIME
+
+			if modified == "ext" {
+				validate = validateExtension
 			}
 
-			t, err := parseTime(fmt.Sprint(value), unit)
+			v, err := validate(value, parts, fieldName)
 
 			if err != nil {
-				return fmt.Errorf("mson: %w, conversion of field %s to time.Time failed", err, fieldName)
+				return err
 			}
 
-			value = reflect.ValueOf(t)
-		case "nilslice":
-			if value == nil {
-				if !inverted {
-					if inner.Kind() != reflect.Slice {
-						return fmt.Errorf("mson: cannot convert field %s to a new slice; field is of kind %s, not a slice", fieldName, inner.Kind())
-					}
-
-					value = reflect.SliceOf(inner.Type().Elem())
-				}
-			} else if inverted {
-				v := reflect.ValueOf(value)
+			value = v
+		case "path":
+			v, err := cleanPath(value, parts, fieldName)
 
-				if v.Kind() == reflect.Slice && v.Len() == 0 {
-					value = nil
-				}
+			if err != nil {
+				return err
 			}
-		case "nilmap":
-			if value == nil {
-				if !inverted {
-					if inner.Kind() != reflect.Map {
-						return fmt.Errorf("mson: cannot convert field %s to a new map; field is of kind %s, not a map", fieldName, inner.Kind())
-					}
 
-					value = reflect.MapOf(inner.Type().Key(), inner.Type().Elem())
-				}
-			} else if inverted {
-				v := reflect.ValueOf(value)
+			value = v
+		case "htmlunescape":
+			s, ok := value.(string)
 
-				if v.Kind() == reflect.Map && v.Len() == 0 {
-					value = nil
-				}
+			if !ok {
+				return errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
 			}
-		case "equals":
-			if len(parts) > 1 {
-				arg, err := strconv.Unquote(parts[1])
 
-				if err != nil {
-					arg = parts[1]
-				}
+			value = html.UnescapeString(s)
+		case "norm":
+			v, err := normalizeString(value, parts, fieldName)
 
-				value = reflect.ValueOf(compareInterfaceValue(value, arg) == (!inverted))
-			} else {
-				value = reflect.ValueOf(inner.IsZero() == (!inverted))
+			if err != nil {
+				return err
 			}
-		case "contains":
-			// Sets value to true if the field contains the argument, false otherwise
-			// There is no 'contains!' alternative because mson ignores non-existent fields
-			value = true
-		case "empty":
-			var empty bool
 
-			if v := reflect.ValueOf(value); len(parts) > 1 {
-				isZero := v.MethodByName(parts[1])
+			value = v
+		case "sanitize":
+			value = sanitizeValue(value)
+		case "numbers":
+			// Leaves numbers as json.Number, see above
+		case "ordered":
+			v, err := orderedValue(target, containsOption(options, "numbers"), fieldName, state)
 
-				if !isZero.IsValid() || isZero.Type().NumIn() > 0 || isZero.Type().NumOut() != 1 || isZero.Type().Out(0) != reflect.TypeOf(true) {
-					panic(fmt.Errorf("mson: invalid function %s provided as argument to empty; function must exist on the type %s, take zero parameters, and return one boolean value", parts[1], v.Type().String()))
-				}
-				empty = isZero.Call(nil)[0].Bool()
-			} else {
-				empty = v.IsZero()
+			if err != nil {
+				return err
 			}
 
-			if (empty && !inverted) || (!empty && inverted) {
-				field.Set(reflect.Zero(field.Type()))
-				return nil
+			value = v
+		case "string":
+			v, err := parseStringOption(value, target, fieldName)
+
+			if err != nil {
+				return err
 			}
+
+			value = v
 		case "fromstring":
 			strValue, ok := value.(string)
 			if ok {
 				if inverted {
-					return fmt.Errorf("mson: field %s is already a string", fieldName)
+					return errorf(ErrTypeMismatch, "mson: field %s is already a string", fieldName)
+				}
+				v, err := unquoteLayers(strValue, parts, field.Type(), fieldName)
+
+				if err != nil {
+					return err
 				}
-				if err := json.Unmarshal([]byte(strValue), &value); err != nil {
-					return fmt.Errorf("%w, unquoting of field %s to %v failed", fmt.Errorf(strings.Replace(err.Error(), "json", "mson", 1)), fieldName, field.Type())
+
+				value = v
+
+				if target.Kind() == reflect.Struct && !isBigType(target) {
+					// Embedded objects are decoded with the struct's own tags
+					obj, ok := value.(map[string]interface{})
+
+					if !ok {
+						return errorf(ErrTypeMismatch, "mson: field %s does not contain a JSON object", fieldName)
+					}
+
+					return decodeStruct(stripPointer(field), obj, state)
 				}
 			} else {
 				if !inverted {
-					return fmt.Errorf("mson: field %s is not a string", fieldName)
+					return errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
 				}
 
 				value = fmt.Sprintf("%v", value)
@@ -159,71 +901,390 @@ func processTag(field reflect.Value, value interface{}, options []string, fieldN
 
 			value = v
 		default:
-			panic(fmt.Errorf("mson: unknown tag option %s", parts[0]))
+			panic(errorf(ErrUnknownOption, "mson: unknown tag option %s", parts[0]))
 		}
 	}
 
-	inner.Set(reflect.ValueOf(value))
+	if value == nil && field.Kind() == reflect.Ptr {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
 
-	return nil
+	inner := stripPointer(field)
+
+	if isBigType(inner.Type()) {
+		if value == nil {
+			inner.Set(reflect.Zero(inner.Type()))
+			return nil
+		}
+
+		v, err := parseBig(value, inner.Type(), fieldName)
+
+		if err != nil {
+			return err
+		}
+
+		inner.Set(v)
+		return nil
+	}
+
+	if state.interner != nil {
+		value = state.interner.value(value)
+	}
+
+	return assignValue(inner, value, fieldName, state)
 }
 
-func processField(field reflect.Value, metaData reflect.StructField, data map[string]interface{}) error {
-	msonTag := splitIgnoreQuoted(metaData.Tag.Get("json"), ',')
+func processField(field reflect.Value, metaData reflect.StructField, data map[string]interface{}, state *decodeState) error {
+	fieldName, tagOptions, ok := parseTag(metaData, &state.settings)
 
-	if len(msonTag) == 0 || msonTag[0] == "-" {
+	if !ok {
 		return nil
 	}
 
-	if msonTag[0] == "_" {
+	key := state.key(fieldName)
+
+	if state.proto3 {
+		key = proto3Key(data, fieldName, &state.settings)
+	}
+
+	value, ok := data[key]
+
+	if ok {
+		state.consumed[key] = true
+	}
+
+	if ok && state.emptyAsNull && value == "" {
+		switch derefType(field.Type()).Kind() {
+		case reflect.String, reflect.Interface:
+		default:
+			ok = false
+		}
+	}
+
+	// proto3 makes no difference between null and an absent field
+	if ok && state.proto3 && value == nil {
+		ok = false
+	}
+
+	if ok {
+		var previous reflect.Value
 
+		switch kind := field.Kind(); {
+		case kind == reflect.Slice && state.merge.AppendSlices, kind == reflect.Map && state.merge.MergeMaps:
+			previous = reflect.New(field.Type()).Elem()
+			previous.Set(field)
+		case kind != reflect.Slice && kind != reflect.Map && state.merge.KeepNonZero && !field.IsZero():
+			return nil
+		}
+
+		options := fieldOptions(metaData.Type, tagOptions, &state.settings)
+
+		if state.jwt {
+			options = jwtOptions(metaData, options)
+		}
+
+		checkPipeline(metaData.Type, options)
+
+		state.path = append(state.path, fieldName)
+		defer func() { state.path = state.path[:len(state.path)-1] }()
+
+		if state.sanitize {
+			value = sanitizeValue(value)
+		}
+
+		if state.resolve != nil {
+			v, err := interpolateValue(value, state.resolve, fieldName)
+
+			if err != nil {
+				return fieldError(err, state.source, state.path, &state.settings)
+			}
+
+			value = v
+		}
+
+		if state.proto3 && !hasDecodeOptions(options) {
+			v, err := proto3Value(value, field.Type(), fieldName)
+
+			if err != nil {
+				return fieldError(err, state.source, state.path, &state.settings)
+			}
+
+			value = v
+		}
+
+		if err := processTag(field, value, options, fieldName, state); err != nil {
+			return fieldError(err, state.source, state.path, &state.settings)
+		}
+
+		if previous.IsValid() {
+			mergeValue(field, previous, state.merge)
+		}
+
+		return nil
 	}
 
-	fieldName := msonTag[0]
+	options := fieldOptions(metaData.Type, tagOptions, &state.settings)
 
-	if fieldName == "" {
-		fieldName = metaData.Name
-	} else if fieldName == "_" {
-		fieldName = metaData.Name
+	if containsOption(options, "required") {
+		err := errorf(ErrFieldMissing, "mson: field %s is required", state.fieldPath(fieldName))
+		return fieldError(err, state.source, append(state.path[:len(state.path):len(state.path)], fieldName), &state.settings)
+	}
 
-		
+	if state.version != "" && requiredByVersion(options, state.version) {
+		err := errorf(ErrFieldMissing, "mson: field %s is required in version %s", state.fieldPath(fieldName), state.version)
+		return fieldError(err, state.source, append(state.path[:len(state.path):len(state.path)], fieldName), &state.settings)
+	}
 
+	if state.metadata != nil {
+		state.metadata.addUnset(state.fieldPath(fieldName))
 	}
 
-	if value, ok := data[strings.ToLower(fieldName)]; ok {
-		return processTag(field, value, msonTag[1:], fieldName)
+	if !state.merge.KeepAbsent {
+		field.Set(reflect.Zero(field.Type()))
 	}
 
-	field.Set(reflect.Zero(field.Type()))
 	return nil
 }
 
+// Unmarshal parses the JSON-encoded data and stores the result in the struct
+// pointed to by v, applying the options found in each field's tag.
 func Unmarshal(data []byte, v any) error {
-	var parsedData map[string]interface{}
+	return unmarshal(data, v, &decodeState{})
+}
 
-	err := json.Unmarshal(data, &parsedData)
+// UnmarshalVersion is like Unmarshal, but decodes data as the given API
+// version: fields whose since/until options exclude that version are left
+// zero regardless of the payload, while those whose options admit it are
+// required, and their absence is reported as ErrFieldMissing. Fields without
+// since or until options stay optional in every version.
+func UnmarshalVersion(data []byte, v any, version string) error {
+	return UnmarshalWithOptions(data, v, WithVersion(version))
+}
 
-	if err != nil {
-		return err
+// UnmarshalWithOptions is like Unmarshal, but applies the given options to
+// the decode.
+func UnmarshalWithOptions(data []byte, v any, opts ...Option) error {
+	return unmarshal(data, v, newDecodeState(opts))
+}
+
+// newDecodeState returns the state of a decode applying the given options.
+func newDecodeState(opts []Option) *decodeState {
+	state := &decodeState{}
+
+	for _, opt := range opts {
+		opt(&state.settings)
 	}
 
-	for k, v := range parsedData {
-		delete(parsedData, k)
-		parsedData[strings.ToLower(k)] = v
+	return state
+}
+
+// parseDocument parses data, which must hold a single JSON value within the
+// size limit, into v, keeping numbers as json.Number. Every entry point
+// parses its input through it.
+func parseDocument(data []byte, v any, s *settings) error {
+	if s.maxBytes > 0 && len(data) > s.maxBytes {
+		return errorf(ErrTooLarge, "mson: document of %d bytes exceeds limit of %d bytes", len(data), s.maxBytes)
 	}
 
-	rv := reflect.ValueOf(v).Elem()
-	rt := rv.Type()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
 
-	for i := 0; i < rt.NumField(); i++ {
-		field := rv.Field(i)
+	if err := dec.Decode(v); err != nil {
+		var typeErr *json.UnmarshalTypeError
 
-		if field.CanSet() {
-			err = processField(field, rt.Field(i), parsedData)
-			if err != nil {
+		if !errors.As(err, &typeErr) {
+			return fmt.Errorf("mson: %w", err)
+		}
+
+		if typeErr.Field == "" {
+			return errorf(ErrTypeMismatch, "mson: document holds a JSON %s, expected an object", typeErr.Value)
+		}
+
+		return errorf(ErrTypeMismatch, "mson: key %s holds a JSON %s, cannot decode into %s", typeErr.Field, typeErr.Value, typeErr.Type)
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("mson: invalid character after top-level value")
+	}
+
+	return nil
+}
+
+func unmarshal(data []byte, v any, state *decodeState) error {
+	if rv := reflect.ValueOf(v); rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("mson: Unmarshal requires a non-nil pointer to a struct")
+	}
+
+	if state.maxBytes > 0 && len(data) > state.maxBytes {
+		return errorf(ErrTooLarge, "mson: document of %d bytes exceeds limit of %d bytes", len(data), state.maxBytes)
+	}
+
+	if plan := fastPlanFor(v, state); plan != nil && plan.decode(data, reflect.ValueOf(v).Elem(), &state.settings) {
+		return nil
+	}
+
+	var parsedData map[string]interface{}
+
+	if err := parseDocument(data, &parsedData, &state.settings); err != nil {
+		return err
+	}
+
+	state.source = data
+
+	return decodeStruct(reflect.ValueOf(v).Elem(), parsedData, state)
+}
+
+// decodeNode decodes an object into a struct, or an array of objects into a
+// slice of structs, passing each object through prepare first. Null leaves rv
+// zero.
+func decodeNode(rv reflect.Value, node interface{}, prepare func(map[string]interface{}) map[string]interface{}, state *decodeState) error {
+	switch n := node.(type) {
+	case nil:
+		rv.Set(reflect.Zero(rv.Type()))
+		return nil
+	case map[string]interface{}:
+		if rv.Kind() != reflect.Struct {
+			return errorf(ErrTypeMismatch, "mson: document holds an object, cannot decode into %s", rv.Type())
+		}
+
+		return decodeStruct(rv, prepare(n), state)
+	case []interface{}:
+		if rv.Kind() != reflect.Slice || derefType(rv.Type().Elem()).Kind() != reflect.Struct {
+			return errorf(ErrTypeMismatch, "mson: document holds an array, cannot decode into %s", rv.Type())
+		}
+
+		slice := reflect.MakeSlice(rv.Type(), len(n), len(n))
+		path := state.path
+
+		for i, elem := range n {
+			obj, ok := elem.(map[string]interface{})
+
+			if !ok {
+				return errorf(ErrTypeMismatch, "mson: element %d is not an object", i)
+			}
+
+			state.path = append(path[:len(path):len(path)], strconv.Itoa(i))
+
+			if err := decodeStruct(stripPointer(slice.Index(i)), prepare(obj), state); err != nil {
 				return err
 			}
 		}
+
+		state.path = path
+		rv.Set(slice)
+		return nil
+	default:
+		return errorf(ErrTypeMismatch, "mson: document holds neither an object nor an array")
+	}
+}
+
+// resolveSpellings picks the value of keys differing only in case the same
+// way on every decode, rather than as map iteration happens to reach them:
+// the key spelled like a field wins, or else the first in sorted order.
+func resolveSpellings(t reflect.Type, data, parsedData map[string]interface{}, state *decodeState) {
+	keys := make([]string, 0, len(data))
+
+	for k := range data {
+		keys = append(keys, k)
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+
+	for _, k := range keys {
+		parsedData[state.key(k)] = data[k]
+	}
+
+	for _, f := range structFields(t, &state.settings) {
+		name, _, _ := parseTag(f, &state.settings)
+
+		if v, ok := data[name]; ok {
+			parsedData[state.key(name)] = v
+		}
+	}
+}
+
+func decodeStruct(rv reflect.Value, data map[string]interface{}, state *decodeState) error {
+	var parsedData map[string]interface{}
+	var claimed map[string]bool
+
+	if state.arena != nil {
+		parsedData, claimed = state.arena.object(len(data)), state.arena.set()
+	} else {
+		parsedData, claimed = make(map[string]interface{}, len(data)), map[string]bool{}
+	}
+
+	for k, v := range data {
+		parsedData[state.key(k)] = v
+	}
+
+	if len(parsedData) < len(data) {
+		resolveSpellings(rv.Type(), data, parsedData, state)
+	}
+
+	prevData, parent, consumed := state.data, state.parent, state.consumed
+	state.data, state.parent, state.consumed = parsedData, rv, claimed
+	defer func() { state.data, state.parent, state.consumed = prevData, parent, consumed }()
+
+	for _, f := range structFields(rv.Type(), &state.settings) {
+		field, ok := fieldByIndex(rv, f.Index, false)
+
+		// Embedded structs behind nil pointers are only allocated for the
+		// keys of their fields, as encoding/json does
+		if !ok {
+			if name, _, _ := parseTag(f, &state.settings); parsedData[state.key(name)] == nil {
+				continue
+			}
+
+			field, ok = fieldByIndex(rv, f.Index, true)
+		}
+
+		if !ok || !field.CanSet() {
+			continue
+		}
+
+		if name, options, _ := parseTag(f, &state.settings); skippedInterface(f, options, &state.settings) {
+			if _, present := parsedData[state.key(name)]; present {
+				state.consumed[state.key(name)] = true
+
+				if err := reportSkipped(name, fmt.Sprintf("has interface type %s", f.Type), state); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		err := processField(field, f, parsedData, state)
+		if err != nil {
+			return err
+		}
+	}
+
+	if state.skipReport != SkipSilently {
+		if err := reportUnexported(rv, state); err != nil {
+			return err
+		}
+	}
+
+	if state.onWarning != nil || state.metadata != nil {
+		var unknown []string
+
+		for k := range data {
+			if !state.consumed[state.key(k)] {
+				unknown = append(unknown, k)
+			}
+		}
+
+		sort.Strings(unknown)
+
+		for _, k := range unknown {
+			state.warn(Warning{Field: state.fieldPath(k), Message: fmt.Sprintf("key %s does not match any field and was ignored", k)})
+
+			if state.metadata != nil {
+				state.metadata.addUnused(state.fieldPath(k))
+			}
+		}
 	}
 
 	return nil
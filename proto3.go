@@ -0,0 +1,97 @@
+package mson
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// proto3Key returns the key under which data holds a field, trying the
+// lowerCamelCase and snake_case forms of its name when the name itself is
+// absent, as protojson accepts both.
+func proto3Key(data map[string]interface{}, fieldName string, s *settings) string {
+	if _, ok := data[s.key(fieldName)]; ok {
+		return s.key(fieldName)
+	}
+
+	for _, verb := range []string{"camel", "snake"} {
+		name, _ := convertCase(fieldName, verb, fieldName)
+
+		if _, ok := data[s.key(name.(string))]; ok {
+			return s.key(name.(string))
+		}
+	}
+
+	return s.key(fieldName)
+}
+
+// hasDecodeOptions reports whether any of the options applies on Unmarshal,
+// as opposed to only controlling Marshal.
+func hasDecodeOptions(options []string) bool {
+	for _, opt := range options {
+		switch splitIgnoreQuoted(opt, ',')[0] {
+		case "redact", "mask", "durfmt", "prec", "fmt", "htmlescape", "tostring", "omitempty", "omitzero", "omitif":
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+// proto3Value converts the string forms the proto3 JSON mapping uses for
+// 64-bit integers, special floats, durations, timestamps and bytes into the
+// field's type. Other values are returned unchanged.
+func proto3Value(value interface{}, t reflect.Type, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return value, nil
+	}
+
+	target := derefType(t)
+
+	switch {
+	case target == durationType:
+		// Durations are written in seconds only, as in "3.5s"
+		seconds, found := strings.CutSuffix(s, "s")
+		_, ferr := strconv.ParseFloat(seconds, 64)
+		d, err := time.ParseDuration(s)
+
+		if !found || ferr != nil || err != nil {
+			return nil, errorf(ErrTypeMismatch, "mson: field %s holds %q, which is not a proto3 duration", fieldName, s)
+		}
+
+		return d, nil
+	case target == timeType:
+		ts, err := time.Parse(time.RFC3339Nano, s)
+
+		if err != nil {
+			return nil, errorf(ErrTypeMismatch, "mson: field %s holds %q, which is not an RFC 3339 timestamp", fieldName, s)
+		}
+
+		return ts, nil
+	case target.Kind() == reflect.Slice && target.Elem().Kind() == reflect.Uint8:
+		// Both the standard and the URL-safe alphabet are accepted, padded or not
+		trimmed := strings.TrimRight(s, "=")
+
+		for _, enc := range []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding} {
+			if b, err := enc.DecodeString(trimmed); err == nil {
+				return reflect.ValueOf(b).Convert(target).Interface(), nil
+			}
+		}
+
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not valid base64", fieldName)
+	}
+
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return parseStringOption(value, target, fieldName)
+	}
+
+	return value, nil
+}
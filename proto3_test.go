@@ -0,0 +1,48 @@
+package mson_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+func TestWithProto3(t *testing.T) {
+	type message struct {
+		UserID    int64         `json:"user_id"`
+		Ratio     float64       `json:"ratio"`
+		Timeout   time.Duration `json:"timeout"`
+		CreatedAt time.Time     `json:"created_at"`
+		Payload   []byte        `json:"payload"`
+		Note      string        `json:"note"`
+		Count     float64       `json:"count,multiply,2"`
+	}
+
+	data := []byte(`{"userId":"9007199254740993","ratio":"NaN","timeout":"3.5s","createdAt":"2024-01-02T03:04:05.5Z","payload":"AP-_","note":null,"count":3}`)
+
+	v := message{Note: "stale"}
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithProto3()); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.UserID != 9007199254740993 || v.Ratio == v.Ratio || v.Timeout != 3500*time.Millisecond || v.Note != "" || v.Count != 6 {
+		t.Errorf("got %+v", v)
+	}
+
+	if !v.CreatedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 5e8, time.UTC)) || !bytes.Equal(v.Payload, []byte{0, 0xff, 0xbf}) {
+		t.Errorf("got %v and %v", v.CreatedAt, v.Payload)
+	}
+
+	for _, data := range []string{`{"timeout":"1m"}`, `{"createdAt":"yesterday"}`, `{"payload":"***"}`} {
+		if err := mson.UnmarshalWithOptions([]byte(data), &v, mson.WithProto3()); err == nil {
+			t.Errorf("decoded invalid value %s", data)
+		}
+	}
+
+	// Without the option the keys are matched by name alone
+	if err := mson.Unmarshal([]byte(`{"userId":"1"}`), &v); err != nil || v.UserID != 0 {
+		t.Errorf("got %+v, %v", v, err)
+	}
+}
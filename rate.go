@@ -0,0 +1,105 @@
+package mson
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate is a number of events per interval as produced by the rate tag option.
+type Rate struct {
+	Count    float64       `json:"count"`
+	Interval time.Duration `json:"interval"`
+}
+
+// PerSecond returns the rate normalized to events per second.
+func (r Rate) PerSecond() float64 {
+	if r.Interval <= 0 {
+		return 0
+	}
+
+	return r.Count / r.Interval.Seconds()
+}
+
+func (r Rate) String() string {
+	return strconv.FormatFloat(r.Count, 'f', -1, 64) + "/" + r.Interval.String()
+}
+
+var rateUnits = map[string]time.Duration{
+	"ns": time.Nanosecond, "us": time.Microsecond, "µs": time.Microsecond, "ms": time.Millisecond,
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour, "days": 24 * time.Hour,
+}
+
+var rateMultipliers = map[byte]float64{'k': 1e3, 'K': 1e3, 'M': 1e6, 'G': 1e9}
+
+func parseRate(s string) (Rate, error) {
+	count, interval, ok := strings.Cut(s, "/")
+
+	if !ok {
+		count, interval, ok = strings.Cut(s, " per ")
+	}
+
+	if !ok {
+		return Rate{}, fmt.Errorf("rate %q must have the form <count>/<interval>", s)
+	}
+
+	count = strings.TrimSpace(count)
+	multiplier := 1.0
+
+	if len(count) > 0 {
+		if m, ok := rateMultipliers[count[len(count)-1]]; ok {
+			multiplier = m
+			count = count[:len(count)-1]
+		}
+	}
+
+	n, err := strconv.ParseFloat(count, 64)
+
+	if err != nil || n < 0 {
+		return Rate{}, fmt.Errorf("invalid count in rate %q", s)
+	}
+
+	// The interval may carry its own length, as in 100/5m
+	interval = strings.TrimSpace(interval)
+	digits := strings.TrimRight(interval, "abcdefghijklmnopqrstuvwxyzµ")
+	length := 1.0
+
+	if digits != "" {
+		if length, err = strconv.ParseFloat(strings.TrimSpace(digits), 64); err != nil || length <= 0 {
+			return Rate{}, fmt.Errorf("invalid interval in rate %q", s)
+		}
+	}
+
+	unit, ok := rateUnits[strings.TrimSpace(interval[len(digits):])]
+
+	if !ok {
+		return Rate{}, fmt.Errorf("unknown interval unit in rate %q", s)
+	}
+
+	return Rate{Count: n * multiplier, Interval: time.Duration(length * float64(unit))}, nil
+}
+
+func convertRate(value interface{}, target reflect.Type, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	r, err := parseRate(s)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, conversion of field %s to a rate failed", err, fieldName)
+	}
+
+	if target.Kind() == reflect.Float64 {
+		return r.PerSecond(), nil
+	}
+
+	return r, nil
+}
@@ -0,0 +1,102 @@
+package mson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+func addUnits(t time.Time, n int, unit string) (time.Time, error) {
+	switch strings.TrimSuffix(unit, "s") {
+	case "sec", "second":
+		return t.Add(time.Duration(n) * time.Second), nil
+	case "min", "minute":
+		return t.Add(time.Duration(n) * time.Minute), nil
+	case "hr", "hour":
+		return t.Add(time.Duration(n) * time.Hour), nil
+	case "day":
+		return t.AddDate(0, 0, n), nil
+	case "week":
+		return t.AddDate(0, 0, 7*n), nil
+	case "month":
+		return t.AddDate(0, n, 0), nil
+	case "year":
+		return t.AddDate(n, 0, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("unknown time unit %q", unit)
+}
+
+func parseRelativeTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	words := strings.Fields(strings.ToLower(s))
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch strings.Join(words, " ") {
+	case "now", "just now":
+		return now, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return midnight.AddDate(0, 0, 1), nil
+	}
+
+	sign := 1
+
+	switch {
+	case len(words) == 2 && (words[0] == "last" || words[0] == "next"):
+		if words[0] == "last" {
+			sign = -1
+		}
+
+		return addUnits(now, sign, words[1])
+	case len(words) == 3 && words[2] == "ago":
+		sign = -1
+	case len(words) == 3 && words[0] == "in":
+		words = words[1:]
+	case len(words) == 4 && words[2] == "from" && words[3] == "now":
+	default:
+		return time.Time{}, fmt.Errorf("unrecognized relative time %q", s)
+	}
+
+	n, ok := numberWords[words[0]]
+
+	if !ok {
+		conv, err := strconv.Atoi(words[0])
+
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unrecognized relative time %q", s)
+		}
+
+		n = conv
+	}
+
+	return addUnits(now, sign*n, words[1])
+}
+
+func convertRelativeTime(value interface{}, fieldName string, now time.Time) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	t, err := parseRelativeTime(s, now)
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, conversion of field %s to time.Time failed", err, fieldName)
+	}
+
+	return t, nil
+}
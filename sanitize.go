@@ -0,0 +1,44 @@
+package mson
+
+import (
+	"strings"
+	"unicode"
+)
+
+// sanitizeString trims whitespace and byte order marks from both ends of s and
+// drops control characters other than tabs and line breaks, which are kept
+// inside the text.
+func sanitizeString(s string) string {
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '\uFEFF'
+	})
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			return r
+		case unicode.IsControl(r), r == '\uFEFF':
+			return -1
+		}
+
+		return r
+	}, s)
+}
+
+// sanitizeValue applies sanitizeString to every string held by value.
+func sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return sanitizeString(v)
+	case []interface{}:
+		for i, e := range v {
+			v[i] = sanitizeValue(e)
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = sanitizeValue(e)
+		}
+	}
+
+	return value
+}
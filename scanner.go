@@ -0,0 +1,239 @@
+package mson
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// The functions below scan a JSON stream byte by byte to move past the parts
+// of a document that are not decoded, without building tokens or values for
+// them, so that skipping over a large value costs little more than reading
+// it. They check the structure of what they skip, but not every detail of
+// its syntax, which is left to the decoding of the values that are kept.
+
+// peekByte returns the next byte of r that is not whitespace, without
+// consuming it.
+func peekByte(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+
+		if err != nil {
+			return 0, err
+		}
+
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+
+		return b, r.UnreadByte()
+	}
+}
+
+// expectByte consumes the next byte of r that is not whitespace, which must
+// be one of want, and returns it.
+func expectByte(r *bufio.Reader, want string) (byte, error) {
+	b, err := peekByte(r)
+
+	if err != nil {
+		return 0, unexpectedEOF(err)
+	}
+
+	for i := 0; i < len(want); i++ {
+		if b == want[i] {
+			return r.ReadByte()
+		}
+	}
+
+	return 0, fmt.Errorf("mson: invalid character %q in document", b)
+}
+
+// skipJSONValue consumes the value that comes next in r.
+func skipJSONValue(r *bufio.Reader) error {
+	// open holds the opening bracket of each enclosing object or array
+	var open []byte
+
+	for {
+		b, err := peekByte(r)
+
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+
+		switch b {
+		case '{', '[':
+			r.ReadByte()
+
+			if c, err := peekByte(r); err == nil && c == b+2 {
+				// An empty object or array closes right away
+				r.ReadByte()
+				break
+			}
+
+			open = append(open, b)
+
+			if b == '{' {
+				if err := skipJSONKey(r); err != nil {
+					return err
+				}
+			}
+
+			continue
+		case '"':
+			if err := skipJSONString(r); err != nil {
+				return err
+			}
+		default:
+			if err := skipJSONLiteral(r); err != nil {
+				return err
+			}
+		}
+
+		// A value ended, and with it maybe some of the enclosing ones
+		for len(open) > 0 {
+			top := open[len(open)-1]
+			c, err := expectByte(r, ",]}")
+
+			if err != nil {
+				return err
+			}
+
+			if c == ',' {
+				if top == '{' {
+					if err := skipJSONKey(r); err != nil {
+						return err
+					}
+				}
+
+				break
+			}
+
+			if c != top+2 {
+				return fmt.Errorf("mson: invalid character %q in document", c)
+			}
+
+			open = open[:len(open)-1]
+		}
+
+		if len(open) == 0 {
+			return nil
+		}
+	}
+}
+
+// skipJSONKey consumes the key of an object member and the colon after it.
+func skipJSONKey(r *bufio.Reader) error {
+	if err := skipJSONString(r); err != nil {
+		return err
+	}
+
+	_, err := expectByte(r, ":")
+	return err
+}
+
+// skipJSONString consumes the string that comes next in r.
+func skipJSONString(r *bufio.Reader) error {
+	if _, err := expectByte(r, `"`); err != nil {
+		return err
+	}
+
+	for {
+		b, err := r.ReadByte()
+
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+
+		switch {
+		case b == '"':
+			return nil
+		case b == '\\':
+			if _, err := r.ReadByte(); err != nil {
+				return unexpectedEOF(err)
+			}
+		case b < 0x20:
+			return fmt.Errorf("mson: invalid character %q in string", b)
+		}
+	}
+}
+
+// readJSONKey consumes the string that comes next in r and returns it.
+func readJSONKey(r *bufio.Reader) (string, error) {
+	if _, err := expectByte(r, `"`); err != nil {
+		return "", err
+	}
+
+	raw := []byte{'"'}
+	escaped := false
+
+	for {
+		b, err := r.ReadByte()
+
+		if err != nil {
+			return "", unexpectedEOF(err)
+		}
+
+		raw = append(raw, b)
+
+		switch {
+		case b == '"':
+			if !escaped {
+				return string(raw[1 : len(raw)-1]), nil
+			}
+
+			var key string
+			err := json.Unmarshal(raw, &key)
+			return key, err
+		case b == '\\':
+			escaped = true
+
+			if b, err = r.ReadByte(); err != nil {
+				return "", unexpectedEOF(err)
+			}
+
+			raw = append(raw, b)
+		case b < 0x20:
+			return "", fmt.Errorf("mson: invalid character %q in string", b)
+		}
+	}
+}
+
+// skipJSONLiteral consumes the number, true, false or null that comes next
+// in r.
+func skipJSONLiteral(r *bufio.Reader) error {
+	n := 0
+
+	for {
+		b, err := r.ReadByte()
+
+		if err == io.EOF && n > 0 {
+			return nil
+		}
+
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+
+		switch {
+		case '0' <= b && b <= '9', 'a' <= b && b <= 'z', b == '-', b == '+', b == '.', b == 'E':
+			n++
+			continue
+		}
+
+		if n == 0 {
+			return fmt.Errorf("mson: invalid character %q in document", b)
+		}
+
+		return r.UnreadByte()
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
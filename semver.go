@@ -0,0 +1,231 @@
+package mson
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version as produced by the semver tag option.
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	Prerelease string
+	Build      string
+}
+
+// ParseVersion parses a semantic version such as "v1.2.3-rc.1+build.5". A
+// missing minor or patch number is treated as zero.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+
+	rest := strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		v.Build = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.Prerelease = rest[i+1:]
+		rest = rest[:i]
+
+		if v.Prerelease == "" {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+	}
+
+	numbers := strings.Split(rest, ".")
+
+	if len(numbers) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	for i, n := range numbers {
+		conv, err := strconv.ParseUint(n, 10, 64)
+
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+
+		switch i {
+		case 0:
+			v.Major = conv
+		case 1:
+			v.Minor = conv
+		case 2:
+			v.Patch = conv
+		}
+	}
+
+	return v, nil
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+
+	return s
+}
+
+// Compare returns -1, 0 or 1 depending on whether v has lower, equal or higher
+// precedence than o. Build metadata does not affect precedence.
+func (v Version) Compare(o Version) int {
+	for _, pair := range [][2]uint64{{v.Major, o.Major}, {v.Minor, o.Minor}, {v.Patch, o.Patch}} {
+		if pair[0] < pair[1] {
+			return -1
+		} else if pair[0] > pair[1] {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+
+	as := strings.Split(v.Prerelease, ".")
+	bs := strings.Split(o.Prerelease, ".")
+
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aerr := strconv.ParseUint(as[i], 10, 64)
+		bn, berr := strconv.ParseUint(bs[i], 10, 64)
+
+		switch {
+		case aerr == nil && berr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aerr == nil:
+			return -1
+		case berr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+
+	return 0
+}
+
+// Satisfies reports whether v matches the constraint, which is made of
+// comparisons (=, !=, >, >=, <, <=, ~, ^) separated by spaces or "&&", with
+// alternatives separated by "||".
+func (v Version) Satisfies(constraint string) (bool, error) {
+	for _, alternative := range strings.Split(constraint, "||") {
+		ok := true
+
+		for _, comparison := range strings.Fields(strings.ReplaceAll(alternative, "&&", " ")) {
+			matched, err := v.satisfiesComparison(comparison)
+
+			if err != nil {
+				return false, err
+			}
+
+			ok = ok && matched
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (v Version) satisfiesComparison(comparison string) (bool, error) {
+	rest := strings.TrimLeft(comparison, "=!<>~^")
+	op := comparison[:len(comparison)-len(rest)]
+
+	bound, err := ParseVersion(rest)
+
+	if err != nil {
+		return false, err
+	}
+
+	cmp := v.Compare(bound)
+
+	switch op {
+	case "", "=", "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case "~":
+		// Allows patch-level changes
+		return cmp >= 0 && v.Major == bound.Major && v.Minor == bound.Minor, nil
+	case "^":
+		// Allows changes that do not modify the left-most non-zero number
+		if cmp < 0 || v.Major != bound.Major {
+			return false, nil
+		}
+		if bound.Major == 0 && v.Minor != bound.Minor {
+			return false, nil
+		}
+		return bound.Major != 0 || bound.Minor != 0 || v.Patch == bound.Patch, nil
+	}
+
+	return false, fmt.Errorf("invalid version comparison %q", comparison)
+}
+
+func parseSemver(value interface{}, parts []string, inverted bool, target reflect.Type, fieldName string) (interface{}, error) {
+	v, err := ParseVersion(fmt.Sprint(value))
+
+	if err != nil {
+		return nil, fmt.Errorf("mson: %w, parsing of field %s as a semantic version failed", err, fieldName)
+	}
+
+	if len(parts) > 1 {
+		constraint, err := strconv.Unquote(parts[1])
+
+		if err != nil {
+			constraint = parts[1]
+		}
+
+		ok, err := v.Satisfies(constraint)
+
+		if err != nil {
+			panic(errorf(ErrInvalidTag, "mson: tag option 'semver' received invalid constraint %s: %w", parts[1], err))
+		}
+
+		return ok != inverted, nil
+	}
+
+	if target.Kind() == reflect.String {
+		return v.String(), nil
+	}
+
+	return v, nil
+}
@@ -0,0 +1,83 @@
+package mson
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"strings"
+)
+
+// A SignatureVerifier checks the signature of a raw payload, returning an
+// error wrapping ErrInvalidSignature when it does not match.
+type SignatureVerifier interface {
+	Verify(payload []byte) error
+}
+
+// The SignatureVerifierFunc type adapts a function to a SignatureVerifier.
+type SignatureVerifierFunc func(payload []byte) error
+
+// Verify calls f(payload).
+func (f SignatureVerifierFunc) Verify(payload []byte) error {
+	return f(payload)
+}
+
+// NewHMACVerifier returns a verifier checking that signature is the HMAC of
+// the payload under key, as in NewHMACVerifier(sha256.New, secret, header).
+// The signature may be hex or base64 encoded and carry an algorithm prefix
+// such as "sha256=".
+func NewHMACVerifier(h func() hash.Hash, key []byte, signature string) SignatureVerifier {
+	return SignatureVerifierFunc(func(payload []byte) error {
+		mac := hmac.New(h, key)
+		mac.Write(payload)
+
+		if !hmac.Equal(mac.Sum(nil), decodeSignature(signature)) {
+			return errorf(ErrInvalidSignature, "mson: HMAC signature does not match the payload")
+		}
+
+		return nil
+	})
+}
+
+// NewEd25519Verifier returns a verifier checking that signature is the
+// Ed25519 signature of the payload by the given public key.
+func NewEd25519Verifier(publicKey ed25519.PublicKey, signature []byte) SignatureVerifier {
+	return SignatureVerifierFunc(func(payload []byte) error {
+		if len(publicKey) != ed25519.PublicKeySize || !ed25519.Verify(publicKey, payload, signature) {
+			return errorf(ErrInvalidSignature, "mson: Ed25519 signature does not match the payload")
+		}
+
+		return nil
+	})
+}
+
+func decodeSignature(signature string) []byte {
+	// Drop an algorithm prefix, which base64 padding cannot be mistaken for
+	if _, rest, ok := strings.Cut(signature, "="); ok && strings.TrimRight(rest, "=") != "" {
+		signature = rest
+	}
+
+	if b, err := hex.DecodeString(signature); err == nil {
+		return b
+	}
+
+	for _, enc := range []*base64.Encoding{base64.RawStdEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(strings.TrimRight(signature, "=")); err == nil {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalSigned is like UnmarshalWithOptions, but first checks the
+// signature of data with verifier, so that the options of the tags never run
+// on a payload that was tampered with.
+func UnmarshalSigned(data []byte, v any, verifier SignatureVerifier, opts ...Option) error {
+	if err := verifier.Verify(data); err != nil {
+		return err
+	}
+
+	return unmarshal(data, v, newDecodeState(opts))
+}
@@ -0,0 +1,65 @@
+package mson_test
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+type webhook struct {
+	Event string `json:"event,snake"`
+}
+
+func TestUnmarshalSignedHMAC(t *testing.T) {
+	key := []byte("secret")
+	payload := []byte(`{"event":"OrderPaid","extra":1}`)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	var md mson.Metadata
+	var v webhook
+
+	if err := mson.UnmarshalSigned(payload, &v, mson.NewHMACVerifier(sha256.New, key, signature), mson.WithMetadata(&md)); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Event != "order_paid" || len(md.Unused) != 1 {
+		t.Fatalf("got %+v and unused keys %v", v, md.Unused)
+	}
+
+	tampered := []byte(`{"event":"OrderRefunded"}`)
+
+	if err := mson.UnmarshalSigned(tampered, &v, mson.NewHMACVerifier(sha256.New, key, signature)); !errors.Is(err, mson.ErrInvalidSignature) {
+		t.Fatalf("got %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestUnmarshalSignedEd25519(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte(`{"event":"Ping"}`)
+	signature := ed25519.Sign(private, payload)
+
+	var v webhook
+
+	if err := mson.UnmarshalSigned(payload, &v, mson.NewEd25519Verifier(public, signature)); err != nil || v.Event != "ping" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	signature[0] ^= 1
+
+	if err := mson.UnmarshalSigned(payload, &v, mson.NewEd25519Verifier(public, signature)); !errors.Is(err, mson.ErrInvalidSignature) {
+		t.Fatalf("got %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,84 @@
+package mson
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// A SkipReport selects what decoding does about keys of the document that
+// name fields mson cannot decode into: unexported fields, and fields of an
+// interface type with methods, for which mson has no concrete type to
+// create. Such fields are skipped whatever the report.
+type SkipReport int
+
+const (
+	// SkipSilently skips the fields without a word, the default. Fields
+	// of an interface type with methods are then decoded like any other
+	// and fail unless the value implements the interface.
+	SkipSilently SkipReport = iota
+
+	// SkipWithWarning raises a Warning for each skipped field.
+	SkipWithWarning
+
+	// SkipWithError fails the decode with an error wrapping ErrSkippedField.
+	SkipWithError
+)
+
+// WithSkippedFields reports the keys of the document that name a field mson
+// skips, as r asks.
+func WithSkippedFields(r SkipReport) Option {
+	return func(s *settings) {
+		s.skipReport = r
+	}
+}
+
+// skippedInterface reports whether f holds an interface with methods that
+// WithSkippedFields makes decoding skip. Fields with options are left to
+// them, since an option such as func may produce a suitable value.
+func skippedInterface(f reflect.StructField, options []string, s *settings) bool {
+	t := derefType(f.Type)
+
+	return s.skipReport != SkipSilently && t.Kind() == reflect.Interface && t.NumMethod() > 0 && len(options) == 0
+}
+
+// reportSkipped reports the skipped field holding a key of the document.
+func reportSkipped(name, reason string, state *decodeState) error {
+	message := fmt.Sprintf("field %s %s and was skipped", state.fieldPath(name), reason)
+
+	if state.skipReport == SkipWithError {
+		return errorf(ErrSkippedField, "mson: %s", message)
+	}
+
+	state.warn(Warning{Field: state.fieldPath(name), Message: message})
+	return nil
+}
+
+// reportUnexported reports the unexported fields of rv named by a key of the
+// current object that no other field claimed.
+func reportUnexported(rv reflect.Value, state *decodeState) error {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+
+		if f.IsExported() || f.Anonymous {
+			continue
+		}
+
+		name, _, ok := parseTag(f, &state.settings)
+		key := state.key(name)
+
+		if _, present := state.data[key]; !ok || !present || state.consumed[key] {
+			continue
+		}
+
+		// The key was accounted for, so it is not reported as unknown too
+		state.consumed[key] = true
+
+		if err := reportSkipped(name, "is unexported", state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,394 @@
+package mson
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// elements returns the elements of an array as []interface{}, the form the
+// slice options pass on to each other. Options such as func and each may
+// have produced a slice of another type, whose elements are copied.
+func elements(value interface{}, fieldName string) ([]interface{}, error) {
+	if elems, ok := value.([]interface{}); ok {
+		return elems, nil
+	}
+
+	v := reflect.ValueOf(value)
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not an array", fieldName)
+	}
+
+	elems := make([]interface{}, v.Len())
+
+	for i := range elems {
+		elems[i] = v.Index(i).Interface()
+	}
+
+	return elems, nil
+}
+
+func lessValues(a, b reflect.Value) (bool, bool) {
+	for a.Kind() == reflect.Interface && !a.IsNil() {
+		a = a.Elem()
+	}
+
+	for b.Kind() == reflect.Interface && !b.IsNil() {
+		b = b.Elem()
+	}
+
+	switch {
+	case a.CanInt() && b.CanInt():
+		return a.Int() < b.Int(), true
+	case a.CanUint() && b.CanUint():
+		return a.Uint() < b.Uint(), true
+	case a.CanFloat() && b.CanFloat():
+		return a.Float() < b.Float(), true
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return a.String() < b.String(), true
+	}
+
+	return false, false
+}
+
+func processSlice(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	elems, err := elements(value, fieldName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := append([]interface{}(nil), elems...)
+
+	switch parts[0] {
+	case "sort":
+		desc := len(parts) > 1 && parts[1] == "desc"
+		comparable := true
+
+		sort.SliceStable(out, func(i, j int) bool {
+			a, b := reflect.ValueOf(out[i]), reflect.ValueOf(out[j])
+
+			if desc {
+				a, b = b, a
+			}
+
+			less, ok := lessValues(a, b)
+			comparable = comparable && ok
+
+			return less
+		})
+
+		if !comparable {
+			return nil, errorf(ErrTypeMismatch, "mson: field %s holds elements that cannot be sorted", fieldName)
+		}
+	case "unique":
+		seen := map[interface{}]bool{}
+		unique := out[:0]
+
+		for _, e := range out {
+			if e != nil && !reflect.TypeOf(e).Comparable() {
+				return nil, errorf(ErrTypeMismatch, "mson: field %s holds elements that cannot be compared", fieldName)
+			}
+
+			if !seen[e] {
+				seen[e] = true
+				unique = append(unique, e)
+			}
+		}
+
+		out = unique
+	case "reverse":
+		for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+			out[i], out[j] = out[j], out[i]
+		}
+	case "limit":
+		if len(parts) < 2 {
+			panic(errorf(ErrInvalidTag, "mson: tag option 'limit' requires a length argument"))
+		}
+
+		n, err := strconv.Atoi(parts[1])
+
+		if err != nil {
+			panic(errorf(ErrInvalidTag, "mson: tag option 'limit' received invalid argument %s", parts[1]))
+		}
+
+		// A negative limit keeps the last elements instead of the first
+		if n < 0 && -n < len(out) {
+			out = out[len(out)+n:]
+		} else if n >= 0 && n < len(out) {
+			out = out[:n]
+		}
+	}
+
+	return out, nil
+}
+
+func elementIndex(parts []string, length int) int {
+	switch parts[0] {
+	case "first":
+		return 0
+	case "last":
+		return length - 1
+	}
+
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'index' requires a position argument"))
+	}
+
+	i, err := strconv.Atoi(parts[1])
+
+	if err != nil {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'index' received invalid argument %s", parts[1]))
+	}
+
+	// Negative positions count from the end
+	if i < 0 {
+		i += length
+	}
+
+	return i
+}
+
+var predicateOperators = []string{"!=", ">=", "<=", "=", ">", "<"}
+
+// matchesPredicate evaluates a filter condition of the form [key]<op><value>
+// against an element, where key selects a property of object elements and op
+// is one of =, !=, >, >=, < or <=. The conditions "nonzero" and "nonempty"
+// keep elements that are neither null, false, 0 nor empty.
+func matchesPredicate(elem interface{}, predicate string) bool {
+	if predicate == "nonzero" || predicate == "nonempty" {
+		return elem != nil && !isEmptyValue(reflect.ValueOf(elem))
+	}
+
+	var key, op, want string
+
+	for i := 0; i < len(predicate) && op == ""; i++ {
+		for _, candidate := range predicateOperators {
+			if len(predicate[i:]) >= len(candidate) && predicate[i:i+len(candidate)] == candidate {
+				key, op, want = predicate[:i], candidate, unquote(predicate[i+len(candidate):])
+				break
+			}
+		}
+	}
+
+	if op == "" {
+		panic(errorf(ErrInvalidTag, "mson: invalid filter condition %s", predicate))
+	}
+
+	if key != "" {
+		obj, ok := elem.(map[string]interface{})
+
+		if !ok {
+			return false
+		}
+
+		elem, ok = obj[key]
+
+		if !ok {
+			return false
+		}
+	}
+
+	var cmp int
+
+	if n, ok := numberOf(elem); ok {
+		w, err := strconv.ParseFloat(want, 64)
+
+		if err != nil {
+			return op == "!="
+		}
+
+		switch {
+		case n < w:
+			cmp = -1
+		case n > w:
+			cmp = 1
+		}
+	} else {
+		s := fmt.Sprint(elem)
+
+		switch {
+		case s < want:
+			cmp = -1
+		case s > want:
+			cmp = 1
+		}
+	}
+
+	switch op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	}
+
+	return cmp <= 0
+}
+
+func filterSlice(value interface{}, parts []string, inverted bool, fieldName string) (interface{}, error) {
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'filter' requires a condition"))
+	}
+
+	elems, err := elements(value, fieldName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make([]interface{}, 0, len(elems))
+
+	for _, e := range elems {
+		if matchesPredicate(e, unquote(parts[1])) != inverted {
+			kept = append(kept, e)
+		}
+	}
+
+	return kept, nil
+}
+
+func mapSlice(value interface{}, parts []string, target reflect.Type, fieldName string, state *decodeState) (interface{}, error) {
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'each' requires a chain of options"))
+	}
+
+	if target.Kind() != reflect.Slice {
+		return nil, errorf(ErrTypeMismatch, "mson: cannot apply each to field %s; field is of kind %s, not a slice", fieldName, target.Kind())
+	}
+
+	elems := reflect.ValueOf(value)
+
+	if elems.Kind() != reflect.Slice {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not an array", fieldName)
+	}
+
+	// A chain of several options must be quoted so that it stays in one piece
+	chain := strings.Join(parts[1:], ",")
+
+	if len(parts) == 2 {
+		chain = unquote(parts[1])
+	}
+
+	options := groupOptions(splitIgnoreQuoted(chain, ','), &state.settings)
+	out := reflect.MakeSlice(target, elems.Len(), elems.Len())
+
+	for i := 0; i < elems.Len(); i++ {
+		if err := processTag(out.Index(i), elems.Index(i).Interface(), options, fmt.Sprintf("%s[%d]", fieldName, i), state); err != nil {
+			return nil, err
+		}
+	}
+
+	return out.Interface(), nil
+}
+
+// projectMap returns the keys or the values of a decoded JSON object. Since
+// the parser does not keep key order, both are ordered by key.
+func projectMap(value interface{}, verb string, fieldName string) (interface{}, error) {
+	obj, ok := value.(map[string]interface{})
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not an object", fieldName)
+	}
+
+	keys := make([]string, 0, len(obj))
+
+	for k := range obj {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	projected := make([]interface{}, len(keys))
+
+	for i, k := range keys {
+		if verb == "keys" {
+			projected[i] = k
+		} else {
+			projected[i] = obj[k]
+		}
+	}
+
+	return projected, nil
+}
+
+func decodeElement(dst reflect.Value, elem interface{}, fieldName string, state *decodeState) error {
+	if obj, ok := elem.(map[string]interface{}); ok && dst.Kind() == reflect.Struct {
+		return decodeStruct(dst, obj, state)
+	}
+
+	return processTag(dst, elem, nil, fieldName, state)
+}
+
+// groupSlice turns an array of objects into a map keyed by the given property.
+// Maps of slices collect every element sharing a key; any other map requires
+// keys to be unique, as does the unique argument of groupby=<key>,unique
+// whatever the map.
+func groupSlice(value interface{}, parts []string, target reflect.Type, fieldName string, state *decodeState) (interface{}, error) {
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'groupby' requires a property name"))
+	}
+
+	if target.Kind() != reflect.Map || target.Key().Kind() != reflect.String {
+		return nil, errorf(ErrTypeMismatch, "mson: cannot group field %s; field is of type %s, not a map with string keys", fieldName, target)
+	}
+
+	elems, err := elements(value, fieldName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	property := unquote(parts[1])
+	unique := containsOption(parts[2:], "unique")
+	grouped := reflect.MakeMap(target)
+	elemType := target.Elem()
+	collect := elemType.Kind() == reflect.Slice
+
+	if collect {
+		elemType = elemType.Elem()
+	}
+
+	for i, e := range elems {
+		obj, ok := e.(map[string]interface{})
+
+		if !ok {
+			return nil, errorf(ErrTypeMismatch, "mson: element %d of field %s is not an object", i, fieldName)
+		}
+
+		k, ok := obj[property]
+
+		if !ok {
+			return nil, errorf(ErrFieldMissing, "mson: element %d of field %s has no property %s", i, fieldName, property)
+		}
+
+		key := reflect.ValueOf(fmt.Sprint(k)).Convert(target.Key())
+		dst := reflect.New(elemType).Elem()
+
+		if err := decodeElement(dst, e, fmt.Sprintf("%s[%d]", fieldName, i), state); err != nil {
+			return nil, err
+		}
+
+		existing := grouped.MapIndex(key)
+
+		switch {
+		case existing.IsValid() && (unique || !collect):
+			return nil, errorf(ErrDuplicate, "mson: field %s holds duplicate %s %v", fieldName, property, k)
+		case collect && existing.IsValid():
+			grouped.SetMapIndex(key, reflect.Append(existing, dst))
+		case collect:
+			grouped.SetMapIndex(key, reflect.Append(reflect.MakeSlice(target.Elem(), 0, 1), dst))
+		default:
+			grouped.SetMapIndex(key, dst)
+		}
+	}
+
+	return grouped.Interface(), nil
+}
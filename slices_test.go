@@ -0,0 +1,211 @@
+package mson_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+func TestSortUniqueReverseLimit(t *testing.T) {
+	type lists struct {
+		Sorted   []int    `json:"sorted,sort"`
+		Desc     []string `json:"desc,sort,desc"`
+		Unique   []string `json:"unique,unique"`
+		Reversed []int    `json:"reversed,reverse"`
+		Limited  []int    `json:"limited,limit,2"`
+	}
+
+	data := []byte(`{"sorted":[3,1,2],"desc":["a","c","b"],"unique":["x","y","x"],"reversed":[1,2,3],"limited":[1,2,3]}`)
+
+	var v lists
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	want := lists{
+		Sorted:   []int{1, 2, 3},
+		Desc:     []string{"c", "b", "a"},
+		Unique:   []string{"x", "y"},
+		Reversed: []int{3, 2, 1},
+		Limited:  []int{1, 2},
+	}
+
+	if !reflect.DeepEqual(v, want) {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+}
+
+func TestFilterAndEachOptions(t *testing.T) {
+	type lists struct {
+		Large  []int         `json:"large,filter=>1"`
+		Small  []int         `json:"small,filter!=>1"`
+		Items  []interface{} `json:"items,filter=\"n>=2\""`
+		Scaled []float64     `json:"scaled,each,\"multiply,10\""`
+		Shifts []float64     `json:"shifts,each,\"multiply,10,add,1\""`
+	}
+
+	data := []byte(`{"large":[3,1,2,0],"small":[3,1,2,0],"items":[{"n":1},{"n":2},{"n":3}],"scaled":[1,2],"shifts":[1,2]}`)
+
+	var v lists
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	want := lists{
+		Large:  []int{3, 2},
+		Small:  []int{1, 0},
+		Items:  []interface{}{map[string]interface{}{"n": 2.0}, map[string]interface{}{"n": 3.0}},
+		Scaled: []float64{10, 20},
+		Shifts: []float64{11, 21},
+	}
+
+	if !reflect.DeepEqual(v, want) {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"scaled":1}`), &v); err == nil {
+		t.Fatal("applied each to a value that is not an array")
+	}
+}
+
+func TestElementOptions(t *testing.T) {
+	type picked struct {
+		First   float64                `json:"first,first"`
+		Last    string                 `json:"last,last"`
+		Second  float64                `json:"second,index,1"`
+		FromEnd float64                `json:"from_end,index,-2"`
+		Missing float64                `json:"missing,index,5"`
+		Item    map[string]interface{} `json:"item,first"`
+	}
+
+	data := []byte(`{"first":[4,5],"last":["a","b"],"second":[1,2,3],"from_end":[1,2,3],"missing":[1],"item":[{"n":7},{"n":8}]}`)
+
+	v := picked{Missing: 9}
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.First != 4 || v.Last != "b" || v.Second != 2 || v.FromEnd != 2 || v.Missing != 0 || v.Item["n"] != 7.0 {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestKeysAndValuesOptions(t *testing.T) {
+	var v struct {
+		Keys   []string  `json:"keys,keys"`
+		Values []float64 `json:"values,values"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"keys":{"b":1,"a":2},"values":{"b":1,"a":2}}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both are ordered by key
+	if !reflect.DeepEqual(v.Keys, []string{"a", "b"}) || !reflect.DeepEqual(v.Values, []float64{2, 1}) {
+		t.Fatalf("got %+v", v)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"keys":[1,2]}`), &v); err == nil {
+		t.Fatal("projected the keys of an array")
+	}
+}
+
+type member struct {
+	ID   string `json:"id"`
+	Team string `json:"team"`
+}
+
+func TestGroupByOption(t *testing.T) {
+	var v struct {
+		ByID   map[string]member   `json:"by_id,groupby=id"`
+		ByTeam map[string][]member `json:"by_team,groupby=team"`
+	}
+
+	members := `[{"id":"a","team":"x"},{"id":"b","team":"y"},{"id":"c","team":"x"}]`
+
+	if err := mson.Unmarshal([]byte(`{"by_id":`+members+`,"by_team":`+members+`}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(v.ByID) != 3 || v.ByID["b"].Team != "y" || len(v.ByTeam["x"]) != 2 || v.ByTeam["x"][1].ID != "c" {
+		t.Fatalf("got %+v", v)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"by_id":[{"id":"a"},{"id":"a"}]}`), &v); err == nil {
+		t.Fatal("grouped duplicate keys into a map of single values")
+	}
+}
+
+func TestGroupByUnique(t *testing.T) {
+	var v struct {
+		ByTeam map[string][]member `json:"by_team,groupby=team,unique"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"by_team":[{"id":"a","team":"x"},{"id":"b","team":"y"}]}`), &v); err != nil || len(v.ByTeam["x"]) != 1 {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"by_team":[{"id":"a","team":"x"},{"id":"c","team":"x"}]}`), &v); !errors.Is(err, mson.ErrDuplicate) {
+		t.Fatalf("got %v, want ErrDuplicate", err)
+	}
+}
+
+func TestSliceOptionsChain(t *testing.T) {
+	type item struct {
+		N int `json:"n"`
+	}
+
+	type chained struct {
+		KeyCount   int             `json:"props,keys,count"`
+		Large      []int           `json:"scores,sort,filter=>1"`
+		Top        int             `json:"top,sort,desc,first"`
+		Distinct   int             `json:"tags,unique,count"`
+		Total      float64         `json:"totals,values,sum"`
+		Names      []string        `json:"names,sort,reverse,limit,2"`
+		LastKey    string          `json:"last,keys,last"`
+		Grouped    map[string]item `json:"grouped,filter=\"n>1\",groupby=id"`
+		Positive   int             `json:"positive,filter=>0,unique,count"`
+		SortedKeys []string        `json:"sorted,keys,sort,desc"`
+	}
+
+	data := []byte(`{
+		"props": {"a": 1, "b": 2, "c": 3},
+		"scores": [3, 1, 2, 0],
+		"top": [4, 9, 2],
+		"tags": ["x", "y", "x"],
+		"totals": {"a": 1.5, "b": 2},
+		"names": ["ann", "bob", "cy"],
+		"last": {"a": 1, "z": 2},
+		"grouped": [{"id": "p", "n": 1}, {"id": "q", "n": 2}],
+		"positive": [-1, 1, 2, 2],
+		"sorted": {"a": 1, "b": 2}
+	}`)
+
+	var v chained
+
+	if err := mson.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	want := chained{
+		KeyCount:   3,
+		Large:      []int{2, 3},
+		Top:        9,
+		Distinct:   2,
+		Total:      3.5,
+		Names:      []string{"cy", "bob"},
+		LastKey:    "z",
+		Grouped:    map[string]item{"q": {N: 2}},
+		Positive:   2,
+		SortedKeys: []string{"b", "a"},
+	}
+
+	if !reflect.DeepEqual(v, want) {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+}
@@ -0,0 +1,297 @@
+package mson
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// parseTag returns the key and the option tokens of a field, following the
+// grammar of encoding/json: a tag of "-" skips the field while "-," names it
+// "-", and an empty name, or one encoding/json would reject, stands for the
+// field's name. Names may also be quoted to hold commas. Options of a json
+// tag that are neither tag options nor aliases, such as those meant for
+// other encoders, are ignored, as encoding/json ignores options it does not
+// know.
+func parseTag(f reflect.StructField, s *settings) (name string, options []string, ok bool) {
+	tag, key := s.lookupTag(f)
+
+	if tag == "-" {
+		return "", nil, false
+	}
+
+	tokens := splitIgnoreQuoted(tag, ',')
+	name, named := tagName(tokens[0])
+
+	if !named {
+		name = f.Name
+	}
+
+	for _, token := range tokens[1:] {
+		// encoding/json accepts empty options, as in `json:"name,"`
+		if token == "" {
+			continue
+		}
+
+		if key == "json" && len(options) == 0 && !knownOption(token, s) {
+			continue
+		}
+
+		options = append(options, token)
+	}
+
+	return name, options, true
+}
+
+// tagName returns the key named by the first token of a tag, and false when
+// the token leaves the key to the field's name.
+func tagName(token string) (string, bool) {
+	if unquoted, err := strconv.Unquote(token); err == nil && strings.HasPrefix(token, `"`) {
+		return unquoted, true
+	}
+
+	return token, token != "_" && validTagName(token)
+}
+
+// validTagName reports whether encoding/json accepts name as a key.
+func validTagName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+
+	return true
+}
+
+// knownOption reports whether token starts a tag option or names an alias.
+func knownOption(token string, s *settings) bool {
+	name, _, _ := strings.Cut(token, "=")
+
+	if verbs[strings.TrimSuffix(name, "!")] {
+		return true
+	}
+
+	if _, ok := s.aliases[token]; ok {
+		return true
+	}
+
+	aliasesMu.RLock()
+	defer aliasesMu.RUnlock()
+
+	_, ok := aliases[token]
+	return ok
+}
+
+type candidate struct {
+	field  reflect.StructField
+	name   string
+	tagged bool
+}
+
+// dominant reports whether c wins over the other fields sharing its key.
+func dominant(c candidate, rivals []candidate) bool {
+	for _, r := range rivals {
+		switch {
+		case len(r.field.Index) == len(c.field.Index) && sameIndex(r.field.Index, c.field.Index):
+			// c itself
+		case len(r.field.Index) < len(c.field.Index):
+			return false
+		case len(r.field.Index) > len(c.field.Index):
+		case r.tagged == c.tagged, r.tagged:
+			return false
+		}
+	}
+
+	return true
+}
+
+func sameIndex(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type fieldsKey struct {
+	t    reflect.Type
+	keys string
+}
+
+// structFieldCache holds the result of structFields for each struct type and
+// list of tag keys.
+var structFieldCache sync.Map
+
+// structFields returns the fields decoded and encoded for a struct type, in
+// the order of their declaration: its exported fields, and the fields of the
+// structs it embeds without naming them in a tag, which are promoted as
+// encoding/json promotes them. Of several fields with the same key, the
+// least deeply embedded one wins, then the one with a tag; fields that tie
+// are all left out. The Index of each field is its path from t.
+func structFields(t reflect.Type, s *settings) []reflect.StructField {
+	key := fieldsKey{t, strings.Join(s.tagKeys, ",")}
+
+	if fields, ok := structFieldCache.Load(key); ok {
+		return fields.([]reflect.StructField)
+	}
+
+	var candidates []candidate
+	var walk func(t reflect.Type, index []int, visited map[reflect.Type]bool)
+
+	walk = func(t reflect.Type, index []int, visited map[reflect.Type]bool) {
+		visited[t] = true
+		defer delete(visited, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			f.Index = append(index[:len(index):len(index)], i)
+			ft := f.Type
+
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			if f.Anonymous {
+				// Unexported embedded types only matter for the exported
+				// fields of structs, which cannot be allocated behind a pointer
+				if !f.IsExported() && (ft.Kind() != reflect.Struct || f.Type.Kind() == reflect.Ptr) {
+					continue
+				}
+			} else if !f.IsExported() {
+				continue
+			}
+
+			name, _, ok := parseTag(f, s)
+
+			if !ok {
+				continue
+			}
+
+			tag, _ := s.lookupTag(f)
+			_, tagged := tagName(splitIgnoreQuoted(tag, ',')[0])
+
+			if f.Anonymous && !tagged && ft.Kind() == reflect.Struct {
+				if !visited[ft] {
+					walk(ft, f.Index, visited)
+				}
+
+				continue
+			}
+
+			if !f.IsExported() {
+				continue
+			}
+
+			candidates = append(candidates, candidate{f, name, tagged})
+		}
+	}
+
+	walk(t, nil, map[reflect.Type]bool{})
+
+	byName := map[string][]candidate{}
+
+	for _, c := range candidates {
+		byName[c.name] = append(byName[c.name], c)
+	}
+
+	var fields []reflect.StructField
+
+	for _, c := range candidates {
+		if dominant(c, byName[c.name]) {
+			fields = append(fields, c.field)
+		}
+	}
+
+	structFieldCache.Store(key, fields)
+
+	return fields
+}
+
+// fieldByIndex returns the field of rv at the path index, allocating the
+// embedded structs behind nil pointers on the way when alloc is set. It
+// reports false when it meets a nil pointer it may not allocate.
+func fieldByIndex(rv reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				if !alloc || !rv.CanSet() {
+					return reflect.Value{}, false
+				}
+
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+
+			rv = rv.Elem()
+		}
+
+		rv = rv.Field(x)
+	}
+
+	return rv, true
+}
+
+// A Field describes a field that mson decodes and encodes, as returned by
+// Fields.
+type Field struct {
+	// Key is the JSON key of the field.
+	Key string
+
+	// Index is the path of the field from the struct, for use with
+	// reflect.Value.FieldByIndex; fields promoted from embedded structs have
+	// more than one element.
+	Index []int
+
+	// Tag is the tag the key and options were read from.
+	Tag string
+
+	// Options holds the options applied to the field, each with its
+	// arguments, as in "multiply,100", including those registered for the
+	// field's type.
+	Options []string
+}
+
+// Fields returns the fields decoded and encoded for the struct type of v,
+// which must be a struct or a pointer to one, in the order of their
+// declaration. Unexported fields and fields tagged "-" are left out, and
+// the fields of embedded structs are promoted as encoding/json promotes
+// them, so that tools built on mson, such as test helpers, see the same
+// fields as Unmarshal.
+func Fields(v any, opts ...Option) ([]Field, error) {
+	s := &settings{}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	t := reflect.TypeOf(v)
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("mson: Fields requires a struct or a pointer to a struct")
+	}
+
+	var fields []Field
+
+	for _, f := range structFields(t, s) {
+		name, options, _ := parseTag(f, s)
+		tag, _ := s.lookupTag(f)
+		fields = append(fields, Field{Key: name, Index: f.Index, Tag: tag, Options: fieldOptions(f.Type, options, s)})
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,220 @@
+package mson
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// truncateString shortens a string to at most n runes, ending it with the
+// ellipsis argument when text was cut; the ellipsis counts toward n.
+func truncateString(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'maxlen' requires a length argument"))
+	}
+
+	n, err := strconv.Atoi(parts[1])
+
+	if err != nil || n < 0 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'maxlen' received invalid argument %s", parts[1]))
+	}
+
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	if utf8.RuneCountInString(s) <= n {
+		return s, nil
+	}
+
+	var ellipsis string
+
+	if len(parts) > 2 {
+		ellipsis = unquote(parts[2])
+	}
+
+	keep := n - utf8.RuneCountInString(ellipsis)
+
+	if keep < 0 {
+		keep, ellipsis = n, ""
+	}
+
+	return string([]rune(s)[:keep]) + ellipsis, nil
+}
+
+// padString extends a string to n runes with the given character, which is
+// appended unless the third argument is left.
+func padString(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	if len(parts) < 3 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'pad' requires a length and a character"))
+	}
+
+	n, err := strconv.Atoi(parts[1])
+
+	if err != nil || n < 0 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'pad' received invalid argument %s", parts[1]))
+	}
+
+	char := unquote(parts[2])
+
+	if utf8.RuneCountInString(char) != 1 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'pad' requires a single character, got %s", parts[2]))
+	}
+
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	missing := n - utf8.RuneCountInString(s)
+
+	if missing <= 0 {
+		return s, nil
+	}
+
+	if len(parts) > 3 && parts[3] == "left" {
+		return strings.Repeat(char, missing) + s, nil
+	}
+
+	return s + strings.Repeat(char, missing), nil
+}
+
+// transliterations covers the Latin letters that do not decompose into a
+// base letter and combining marks.
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'ø': "o", 'Ø': "o", 'œ': "oe", 'Œ': "oe",
+	'đ': "d", 'Đ': "d", 'ð': "d", 'Ð': "d", 'ł': "l", 'Ł': "l", 'þ': "th", 'Þ': "th",
+	'ı': "i", '&': "and",
+}
+
+// slugify turns a string into a lowercase, URL-safe slug whose words are
+// joined by the separator argument, a hyphen by default. Accented letters
+// lose their accents; other characters outside a-z and 0-9 separate words.
+func slugify(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	sep := "-"
+
+	if len(parts) > 1 {
+		sep = unquote(parts[1])
+	}
+
+	var (
+		b          strings.Builder
+		pending    bool
+		decomposed []rune
+	)
+
+	for _, r := range s {
+		decomposed = decomposeRune(decomposed, r, true)
+	}
+
+	for _, r := range decomposed {
+		word := string(r)
+
+		if t, ok := transliterations[r]; ok {
+			word = t
+		}
+
+		for _, c := range strings.ToLower(word) {
+			switch {
+			case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+				if pending && b.Len() > 0 {
+					b.WriteString(sep)
+				}
+
+				pending = false
+				b.WriteRune(c)
+			case c >= 0x0300 && c <= 0x036F:
+				// Combining marks left over from decomposition
+			default:
+				pending = true
+			}
+		}
+	}
+
+	return b.String(), nil
+}
+
+// identifierWords splits an identifier written in any naming convention into
+// its words, breaking on separators and on changes of case, so that
+// "parseHTTPResponse_code" yields parse, HTTP, Response and code.
+func identifierWords(s string) []string {
+	var (
+		words []string
+		word  []rune
+	)
+
+	runes := []rune(s)
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+
+			word = nil
+			continue
+		}
+
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := word[len(word)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			// A new word starts at an upper-case letter following a lower-case
+			// letter or digit, or at the last capital of an acronym
+			if !unicode.IsUpper(prev) || nextLower {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+
+		word = append(word, r)
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	return words
+}
+
+// convertCase rewrites an identifier in the naming convention of verb: camel,
+// pascal, snake or kebab.
+func convertCase(value interface{}, verb string, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	words := identifierWords(s)
+
+	for i, w := range words {
+		w = strings.ToLower(w)
+
+		if verb == "pascal" || (verb == "camel" && i > 0) {
+			r, size := utf8.DecodeRuneInString(w)
+			w = string(unicode.ToUpper(r)) + w[size:]
+		}
+
+		words[i] = w
+	}
+
+	switch verb {
+	case "snake":
+		return strings.Join(words, "_"), nil
+	case "kebab":
+		return strings.Join(words, "-"), nil
+	}
+
+	return strings.Join(words, ""), nil
+}
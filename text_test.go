@@ -0,0 +1,224 @@
+package mson_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+func TestSanitizeOption(t *testing.T) {
+	type text struct {
+		Clean string `json:"clean,sanitize"`
+	}
+
+	var v text
+
+	if err := mson.Unmarshal([]byte(`{"clean":"\ufeff  line\u0000 one\n\ttwo\u0007 "}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (text{Clean: "line one\n\ttwo"}); v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+}
+
+func TestNormOption(t *testing.T) {
+	type text struct {
+		Composed string `json:"composed,norm"`
+		Split    string `json:"split,norm,nfd"`
+	}
+
+	var v text
+
+	if err := mson.Unmarshal([]byte(`{"composed":"e\u0301","split":"\u00e9"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (text{Composed: "\u00e9", Split: "e\u0301"}); v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+}
+
+func TestHTMLUnescapeOption(t *testing.T) {
+	type text struct {
+		Unescape string `json:"unescape,htmlunescape"`
+	}
+
+	var v text
+
+	if err := mson.Unmarshal([]byte(`{"unescape":"Tom &amp; Jerry &lt;3 &#x263A;"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (text{Unescape: "Tom & Jerry <3 ☺"}); v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+}
+
+func TestMaxlenAndPadOptions(t *testing.T) {
+	type text struct {
+		Short string `json:"short,maxlen,5,\"…\""`
+		Exact string `json:"exact,maxlen,5"`
+		Right string `json:"right,pad,5,\".\""`
+		Left  string `json:"left,pad,4,0,left"`
+	}
+
+	var v text
+
+	if err := mson.Unmarshal([]byte(`{"short":"truncated text","exact":"12345","right":"ab","left":"42"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (text{Short: "trun…", Exact: "12345", Right: "ab...", Left: "0042"}); v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+}
+
+func TestSlugOption(t *testing.T) {
+	type text struct {
+		Slug  string `json:"slug,slug"`
+		Under string `json:"under,slug,_"`
+	}
+
+	var v text
+
+	if err := mson.Unmarshal([]byte(`{"slug":"Crème Brûlée: the Recipe!","under":"Hello World"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (text{Slug: "creme-brulee-the-recipe", Under: "hello_world"}); v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+}
+
+func TestCaseOptions(t *testing.T) {
+	type text struct {
+		Camel  string `json:"camel,camel"`
+		Pascal string `json:"pascal,pascal"`
+		Snake  string `json:"snake,snake"`
+		Kebab  string `json:"kebab,kebab"`
+	}
+
+	var v text
+
+	if err := mson.Unmarshal([]byte(`{"camel":"user_id","pascal":"http-server","snake":"parseHTTPResponse","kebab":"UserID"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := (text{Camel: "userId", Pascal: "HttpServer", Snake: "parse_http_response", Kebab: "user-id"}); v != want {
+		t.Fatalf("got %+v, want %+v", v, want)
+	}
+}
+
+func TestHTMLEscapeOnMarshal(t *testing.T) {
+	type comment struct {
+		Body string `json:"body,htmlunescape,htmlescape"`
+	}
+
+	var v comment
+
+	if err := mson.Unmarshal([]byte(`{"body":"a &lt;b&gt;"}`), &v); err != nil || v.Body != "a <b>" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+
+	out, err := mson.Marshal(v)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"body":"a \u0026lt;b\u0026gt;"}`; string(out) != want {
+		t.Errorf("got %s, want %s", out, want)
+	}
+}
+
+func TestWithSanitize(t *testing.T) {
+	var v struct {
+		Name string                 `json:"name"`
+		Tags []interface{}          `json:"tags"`
+		Meta map[string]interface{} `json:"meta"`
+	}
+
+	data := []byte(`{"name":" \u0000Ada ","tags":[" a\u0001 "],"meta":{"k":"\ufeffv"}}`)
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithSanitize()); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Name != "Ada" || v.Tags[0] != "a" || v.Meta["k"] != "v" {
+		t.Fatalf("got %+v", v)
+	}
+}
+
+func TestPathOption(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MSON_TEST_DIR", "data")
+
+	var v struct {
+		Path   string `json:"path,path"`
+		Exists string `json:"exists,path,exists"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"path":"~/$MSON_TEST_DIR/../${MSON_TEST_DIR}//file","exists":"~"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := filepath.Join(home, "data", "file"); v.Path != want || v.Exists != home {
+		t.Fatalf("got %+v, want %s and %s", v, want, home)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"exists":"~/missing"}`), &v); err == nil {
+		t.Fatal("accepted a missing path")
+	}
+}
+
+func TestWithInterpolation(t *testing.T) {
+	var v struct {
+		URL   string        `json:"url"`
+		Hosts []interface{} `json:"hosts"`
+	}
+
+	resolve := func(ref string) (string, error) {
+		if ref == "host" {
+			return "example.com", nil
+		}
+
+		return "", errors.New("unknown reference " + ref)
+	}
+
+	data := []byte(`{"url":"https://${host}/a?q=$${x}","hosts":["${host}"]}`)
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithInterpolation(resolve)); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.URL != "https://example.com/a?q=${x}" || v.Hosts[0] != "example.com" {
+		t.Fatalf("got %+v", v)
+	}
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"url":"${port}"}`), &v, mson.WithInterpolation(resolve)); err == nil {
+		t.Fatal("resolved an unknown reference")
+	}
+}
+
+type upperNormalizer struct{}
+
+func (upperNormalizer) NormalizeString(form, s string) (string, error) {
+	return form + ":" + s, nil
+}
+
+func TestRegisterUnicodeNormalizer(t *testing.T) {
+	mson.RegisterUnicodeNormalizer(upperNormalizer{})
+	defer mson.RegisterUnicodeNormalizer(nil)
+
+	var v struct {
+		S string `json:"s,norm,nfkc"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"s":"x"}`), &v); err != nil || v.S != "nfkc:x" {
+		t.Fatalf("got %+v, %v", v, err)
+	}
+}
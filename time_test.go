@@ -0,0 +1,261 @@
+package mson_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+	"github.com/monerowner/mson/msontest"
+)
+
+var testNow = time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC)
+
+func testClock() time.Time {
+	return testNow
+}
+
+type timedEvent struct {
+	At       time.Time     `json:"at,unix"`
+	AtMilli  time.Time     `json:"at_ms,unix,milliseconds"`
+	Timeout  time.Duration `json:"timeout,duration"`
+	Backoff  time.Duration `json:"backoff,duration,milliseconds"`
+	Deadline time.Time     `json:"deadline,duration!,minutes"`
+}
+
+func TestUnixAndDurationOptions(t *testing.T) {
+	var v timedEvent
+
+	data := []byte(`{"at":1700000000,"at_ms":1700000000123,"timeout":30,"backoff":1500,"deadline":90}`)
+
+	if err := mson.UnmarshalWithOptions(data, &v, mson.WithClock(testClock)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !v.At.Equal(time.Unix(1700000000, 0)) || !v.AtMilli.Equal(time.UnixMilli(1700000000123)) {
+		t.Errorf("got times %v and %v", v.At, v.AtMilli)
+	}
+
+	if v.Timeout != 30*time.Second || v.Backoff != 1500*time.Millisecond {
+		t.Errorf("got durations %v and %v", v.Timeout, v.Backoff)
+	}
+
+	if !v.Deadline.Equal(testNow.Add(90 * time.Minute)) {
+		t.Errorf("got deadline %v", v.Deadline)
+	}
+
+	out, err := mson.MarshalWithOptions(v, mson.WithClock(testClock))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(out) != string(data) {
+		t.Errorf("got %s, want %s", out, data)
+	}
+}
+
+func TestRoundTripTimeOptions(t *testing.T) {
+	msontest.RoundTrip(t, timedEvent{
+		At:      time.Unix(1700000000, 0),
+		AtMilli: time.UnixMilli(1700000000123),
+		Timeout: 90 * time.Second,
+		Backoff: 250 * time.Millisecond,
+	})
+}
+
+func TestRelativeTime(t *testing.T) {
+	var v struct {
+		When time.Time `json:"when,relativetime"`
+	}
+
+	before := time.Now()
+
+	if err := mson.Unmarshal([]byte(`{"when":"in three hours"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := v.When.Sub(before); d < 3*time.Hour || d > 3*time.Hour+time.Minute {
+		t.Errorf("got %v, want three hours from now", v.When)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"when":"2024-01-02T03:04:05Z"}`), &v); err != nil || !v.When.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("got %v, %v", v.When, err)
+	}
+
+	if err := mson.Unmarshal([]byte(`{"when":"some day"}`), &v); err == nil {
+		t.Error("decoded an unrecognized relative time")
+	}
+}
+
+func TestRelativeTimeWithClock(t *testing.T) {
+	var v struct {
+		When time.Time `json:"when,relativetime"`
+	}
+
+	for input, want := range map[string]time.Time{
+		"now":             testNow,
+		"yesterday":       time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC),
+		"2 days ago":      testNow.AddDate(0, 0, -2),
+		"in three hours":  testNow.Add(3 * time.Hour),
+		"a week from now": testNow.AddDate(0, 0, 7),
+		"last month":      testNow.AddDate(0, -1, 0),
+	} {
+		if err := mson.UnmarshalWithOptions([]byte(`{"when":"`+input+`"}`), &v, mson.WithClock(testClock)); err != nil {
+			t.Errorf("decoding %q: %v", input, err)
+		} else if !v.When.Equal(want) {
+			t.Errorf("decoding %q gave %v, want %v", input, v.When, want)
+		}
+	}
+}
+
+func TestIntervalOption(t *testing.T) {
+	var v struct {
+		Window mson.Interval `json:"window,interval"`
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for input, want := range map[string]mson.Interval{
+		"2024-01-01T00:00:00Z/2024-01-03T00:00:00Z": {Start: start, End: start.AddDate(0, 0, 2)},
+		"2024-01-01T00:00:00Z/P1DT12H":              {Start: start, End: start.Add(36 * time.Hour)},
+		"P1M/2024-01-01T00:00:00Z":                  {Start: start.AddDate(0, -1, 0), End: start},
+	} {
+		if err := mson.Unmarshal([]byte(`{"window":"`+input+`"}`), &v); err != nil {
+			t.Errorf("decoding %q: %v", input, err)
+		} else if !v.Window.Start.Equal(want.Start) || !v.Window.End.Equal(want.End) {
+			t.Errorf("decoding %q gave %v, want %v", input, v.Window, want)
+		}
+	}
+
+	for _, input := range []string{"P1D/P2D", "2024-01-03T00:00:00Z/2024-01-01T00:00:00Z", "2024-01-01"} {
+		if err := mson.Unmarshal([]byte(`{"window":"`+input+`"}`), &v); err == nil {
+			t.Errorf("decoded invalid interval %q", input)
+		}
+	}
+}
+
+func TestTimeTruncAndRound(t *testing.T) {
+	var v struct {
+		Day     time.Time `json:"day,unix,timetrunc,day"`
+		Hour    time.Time `json:"hour,unix,timeround,hour"`
+		Quarter time.Time `json:"quarter,unix,timetrunc,15m"`
+	}
+
+	// 2023-11-14T22:13:20Z
+	if err := mson.Unmarshal([]byte(`{"day":1700000000,"hour":1700000000,"quarter":1700000000}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC); !v.Day.Equal(want) {
+		t.Errorf("got day %v, want %v", v.Day, want)
+	}
+
+	if want := time.Date(2023, 11, 14, 22, 0, 0, 0, time.UTC); !v.Hour.Equal(want) {
+		t.Errorf("got hour %v, want %v", v.Hour, want)
+	}
+
+	if want := time.Date(2023, 11, 14, 22, 0, 0, 0, time.UTC); !v.Quarter.Equal(want) {
+		t.Errorf("got quarter %v, want %v", v.Quarter, want)
+	}
+}
+
+func TestDurationFormats(t *testing.T) {
+	type formatted struct {
+		Human  time.Duration `json:"human,durfmt"`
+		Go     time.Duration `json:"go,durfmt,gostring"`
+		Millis time.Duration `json:"millis,durfmt,ms"`
+		Secs   time.Duration `json:"secs,durfmt,seconds"`
+	}
+
+	d := 90 * time.Minute
+	out, err := mson.Marshal(formatted{Human: d, Go: d, Millis: 1500 * time.Microsecond, Secs: 2 * time.Second})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `{"human":"1h30m","go":"1h30m0s","millis":1.5,"secs":2}`; string(out) != want {
+		t.Fatalf("got %s, want %s", out, want)
+	}
+}
+
+func TestRateOption(t *testing.T) {
+	var v struct {
+		Limit     mson.Rate `json:"limit,rate"`
+		PerSecond float64   `json:"per_second,rate"`
+	}
+
+	if err := mson.Unmarshal([]byte(`{"limit":"100/5m","per_second":"3k per hour"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Limit.Count != 100 || v.Limit.Interval != 5*time.Minute {
+		t.Errorf("got %v", v.Limit)
+	}
+
+	if v.PerSecond != 3000.0/3600 {
+		t.Errorf("got %v events per second", v.PerSecond)
+	}
+}
+
+func TestCronOption(t *testing.T) {
+	var v struct {
+		Next time.Time `json:"next,cron"`
+		Expr string    `json:"expr,cron"`
+	}
+
+	before := time.Now()
+
+	if err := mson.Unmarshal([]byte(`{"next":"0 9 * * mon-fri","expr":" @daily "}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if day := v.Next.Weekday(); v.Next.Before(before) || v.Next.Hour() != 9 || v.Next.Minute() != 0 || day == time.Saturday || day == time.Sunday {
+		t.Errorf("got %v, want the next weekday at 9:00", v.Next)
+	}
+
+	if v.Expr != "@daily" {
+		t.Errorf("got %q", v.Expr)
+	}
+
+	for _, expr := range []string{"0 9 * *", "61 * * * *", "0 0 30 2 *"} {
+		if err := mson.Unmarshal([]byte(`{"next":"`+expr+`"}`), &v); err == nil || !strings.Contains(err.Error(), "next") {
+			t.Errorf("decoding %q returned %v", expr, err)
+		}
+	}
+}
+
+func TestCronOptionWithClock(t *testing.T) {
+	var v struct {
+		Next time.Time `json:"next,cron"`
+	}
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"next":"0 9 * * mon-fri"}`), &v, mson.WithClock(testClock)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 2024-06-15 is a Saturday
+	if want := time.Date(2024, 6, 17, 9, 0, 0, 0, time.UTC); !v.Next.Equal(want) {
+		t.Errorf("got %v, want %v", v.Next, want)
+	}
+}
+
+// Options relative to the current time decode the same document to the same
+// value, since Fuzz fixes the clock.
+type scheduledEvent struct {
+	At     time.Time     `json:"at,unix"`
+	Day    time.Time     `json:"day,unix,milliseconds,timetrunc,day"`
+	Window mson.Interval `json:"window,interval"`
+	Limit  mson.Rate     `json:"limit,rate"`
+	Next   time.Time     `json:"next,cron"`
+	When   time.Time     `json:"when,relativetime"`
+}
+
+func FuzzTimeOptions(f *testing.F) {
+	msontest.Fuzz(f, scheduledEvent{})
+}
+
+func FuzzTimeOptionsWithClock(f *testing.F) {
+	msontest.Fuzz(f, scheduledEvent{}, mson.WithClock(testClock), mson.WithStrictEnums())
+}
@@ -0,0 +1,152 @@
+package mson
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ToMap returns the struct pointed to or held by v as a map, applying the
+// options found in each field's tag in reverse like Marshal, for callers that
+// hand the result to a template engine or a document database rather than
+// write it out. Each field holds what Marshal would write for it, so that a
+// time tagged unix becomes a number of seconds. Nested structs become maps
+// and slices and arrays become []any, while other values keep their Go
+// types: numbers are not turned into float64 and values such as times are
+// not turned into strings.
+func ToMap(v any) (map[string]any, error) {
+	return ToMapWithOptions(v)
+}
+
+// ToMapWithOptions is like ToMap, but applies the given options to the
+// encode.
+func ToMapWithOptions(v any, opts ...Option) (map[string]any, error) {
+	state := &encodeState{}
+
+	for _, opt := range opts {
+		opt(&state.settings)
+	}
+
+	return toMap(v, state)
+}
+
+// ToMap is like the package-level ToMap, using the codec's options.
+func (c *Codec) ToMap(v any) (map[string]any, error) {
+	return toMap(v, &encodeState{settings: c.settings})
+}
+
+func toMap(v any, state *encodeState) (map[string]any, error) {
+	rv := derefValue(reflect.ValueOf(v))
+
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("mson: ToMap requires a struct or a pointer to a struct")
+	}
+
+	return mapStruct(rv, state)
+}
+
+func mapStruct(rv reflect.Value, state *encodeState) (map[string]interface{}, error) {
+	parent := state.parent
+	state.parent = rv
+	defer func() { state.parent = parent }()
+
+	obj := map[string]interface{}{}
+
+	for _, f := range structFields(rv.Type(), &state.settings) {
+		field, ok := fieldByIndex(rv, f.Index, false)
+
+		// The fields of a nil embedded struct are left out
+		if !ok {
+			continue
+		}
+
+		name, value, ok, err := fieldValue(field, f, state)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			continue
+		}
+
+		if obj[name], err = mapValue(reflect.ValueOf(value), state); err != nil {
+			return nil, err
+		}
+	}
+
+	return obj, nil
+}
+
+// mapValue is the counterpart of encodeValue for ToMap.
+func mapValue(rv reflect.Value, state *encodeState) (interface{}, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	// Values that encode themselves are kept whole
+	if isBigType(rv.Type()) || rv.Type() == orderedMapType || rv.Type().Implements(jsonMarshalerType) || rv.Type().Implements(textMarshalerType) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, nil
+		}
+
+		return rv.Interface(), nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+
+		return mapValue(rv.Elem(), state)
+	case reflect.Struct:
+		return mapStruct(rv, state)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil, nil
+		}
+
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Interface(), nil
+		}
+
+		elems := make([]interface{}, rv.Len())
+
+		for i := range elems {
+			elem, err := mapValue(rv.Index(i), state)
+
+			if err != nil {
+				return nil, err
+			}
+
+			elems[i] = elem
+		}
+
+		return elems, nil
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+
+		if rv.Type().Key().Kind() != reflect.String {
+			return rv.Interface(), nil
+		}
+
+		obj := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+
+		for iter.Next() {
+			elem, err := mapValue(iter.Value(), state)
+
+			if err != nil {
+				return nil, err
+			}
+
+			obj[iter.Key().String()] = elem
+		}
+
+		return obj, nil
+	}
+
+	return rv.Interface(), nil
+}
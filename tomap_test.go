@@ -0,0 +1,83 @@
+package mson_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+type mappedLine struct {
+	Price float64 `json:"price,divide,100"`
+}
+
+type mappedOrder struct {
+	Placed  time.Time     `json:"placed,unix"`
+	Timeout time.Duration `json:"timeout,duration,milliseconds"`
+	Created time.Time     `json:"created"`
+	ID      int64         `json:"id,string"`
+	Lines   []mappedLine  `json:"lines"`
+	Note    string        `json:"note,omitempty"`
+}
+
+func TestToMapMatchesMarshal(t *testing.T) {
+	created := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	in := mappedOrder{
+		Placed:  time.Unix(1700000000, 0),
+		Timeout: 1500 * time.Millisecond,
+		Created: created,
+		ID:      42,
+		Lines:   []mappedLine{{Price: 12.5}},
+	}
+
+	m, err := mson.ToMap(&in)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"placed":  int64(1700000000),
+		"timeout": int64(1500),
+		"created": created,
+		"id":      "42",
+		"lines":   []any{map[string]any{"price": 1250.0}},
+	}
+
+	if len(m) != len(want) {
+		t.Errorf("got keys %v, want %d of them", reflect.ValueOf(m).MapKeys(), len(want))
+	}
+
+	for k, v := range want {
+		if got := m[k]; !reflect.DeepEqual(got, v) {
+			t.Errorf("got %s = %#v (%T), want %#v (%T)", k, got, got, v, v)
+		}
+	}
+
+	// Decoding the map gives back the struct, as decoding the output of
+	// Marshal does
+	data, err := json.Marshal(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out mappedOrder
+
+	if err := mson.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !out.Placed.Equal(in.Placed) || out.Timeout != in.Timeout || out.ID != in.ID || out.Lines[0].Price != 12.5 {
+		t.Fatalf("got %+v, want %+v", out, in)
+	}
+}
+
+func TestToMapRequiresStruct(t *testing.T) {
+	if _, err := mson.ToMap(map[string]int{}); err == nil {
+		t.Fatal("ToMap accepted a map")
+	}
+}
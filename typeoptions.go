@@ -0,0 +1,61 @@
+package mson
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	typeOptionsMu sync.RWMutex
+	typeOptions   = map[reflect.Type][]string{}
+)
+
+// RegisterTypeOptions makes options the default chain of tag options for
+// fields of the type of v, or pointers to it, as in
+// RegisterTypeOptions(Timeout(0), "duration,milliseconds"). A field whose tag
+// carries options of its own uses those instead, unless they are only
+// omitempty, omitzero or omitif, which are added to the defaults. Passing
+// empty options removes the type's defaults.
+func RegisterTypeOptions(v any, options string) {
+	t := reflect.TypeOf(v)
+
+	typeOptionsMu.Lock()
+	defer typeOptionsMu.Unlock()
+	defer resetFastPlans()
+
+	if options == "" {
+		delete(typeOptions, t)
+		return
+	}
+
+	typeOptions[t] = splitIgnoreQuoted(options, ',')
+}
+
+// fieldOptions returns the grouped options of a field: those of its tag, or
+// the defaults registered for its type when the tag only controls omission.
+func fieldOptions(fieldType reflect.Type, tagOptions []string, s *settings) []string {
+	options := groupOptions(tagOptions, s)
+
+	for _, opt := range options {
+		switch splitIgnoreQuoted(opt, ',')[0] {
+		case "omitempty", "omitzero", "omitif":
+		default:
+			return options
+		}
+	}
+
+	t := derefType(fieldType)
+	defaults, ok := s.typeOptions[t]
+
+	if !ok {
+		typeOptionsMu.RLock()
+		defaults, ok = typeOptions[t]
+		typeOptionsMu.RUnlock()
+	}
+
+	if !ok {
+		return options
+	}
+
+	return append(groupOptions(defaults, s), options...)
+}
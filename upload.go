@@ -0,0 +1,63 @@
+package mson
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// validateMIME checks a media type against the patterns of the mime option,
+// such as image/* or application/pdf, separated by |. Parameters like
+// charset are ignored.
+func validateMIME(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'mime' requires a pattern argument"))
+	}
+
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(s)
+
+	if err != nil {
+		return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must be a media type"}
+	}
+
+	patterns := unquote(parts[1])
+
+	for _, pattern := range strings.Split(patterns, "|") {
+		if matchGlob(strings.ToLower(strings.TrimSpace(pattern)), mediaType) {
+			return s, nil
+		}
+	}
+
+	return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must have a media type matching " + patterns}
+}
+
+// validateExtension checks the extension of a file name against the list of
+// the ext option, such as jpg|png, ignoring case and leading dots.
+func validateExtension(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option 'ext' requires a list of extensions"))
+	}
+
+	s, ok := value.(string)
+
+	if !ok {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a string", fieldName)
+	}
+
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(s)), ".")
+	allowed := unquote(parts[1])
+
+	for _, candidate := range strings.Split(allowed, "|") {
+		if ext != "" && ext == strings.TrimPrefix(strings.ToLower(strings.TrimSpace(candidate)), ".") {
+			return s, nil
+		}
+	}
+
+	return nil, &ValidationError{Field: fieldName, Value: s, Reason: "must have one of the extensions " + allowed}
+}
@@ -1,6 +1,8 @@
 package mson
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"reflect"
@@ -18,6 +20,11 @@ func splitIgnoreQuoted(s string, sep byte) []string {
 		switch s[i] {
 		case '"':
 			inQuotes = !inQuotes
+		case '\\':
+			// An escaped quote does not end a quoted argument
+			if inQuotes && i+1 < len(s) {
+				i++
+			}
 		case sep:
 			if !inQuotes {
 				parts = append(parts, strings.TrimSpace(s[start:end]))
@@ -32,6 +39,128 @@ func splitIgnoreQuoted(s string, sep byte) []string {
 	return parts
 }
 
+func unquote(s string) string {
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		return unquoted
+	}
+
+	return s
+}
+
+// verbArguments holds the arguments of options that are spelled like an
+// option themselves, such as the unique of groupby=<key>,unique and the
+// equals condition of omitif.
+var verbArguments = map[string][]string{"groupby": {"unique"}, "omitif": {"equals"}}
+
+func groupOptions(tokens []string, s *settings) []string {
+	var options []string
+
+	for _, token := range expandAliases(tokens, s, 0) {
+		name, arg, hasArg := strings.Cut(token, "=")
+
+		if len(options) > 0 && (containsOption(verbArguments[splitIgnoreQuoted(options[len(options)-1], ',')[0]], token) || isOmitIfCondition(options[len(options)-1], token)) {
+			options[len(options)-1] += "," + token
+		} else if verbs[strings.TrimSuffix(name, "!")] {
+			if hasArg {
+				token = name + "," + arg
+			}
+
+			options = append(options, token)
+		} else if len(options) > 0 {
+			options[len(options)-1] += "," + token
+		} else {
+			options = append(options, token)
+		}
+	}
+
+	return options
+}
+
+// isOmitIfCondition reports whether token continues the conditions of
+// option, an omitif option. Conditions name sibling fields, which may be
+// spelled like a verb, as in omitif,count=0, and the value compared by equals
+// may be too.
+func isOmitIfCondition(option, token string) bool {
+	parts := splitIgnoreQuoted(option, ',')
+
+	if parts[0] != "omitif" {
+		return false
+	}
+
+	return parts[len(parts)-1] == "equals" || strings.Contains(token, "=")
+}
+
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+
+		if i < len(as) {
+			x = as[i]
+		}
+
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		xn, xerr := strconv.ParseUint(x, 10, 64)
+		yn, yerr := strconv.ParseUint(y, 10, 64)
+
+		if xerr == nil && yerr == nil {
+			if xn < yn {
+				return -1
+			} else if xn > yn {
+				return 1
+			}
+		} else if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// admitsVersion reports whether version lies in the range of a since or until
+// option, whose arguments are parts: since admits its version and those after
+// it, until those before it.
+func admitsVersion(parts []string, version string) bool {
+	verb := parts[0]
+
+	if len(parts) < 2 {
+		panic(errorf(ErrInvalidTag, "mson: tag option '%s' requires a version argument", verb))
+	}
+
+	if verb == "since" {
+		return compareVersions(version, parts[1]) >= 0
+	}
+
+	return compareVersions(version, parts[1]) < 0
+}
+
+// requiredByVersion reports whether a field with options is required when
+// decoding version: a field carrying since or until options belongs to the
+// versions they admit, so its key must be present in those versions.
+func requiredByVersion(options []string, version string) bool {
+	gated := false
+
+	for _, opt := range options {
+		parts := splitIgnoreQuoted(opt, ',')
+
+		switch parts[0] {
+		case "since", "until":
+			if !admitsVersion(parts, version) {
+				return false
+			}
+
+			gated = true
+		}
+	}
+
+	return gated
+}
+
 func parseDuration(value, unit string) (time.Duration, error) {
 	seconds, err := strconv.ParseFloat(value, 64)
 	if err != nil {
@@ -56,6 +185,42 @@ func parseDuration(value, unit string) (time.Duration, error) {
 	}
 }
 
+func formatDuration(d time.Duration, format string) (interface{}, error) {
+	switch format {
+	case "human":
+		s := d.String()
+
+		// Drop the zero units time.Duration.String always spells out
+		if strings.HasSuffix(s, "m0s") {
+			s = s[:len(s)-2]
+		}
+
+		if strings.HasSuffix(s, "h0m") {
+			s = s[:len(s)-2]
+		}
+
+		return s, nil
+	case "gostring":
+		return d.String(), nil
+	case "seconds":
+		if d%time.Second == 0 {
+			return int64(d / time.Second), nil
+		}
+
+		return d.Seconds(), nil
+	case "ms", "milliseconds":
+		if d%time.Millisecond == 0 {
+			return d.Milliseconds(), nil
+		}
+
+		return float64(d) / float64(time.Millisecond), nil
+	case "ns", "nanoseconds":
+		return int64(d), nil
+	}
+
+	return nil, fmt.Errorf("unknown duration format %s", format)
+}
+
 func parseTime(value, unit string) (time.Time, error) {
 	unixTime, err := strconv.ParseFloat(value, 64)
 
@@ -81,8 +246,147 @@ func parseTime(value, unit string) (time.Time, error) {
 	}
 }
 
+// unitLength returns the length of a unit accepted by the unix and duration
+// options, seconds being the default.
+func unitLength(unit string) time.Duration {
+	switch unit {
+	case "nanoseconds":
+		return time.Nanosecond
+	case "microseconds":
+		return time.Microsecond
+	case "milliseconds":
+		return time.Millisecond
+	case "minutes":
+		return time.Minute
+	case "hours":
+		return time.Hour
+	}
+
+	return time.Second
+}
+
+// countUnits is the inverse of parseDuration, returning d as a number of
+// units, an integer when d holds a whole number of them.
+func countUnits(d time.Duration, unit string) interface{} {
+	length := unitLength(unit)
+
+	if d%length == 0 {
+		return int64(d / length)
+	}
+
+	return float64(d) / float64(length)
+}
+
+// formatTime is the inverse of parseTime, returning t as a number of units
+// since the Unix epoch. Seconds are counted apart from nanoseconds so that
+// times outside the range of UnixNano keep their value.
+func formatTime(t time.Time, unit string) interface{} {
+	length := unitLength(unit)
+
+	if length < time.Second {
+		return t.Unix()*int64(time.Second/length) + int64(t.Nanosecond())/int64(length)
+	}
+
+	seconds, per := t.Unix(), int64(length/time.Second)
+
+	if seconds%per == 0 && t.Nanosecond() == 0 {
+		return seconds / per
+	}
+
+	return (float64(seconds) + float64(t.Nanosecond())/float64(time.Second)) / float64(per)
+}
+
+// formatUnits undoes the unix and duration options for Marshal, writing the
+// time or duration of a field as the number of units the option reads.
+// Times decoded by duration! are written as the duration from now on.
+func formatUnits(value interface{}, parts []string, inverted bool, fieldName string, now time.Time) (interface{}, error) {
+	rv := derefValue(reflect.ValueOf(value))
+
+	// Null stays null
+	if !rv.IsValid() || rv.Kind() == reflect.Ptr {
+		return value, nil
+	}
+
+	unit := "seconds"
+
+	if len(parts) > 1 {
+		unit = parts[1]
+	}
+
+	t, isTime := rv.Interface().(time.Time)
+
+	switch {
+	case isTime && parts[0] == "unix":
+		return formatTime(t, unit), nil
+	case isTime && inverted:
+		return countUnits(t.Sub(now), unit), nil
+	case !isTime && parts[0] == "duration" && rv.CanInt():
+		return countUnits(time.Duration(rv.Int()), unit), nil
+	case !isTime && parts[0] == "duration" && rv.CanFloat():
+		return countUnits(time.Duration(rv.Float()), unit), nil
+	}
+
+	if parts[0] == "duration" && !inverted {
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a duration", fieldName)
+	}
+
+	return nil, errorf(ErrTypeMismatch, "mson: field %s is not a time.Time", fieldName)
+}
+
+func truncateTime(t time.Time, unit string, round bool) (time.Time, error) {
+	var floor, ceil time.Time
+
+	switch unit {
+	case "day":
+		floor = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		ceil = floor.AddDate(0, 0, 1)
+	case "week":
+		floor = time.Date(t.Year(), t.Month(), t.Day()-int(t.Weekday()-time.Monday+7)%7, 0, 0, 0, 0, t.Location())
+		ceil = floor.AddDate(0, 0, 7)
+	case "month":
+		floor = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+		ceil = floor.AddDate(0, 1, 0)
+	case "year":
+		floor = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+		ceil = floor.AddDate(1, 0, 0)
+	default:
+		d, err := time.ParseDuration(unit)
+
+		if err != nil {
+			switch unit {
+			case "hour":
+				d = time.Hour
+			case "minute":
+				d = time.Minute
+			case "second":
+				d = time.Second
+			default:
+				return time.Time{}, fmt.Errorf("invalid time boundary %s", unit)
+			}
+		}
+
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("invalid time boundary %s", unit)
+		}
+
+		if round {
+			return t.Round(d), nil
+		}
+
+		return t.Truncate(d), nil
+	}
+
+	if round && t.Sub(floor) >= ceil.Sub(t) {
+		return ceil, nil
+	}
+
+	return floor, nil
+}
+
 func compareInterfaceValue(value interface{}, arg string) bool {
 	switch v := value.(type) {
+	case string:
+		return v == arg
 	case bool:
 		return (arg == "true" && v) || (arg == "false" && !v)
 	case int:
@@ -96,6 +400,193 @@ func compareInterfaceValue(value interface{}, arg string) bool {
 	return false
 }
 
+// containsValue reports whether an array holds an element equal to arg, a
+// string holds arg as a substring, or an object holds arg as a key.
+func containsValue(value interface{}, arg string) bool {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, e := range v {
+			if s, ok := e.(string); (ok && s == arg) || compareInterfaceValue(e, arg) {
+				return true
+			}
+		}
+	case string:
+		return strings.Contains(v, arg)
+	case map[string]interface{}:
+		_, ok := v[arg]
+		return ok
+	}
+
+	return false
+}
+
+// betweenValues reports whether a number or time lies within the inclusive
+// range [lo, hi]. Times may be given as time.Time or RFC 3339 strings.
+func betweenValues(value interface{}, lo, hi string) (bool, error) {
+	switch v := value.(type) {
+	case float64:
+		l, err := strconv.ParseFloat(lo, 64)
+		if err != nil {
+			return false, err
+		}
+
+		h, err := strconv.ParseFloat(hi, 64)
+		if err != nil {
+			return false, err
+		}
+
+		return v >= l && v <= h, nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return false, fmt.Errorf("value %q is neither a number nor a time", v)
+		}
+
+		value = t
+	}
+
+	t, ok := value.(time.Time)
+
+	if !ok {
+		return false, fmt.Errorf("value %v is neither a number nor a time", value)
+	}
+
+	l, err := time.Parse(time.RFC3339, lo)
+	if err != nil {
+		return false, err
+	}
+
+	h, err := time.Parse(time.RFC3339, hi)
+	if err != nil {
+		return false, err
+	}
+
+	return !t.Before(l) && !t.After(h), nil
+}
+
+// matchGlob reports whether s matches pattern, in which '*' matches any
+// sequence of characters and '?' matches any single character.
+func matchGlob(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	var pi, si int
+	star, mark := -1, 0
+
+	for si < len(str) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == str[si]):
+			pi++
+			si++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, si
+			pi++
+		case star >= 0:
+			// Backtrack, letting the last star swallow one more character
+			mark++
+			pi, si = star+1, mark
+		default:
+			return false
+		}
+	}
+
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+
+	return pi == len(p)
+}
+
+// callComparator calls the named method on value's type with arg, which is
+// converted to the method's parameter type, and returns its boolean result.
+func callComparator(value interface{}, method string, arg string) bool {
+	v := reflect.ValueOf(value)
+	m := v.MethodByName(method)
+
+	if !m.IsValid() || m.Type().NumIn() != 1 || m.Type().NumOut() != 1 || m.Type().Out(0) != reflect.TypeOf(true) {
+		panic(errorf(ErrInvalidTag, "mson: invalid function %s provided as argument to equals; function must exist on the type %v, take one parameter, and return one boolean value", method, v.Type()))
+	}
+
+	param := m.Type().In(0)
+	in := reflect.New(param)
+
+	if param.Kind() == reflect.String {
+		in.Elem().Set(reflect.ValueOf(arg).Convert(param))
+	} else if err := json.Unmarshal([]byte(arg), in.Interface()); err != nil {
+		panic(errorf(ErrInvalidTag, "mson: argument %s to equals cannot be converted to %v", arg, param))
+	}
+
+	return m.Call([]reflect.Value{in.Elem()})[0].Bool()
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// callParentMethod calls the named method of the struct being decoded with
+// value, converted to the method's parameter type through JSON if needed.
+// The method must return a single value, optionally followed by an error.
+func callParentMethod(parent reflect.Value, method string, value interface{}, fieldName string) (interface{}, error) {
+	var m reflect.Value
+
+	if parent.CanAddr() {
+		m = parent.Addr().MethodByName(method)
+	}
+
+	if !m.IsValid() {
+		m = parent.MethodByName(method)
+	}
+
+	if !m.IsValid() || m.Type().NumIn() != 1 || m.Type().NumOut() < 1 || m.Type().NumOut() > 2 || (m.Type().NumOut() == 2 && m.Type().Out(1) != errorType) {
+		panic(errorf(ErrInvalidTag, "mson: invalid function %s provided as argument to func; function must exist on the type %v, take one parameter, and return a value and optionally an error", method, parent.Type()))
+	}
+
+	param := m.Type().In(0)
+	in := reflect.New(param).Elem()
+
+	if value != nil && reflect.TypeOf(value).AssignableTo(param) {
+		in.Set(reflect.ValueOf(value))
+	} else if value != nil {
+		raw, err := json.Marshal(value)
+
+		if err == nil {
+			err = json.Unmarshal(raw, in.Addr().Interface())
+		}
+
+		if err != nil {
+			return nil, errorf(ErrTypeMismatch, "mson: field %s cannot be passed to %s as %v", fieldName, method, param)
+		}
+	}
+
+	out := m.Call([]reflect.Value{in})
+
+	if len(out) == 2 && !out[1].IsNil() {
+		return nil, fmt.Errorf("mson: %w, %s failed for field %s", out[1].Interface().(error), method, fieldName)
+	}
+
+	return out[0].Interface(), nil
+}
+
+// evaluateWhen checks a when condition against the incoming data of the
+// object being decoded. The condition <field>=<value> holds when the field
+// equals value, while a bare <field> holds when it is present and non-zero.
+func evaluateWhen(state *decodeState, condition string) bool {
+	name, want, hasValue := strings.Cut(condition, "=")
+	raw, ok := state.lookup(name)
+
+	if !ok {
+		return false
+	}
+
+	if !hasValue {
+		return raw != nil && !isEmptyValue(reflect.ValueOf(raw))
+	}
+
+	want = unquote(want)
+
+	if s, ok := raw.(string); ok {
+		return s == want
+	}
+
+	return compareInterfaceValue(raw, want)
+}
+
 func containsOption(options []string, target string) bool {
 	for _, option := range options {
 		if option == target {
@@ -106,6 +597,109 @@ func containsOption(options []string, target string) bool {
 	return false
 }
 
+func maskString(s, mode string) (string, error) {
+	runes := []rune(s)
+	keep := 0
+	fromEnd := true
+
+	switch {
+	case mode == "all":
+	case strings.HasPrefix(mode, "last"):
+		n, err := strconv.Atoi(mode[len("last"):])
+		if err != nil {
+			return "", fmt.Errorf("invalid mask mode %s", mode)
+		}
+		keep = n
+	case strings.HasPrefix(mode, "first"):
+		n, err := strconv.Atoi(mode[len("first"):])
+		if err != nil {
+			return "", fmt.Errorf("invalid mask mode %s", mode)
+		}
+		keep = n
+		fromEnd = false
+	default:
+		return "", fmt.Errorf("invalid mask mode %s", mode)
+	}
+
+	if keep > len(runes) {
+		keep = len(runes)
+	}
+
+	for i := range runes {
+		if (fromEnd && i < len(runes)-keep) || (!fromEnd && i >= keep) {
+			runes[i] = '*'
+		}
+	}
+
+	return string(runes), nil
+}
+
+func findField(rv reflect.Value, name string, s *settings) (reflect.Value, reflect.StructField, bool) {
+	for _, f := range structFields(rv.Type(), s) {
+		fieldName, _, _ := parseTag(f, s)
+
+		if !strings.EqualFold(fieldName, name) {
+			continue
+		}
+
+		if field, ok := fieldByIndex(rv, f.Index, false); ok {
+			return field, f, true
+		}
+	}
+
+	return reflect.Value{}, reflect.StructField{}, false
+}
+
+// isEmptyValue reports whether v is empty as defined by encoding/json's
+// omitempty: false, 0, a nil pointer or interface, or an empty array, slice,
+// map or string.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Ptr:
+		return v.IsZero()
+	}
+
+	return false
+}
+
+// isZeroValue reports whether v is the zero value of its type, deferring to an
+// IsZero() bool method when the type has one (as time.Time does).
+func isZeroValue(v reflect.Value) bool {
+	if m := v.MethodByName("IsZero"); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 && m.Type().Out(0).Kind() == reflect.Bool {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return true
+		}
+
+		return m.Call(nil)[0].Bool()
+	}
+
+	return v.IsZero()
+}
+
+// derefType returns the type t points to, through any number of pointers.
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+// derefValue follows v's non-nil pointers without allocating.
+func derefValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v
+}
+
 func stripPointer(v reflect.Value) reflect.Value {
 	for v.Kind() == reflect.Ptr {
 		if v.IsNil() {
@@ -118,6 +712,46 @@ func stripPointer(v reflect.Value) reflect.Value {
 	return v
 }
 
+// emptyValue replaces null with an empty slice, map or struct of the field's
+// type for nilslice, nilmap and nilstruct, so that the field is allocated
+// rather than left nil. Inverted, it replaces an empty array or object with
+// null instead.
+func emptyValue(value interface{}, verb string, inverted bool, target reflect.Type, fieldName string) (interface{}, error) {
+	kind := map[string]reflect.Kind{"nilslice": reflect.Slice, "nilmap": reflect.Map, "nilstruct": reflect.Struct}[verb]
+
+	if inverted {
+		// Objects arrive as maps, including those meant for a struct
+		v := reflect.ValueOf(value)
+
+		if kind == reflect.Struct {
+			kind = reflect.Map
+		}
+
+		if v.Kind() == kind && v.Len() == 0 {
+			return nil, nil
+		}
+
+		return value, nil
+	}
+
+	if value != nil {
+		return value, nil
+	}
+
+	if target.Kind() != kind {
+		return nil, errorf(ErrTypeMismatch, "mson: cannot convert field %s to a new %s; field is of kind %s, not a %s", fieldName, kind, target.Kind(), kind)
+	}
+
+	switch kind {
+	case reflect.Slice:
+		return reflect.MakeSlice(target, 0, 0).Interface(), nil
+	case reflect.Map:
+		return reflect.MakeMap(target).Interface(), nil
+	default:
+		return reflect.New(target).Elem().Interface(), nil
+	}
+}
+
 func performArithmeticOperation(value interface{}, parts []string, inverted bool, fieldName string) (interface{}, error) {
 	var op1 func(int64, int64) int64
 	var op2 func(float64, float64) float64
@@ -136,11 +770,11 @@ func performArithmeticOperation(value interface{}, parts []string, inverted bool
 		op1 = func(a, b int64) int64 { return a / b }
 		op2 = func(a, b float64) float64 { return a / b }
 	default:
-		panic(fmt.Errorf("mson: unknown numerical operation %s", parts[0]))
+		panic(errorf(ErrUnknownOption, "mson: unknown numerical operation %s", parts[0]))
 	}
 
 	if len(parts) < 2 {
-		panic(fmt.Errorf("mson: tag option '%s' requires at least one argument", parts[0]))
+		panic(errorf(ErrInvalidTag, "mson: tag option '%s' requires at least one argument", parts[0]))
 	}
 
 	if conv, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
@@ -150,7 +784,7 @@ func performArithmeticOperation(value interface{}, parts []string, inverted bool
 		case float64:
 			value = op2(v, float64(conv))
 		default:
-			return nil, fmt.Errorf("mson: field %s is not a number", fieldName)
+			return nil, errorf(ErrTypeMismatch, "mson: field %s is not a number", fieldName)
 		}
 
 		return value, nil
@@ -163,13 +797,52 @@ func performArithmeticOperation(value interface{}, parts []string, inverted bool
 		case float64:
 			value = op2(v, conv)
 		default:
-			return nil, fmt.Errorf("mson: field %s is not a number", fieldName)
+			return nil, errorf(ErrTypeMismatch, "mson: field %s is not a number", fieldName)
 		}
 
 		return value, nil
 	}
 
-	return nil, fmt.Errorf("mson: tag option '%s' received invalid argument %s", parts[0], parts[1])
+	return nil, errorf(ErrInvalidTag, "mson: tag option '%s' received invalid argument %s", parts[0], parts[1])
+}
+
+// inverseOperations maps each arithmetic option to the one undoing it.
+var inverseOperations = map[string]string{"add": "subtract", "subtract": "add", "multiply": "divide", "divide": "multiply"}
+
+// invertArithmetic undoes add, subtract, multiply and divide for Marshal.
+// Integer fields stay integers when the result is a whole number.
+func invertArithmetic(value interface{}, parts []string, fieldName string) (interface{}, error) {
+	rv := derefValue(reflect.ValueOf(value))
+
+	// Null stays null
+	if !rv.IsValid() || rv.Kind() == reflect.Ptr {
+		return value, nil
+	}
+
+	var f float64
+
+	switch {
+	case rv.CanInt():
+		f = float64(rv.Int())
+	case rv.CanUint():
+		f = float64(rv.Uint())
+	case rv.CanFloat():
+		f = rv.Float()
+	default:
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a number", fieldName)
+	}
+
+	v, err := performArithmeticOperation(f, append([]string{inverseOperations[parts[0]]}, parts[1:]...), false, fieldName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if n := v.(float64); !rv.CanFloat() && n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+		return int64(n), nil
+	}
+
+	return v, nil
 }
 
 func performNumericalOperation(value interface{}, parts []string, inverted bool, fieldName string) (interface{}, error) {
@@ -183,7 +856,7 @@ func performNumericalOperation(value interface{}, parts []string, inverted bool,
 	case "ceil":
 		op = math.Ceil
 	default:
-		panic(fmt.Errorf("mson: unknown numerical operation %s", parts[0]))
+		panic(errorf(ErrUnknownOption, "mson: unknown numerical operation %s", parts[0]))
 	}
 
 	var places uint8
@@ -191,7 +864,7 @@ func performNumericalOperation(value interface{}, parts []string, inverted bool,
 	if len(parts) > 1 {
 		p, err := strconv.ParseUint(parts[1], 10, 8)
 		if err != nil {
-			return nil, fmt.Errorf("mson: tag option 'round' received invalid argument %s", parts[1])
+			return nil, errorf(ErrInvalidTag, "mson: tag option 'round' received invalid argument %s", parts[1])
 		}
 		places = uint8(p)
 	}
@@ -199,7 +872,7 @@ func performNumericalOperation(value interface{}, parts []string, inverted bool,
 	switch v := value.(type) {
 	case float64:
 		if places == 0 {
-			value = int64(op(v))
+			v = op(v)
 		} else if inverted {
 			for i := uint8(0); i < places; i++ {
 				v /= 10
@@ -232,8 +905,139 @@ func performNumericalOperation(value interface{}, parts []string, inverted bool,
 			value = conv
 		}
 	default:
-		return nil, fmt.Errorf("mson: field %s is not a number", fieldName)
+		return nil, errorf(ErrTypeMismatch, "mson: field %s is not a number", fieldName)
 	}
 
 	return value, nil
 }
+
+// unquoteLayers decodes a string-encoded JSON document, unwrapping as many
+// layers of encoding as the fromstring argument asks for. In auto mode it
+// keeps unwrapping while the value is a string holding valid JSON.
+func unquoteLayers(s string, parts []string, target reflect.Type, fieldName string) (interface{}, error) {
+	layers, auto := 1, false
+
+	if len(parts) > 1 {
+		if parts[1] == "auto" {
+			auto = true
+		} else if n, err := strconv.Atoi(parts[1]); err == nil && n > 0 {
+			layers = n
+		} else {
+			panic(errorf(ErrInvalidTag, "mson: tag option 'fromstring' received invalid argument %s", parts[1]))
+		}
+	}
+
+	var value interface{} = s
+
+	for i := 0; auto || i < layers; i++ {
+		str, ok := value.(string)
+
+		if !ok {
+			if auto {
+				break
+			}
+
+			return nil, errorf(ErrTypeMismatch, "mson: field %s is not string-encoded %d times", fieldName, layers)
+		}
+
+		var decoded interface{}
+
+		if err := json.Unmarshal([]byte(str), &decoded); err != nil {
+			// A string that is not JSON ends auto mode, unless nothing was unwrapped
+			if auto && i > 0 {
+				break
+			}
+
+			return nil, fmt.Errorf("%w, unquoting of field %s to %v failed", fmt.Errorf(strings.Replace(err.Error(), "json", "mson", 1)), fieldName, target)
+		}
+
+		value = decoded
+	}
+
+	return value, nil
+}
+
+// parseStringOption decodes a scalar sent as a string, as encoding/json does
+// for fields tagged with ",string". Values that are already of a scalar kind
+// are accepted as well, so payloads may mix both forms.
+func parseStringOption(value interface{}, target reflect.Type, fieldName string) (interface{}, error) {
+	s, ok := value.(string)
+
+	if !ok || isBigType(target) {
+		return value, nil
+	}
+
+	var (
+		parsed interface{}
+		err    error
+	)
+
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err = strconv.ParseInt(s, 10, target.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		parsed, err = strconv.ParseUint(s, 10, target.Bits())
+	case reflect.Float32, reflect.Float64:
+		parsed, err = strconv.ParseFloat(s, target.Bits())
+	case reflect.Bool:
+		parsed, err = strconv.ParseBool(s)
+	case reflect.String:
+		// encoding/json quotes strings a second time
+		var unquoted string
+
+		if json.Unmarshal([]byte(s), &unquoted) == nil {
+			s = unquoted
+		}
+
+		parsed = s
+	default:
+		return value, nil
+	}
+
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return nil, errorf(ErrOverflow, "mson: field %s holds %q, which overflows %v", fieldName, s, target)
+		}
+
+		return nil, errorf(ErrTypeMismatch, "mson: field %s holds %q, which is not a valid %v", fieldName, s, target)
+	}
+
+	return reflect.ValueOf(parsed).Convert(target).Interface(), nil
+}
+
+// formatStringOption encodes a scalar as a string, the inverse of
+// parseStringOption.
+func formatStringOption(value interface{}) interface{} {
+	rv := reflect.ValueOf(value)
+
+	switch {
+	case !rv.IsValid():
+		return value
+	case rv.Type() == jsonNumberType:
+		return rv.String()
+	case isBigType(rv.Type()):
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return value
+		}
+
+		return fmt.Sprint(encodeBig(rv))
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.String:
+		// Quoted as JSON rather than Go, whose escapes such as \x00 and
+		// \U0001F600 JSON does not have
+		b, _ := json.Marshal(rv.String())
+		return string(b)
+	}
+
+	return value
+}
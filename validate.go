@@ -0,0 +1,158 @@
+package mson
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Validate re-runs the checks of the validating tag options against the
+// struct pointed to or held by v, so that values changed after decoding can be
+// checked again before they are persisted. The options checked are required,
+// min and max with a bound, regex, oneof, which is always strict here, and
+// the options that reject malformed values, such as country, email, hostname
+// or latlng; options that transform values are not applied. Since a zero
+// value cannot be told apart from an absent key, required rejects zero
+// values, and the other options skip them. Nested structs, and slices and
+// maps of them, are validated as well. All failures are returned joined
+// together.
+func Validate(v any, opts ...Option) error {
+	s := &settings{}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return validate(v, s)
+}
+
+// Validate is like the package-level Validate, using the codec's options.
+func (c *Codec) Validate(v any) error {
+	return validate(v, &c.settings)
+}
+
+func validate(v any, s *settings) error {
+	rv := derefValue(reflect.ValueOf(v))
+
+	if rv.Kind() != reflect.Struct {
+		return errors.New("mson: Validate requires a struct or a pointer to a struct")
+	}
+
+	var errs []error
+
+	validateStruct(rv, s, &errs)
+
+	return errors.Join(errs...)
+}
+
+func validateStruct(rv reflect.Value, s *settings, errs *[]error) {
+	for _, f := range structFields(rv.Type(), s) {
+		fieldName, tagOptions, _ := parseTag(f, s)
+		field, ok := fieldByIndex(rv, f.Index, false)
+
+		if !ok {
+			// A nil embedded struct holds nothing to validate
+			continue
+		}
+
+		options := fieldOptions(f.Type, tagOptions, s)
+
+		if containsOption(options, "required") && field.IsZero() {
+			*errs = append(*errs, errorf(ErrFieldMissing, "mson: field %s is required", fieldName))
+			continue
+		}
+
+		field = derefValue(field)
+
+		if field.Kind() == reflect.Ptr {
+			// A nil pointer holds nothing to validate
+			continue
+		}
+
+		// Zero values stand for absent keys, which are not validated
+		// when decoding either
+		for _, opt := range options {
+			if field.IsZero() {
+				break
+			}
+
+			if err := validateOption(field, splitIgnoreQuoted(opt, ','), fieldName); err != nil {
+				*errs = append(*errs, err)
+			}
+		}
+
+		validateNested(field, s, errs)
+	}
+}
+
+func validateNested(v reflect.Value, s *settings, errs *[]error) {
+	v = derefValue(v)
+
+	switch v.Kind() {
+	case reflect.Struct:
+		validateStruct(v, s, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateNested(v.Index(i), s, errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+
+		for iter.Next() {
+			validateNested(iter.Value(), s, errs)
+		}
+	}
+}
+
+func validateOption(field reflect.Value, parts []string, fieldName string) error {
+	value := field.Interface()
+
+	if isBound(parts) {
+		return checkBound(value, parts, fieldName)
+	}
+
+	switch parts[0] {
+	case "regex":
+		return matchPattern(value, parts, fieldName)
+	case "oneof":
+		if len(parts) < 2 {
+			panic(errorf(ErrInvalidTag, "mson: tag option 'oneof' requires a list of accepted values"))
+		}
+
+		accepted := strings.Split(unquote(parts[1]), "|")
+
+		if s := fmt.Sprint(value); !containsOption(accepted, s) {
+			return &UnknownEnumValueError{Field: fieldName, Value: s, Accepted: accepted}
+		}
+	case "country":
+		_, err := normalizeCountry(value, parts, fieldName)
+		return err
+	case "currency":
+		_, err := normalizeCurrency(value, parts, fieldName)
+		return err
+	case "phone":
+		_, err := normalizePhone(value, parts, fieldName)
+		return err
+	case "hostname":
+		_, err := validateHostname(value, parts, fieldName)
+		return err
+	case "email":
+		_, err := normalizeEmail(value, parts, fieldName)
+		return err
+	case "mime":
+		_, err := validateMIME(value, parts, fieldName)
+		return err
+	case "ext":
+		_, err := validateExtension(value, parts, fieldName)
+		return err
+	case "latlng":
+		if p, ok := value.(Point); ok {
+			if err := validatePoint(p); err != nil {
+				return fmt.Errorf("mson: %w, validation of field %s failed", err, fieldName)
+			}
+		}
+	}
+
+	return nil
+}
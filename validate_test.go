@@ -0,0 +1,203 @@
+package mson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/monerowner/mson"
+)
+
+type validatedAddress struct {
+	Country string     `json:"country,country"`
+	Where   mson.Point `json:"where,latlng"`
+}
+
+type validatedUser struct {
+	Role      string                       `json:"role,oneof,\"admin|user\""`
+	Phone     string                       `json:"phone,phone"`
+	Addresses []validatedAddress           `json:"addresses"`
+	ByName    map[string]*validatedAddress `json:"by_name"`
+	Nickname  string                       `json:"nickname,kebab"`
+}
+
+func TestValidate(t *testing.T) {
+	valid := validatedUser{
+		Role:      "admin",
+		Phone:     "+442079460958",
+		Addresses: []validatedAddress{{Country: "DE", Where: mson.Point{Lat: 52.5, Lng: 13.4}}},
+		ByName:    map[string]*validatedAddress{"home": {Country: "FR"}, "none": nil},
+		Nickname:  "Not Kebab",
+	}
+
+	if err := mson.Validate(&valid); err != nil {
+		t.Fatalf("got %v for a valid struct", err)
+	}
+
+	// Zero values stand for absent keys and are not checked
+	if err := mson.Validate(validatedUser{}); err != nil {
+		t.Fatalf("got %v for a zero struct", err)
+	}
+
+	invalid := valid
+	invalid.Role = "root"
+	invalid.Phone = "call me"
+	invalid.Addresses = []validatedAddress{{Country: "Atlantis", Where: mson.Point{Lat: 100}}}
+
+	err := mson.Validate(invalid)
+
+	var enumErr *mson.UnknownEnumValueError
+
+	if !errors.As(err, &enumErr) || enumErr.Value != "root" {
+		t.Fatalf("got %v", err)
+	}
+
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 4 {
+		t.Errorf("got %d errors, want 4: %v", n, err)
+	}
+
+	if err := mson.Validate(42); err == nil {
+		t.Error("validated a value that is not a struct")
+	}
+}
+
+func TestValidateUploadOptions(t *testing.T) {
+	type upload struct {
+		Type string `json:"type,mime,\"image/*\""`
+		Name string `json:"name,ext,png"`
+	}
+
+	if err := mson.Validate(upload{Type: "image/png", Name: "a.png"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mson.Validate(upload{Type: "text/html", Name: "a.gif"}); err == nil || len(err.(interface{ Unwrap() []error }).Unwrap()) != 2 {
+		t.Fatalf("got %v, want errors for both fields", err)
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	type contact struct {
+		Email string `json:"email,email"`
+	}
+
+	if err := mson.Validate(contact{Email: "ada@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var validationErr *mson.ValidationError
+
+	if err := mson.Validate(contact{Email: "not an address"}); !errors.As(err, &validationErr) || validationErr.Field != "email" {
+		t.Fatalf("got %v, want a ValidationError", err)
+	}
+}
+
+func TestValidateHostname(t *testing.T) {
+	type server struct {
+		Host string `json:"host,hostname,fqdn"`
+	}
+
+	if err := mson.Validate(server{Host: "example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var validationErr *mson.ValidationError
+
+	if err := mson.Validate(server{Host: "localhost"}); !errors.As(err, &validationErr) || validationErr.Field != "host" {
+		t.Fatalf("got %v, want a ValidationError", err)
+	}
+}
+
+type signup struct {
+	Name   string   `json:"name,required,min=2,max=5"`
+	Age    int      `json:"age,min=18"`
+	Tags   []string `json:"tags,max=2"`
+	Code   string   `json:"code,regex=\"^[A-Z]{3}$\""`
+	Ref    *string  `json:"ref,required"`
+	Lowest float64  `json:"lowest,min"`
+}
+
+func TestConstraintOptions(t *testing.T) {
+	var v signup
+
+	if err := mson.Unmarshal([]byte(`{"name":"ada","age":36,"tags":["a"],"code":"ABC","ref":"x","lowest":[3,1,2]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Name != "ada" || v.Age != 36 || v.Code != "ABC" || *v.Ref != "x" || v.Lowest != 1 {
+		t.Fatalf("got %+v", v)
+	}
+
+	tests := []struct {
+		data  string
+		want  error
+		field string
+	}{
+		{`{"ref":"x"}`, mson.ErrFieldMissing, "name"},
+		{`{"name":"ada","ref":null}`, mson.ErrFieldMissing, "ref"},
+		{`{"name":"a","ref":"x"}`, nil, "name"},
+		{`{"name":"ada lovelace","ref":"x"}`, nil, "name"},
+		{`{"name":"ada","age":17,"ref":"x"}`, nil, "age"},
+		{`{"name":"ada","tags":["a","b","c"],"ref":"x"}`, nil, "tags"},
+		{`{"name":"ada","code":"abc","ref":"x"}`, nil, "code"},
+	}
+
+	for _, tt := range tests {
+		err := mson.Unmarshal([]byte(tt.data), &v)
+
+		var fieldErr *mson.FieldError
+		var validationErr *mson.ValidationError
+
+		if !errors.As(err, &fieldErr) || fieldErr.Field != tt.field {
+			t.Errorf("decoding %s returned %v, want an error at %s", tt.data, err, tt.field)
+		} else if tt.want != nil && !errors.Is(err, tt.want) || tt.want == nil && !errors.As(err, &validationErr) {
+			t.Errorf("decoding %s returned %v, want %v", tt.data, err, tt.want)
+		}
+	}
+}
+
+func TestValidateConstraints(t *testing.T) {
+	ref := "x"
+
+	if err := mson.Validate(signup{Name: "ada", Age: 36, Tags: []string{"a"}, Code: "ABC", Ref: &ref}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := mson.Validate(signup{Name: "a", Age: 17, Tags: []string{"a", "b", "c"}, Code: "abc"})
+
+	if !errors.Is(err, mson.ErrFieldMissing) {
+		t.Fatalf("got %v, want ErrFieldMissing for ref", err)
+	}
+
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 5 {
+		t.Errorf("got %d errors, want 5: %v", n, err)
+	}
+
+	// Zero values are only rejected by required
+	if err := mson.Validate(signup{Ref: &ref}); !errors.Is(err, mson.ErrFieldMissing) || strings.Contains(err.Error(), "at least") {
+		t.Errorf("got %v, want only the missing name", err)
+	}
+}
+
+func TestValidateWithOptions(t *testing.T) {
+	type account struct {
+		Name  string        `api:"name,required" json:"name"`
+		Limit time.Duration `json:"limit"`
+	}
+
+	v := account{Limit: time.Hour}
+	opts := []mson.Option{mson.WithTagKeys("api", "json"), mson.WithTypeOptions(time.Duration(0), "max=60")}
+
+	if err := mson.Validate(v); err != nil {
+		t.Fatalf("got %v without options", err)
+	}
+
+	var validationErr *mson.ValidationError
+
+	for _, err := range []error{mson.Validate(v, opts...), mson.NewCodec(opts...).Validate(v)} {
+		if !errors.Is(err, mson.ErrFieldMissing) || !errors.As(err, &validationErr) || validationErr.Field != "limit" {
+			t.Errorf("got %v, want the missing name and the limit", err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package mson_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/monerowner/mson"
+)
+
+func TestUnmarshalVersion(t *testing.T) {
+	type user struct {
+		Name     string `json:"name"`
+		Nickname string `json:"nickname,since,2.0"`
+		Login    string `json:"login,until,2.0"`
+	}
+
+	data := []byte(`{"name":"ada","nickname":"countess","login":"ada1"}`)
+
+	tests := []struct {
+		version string
+		want    user
+	}{
+		{"1.5", user{Name: "ada", Login: "ada1"}},
+		{"2.0", user{Name: "ada", Nickname: "countess"}},
+		{"2.1", user{Name: "ada", Nickname: "countess"}},
+		{"1.10", user{Name: "ada", Login: "ada1"}},
+	}
+
+	for _, tt := range tests {
+		v := user{Nickname: "stale", Login: "stale"}
+
+		if err := mson.UnmarshalVersion(data, &v, tt.version); err != nil {
+			t.Fatal(err)
+		}
+
+		if v != tt.want {
+			t.Errorf("version %s got %+v, want %+v", tt.version, v, tt.want)
+		}
+	}
+
+	// Without a version every field is decoded
+	var v user
+
+	if err := mson.Unmarshal(data, &v); err != nil || v != (user{Name: "ada", Nickname: "countess", Login: "ada1"}) {
+		t.Errorf("got %+v, %v", v, err)
+	}
+}
+
+func TestUnmarshalVersionRequiresFields(t *testing.T) {
+	type address struct {
+		Street string `json:"street"`
+		Zip    string `json:"zip,since,2.0"`
+	}
+
+	type user struct {
+		Name    string  `json:"name"`
+		Email   string  `json:"email,since,1.5,until,3.0"`
+		Login   string  `json:"login,until,2.0"`
+		Address address `json:"address"`
+	}
+
+	tests := []struct {
+		version string
+		data    string
+		missing string
+	}{
+		{"1.0", `{"login":"ada1"}`, ""},
+		{"1.0", `{}`, "login"},
+		{"1.5", `{"login":"ada1"}`, "email"},
+		{"2.0", `{"email":"ada@example.com","address":{}}`, "address.zip"},
+		{"2.0", `{"email":"ada@example.com","address":{"zip":"10115"}}`, ""},
+		{"3.0", `{"address":{"zip":"10115"}}`, ""},
+		{"3.0", `{"email":null,"address":{"zip":null}}`, ""},
+	}
+
+	for _, tt := range tests {
+		var v user
+		err := mson.UnmarshalVersion([]byte(tt.data), &v, tt.version)
+
+		if tt.missing == "" {
+			if err != nil {
+				t.Errorf("version %s of %s returned %v", tt.version, tt.data, err)
+			}
+
+			continue
+		}
+
+		var fieldErr *mson.FieldError
+
+		if !errors.Is(err, mson.ErrFieldMissing) || !errors.As(err, &fieldErr) || fieldErr.Field != tt.missing {
+			t.Errorf("version %s of %s returned %v, want %s missing", tt.version, tt.data, err, tt.missing)
+		}
+	}
+
+	// Without a version no field is required
+	var v user
+
+	if err := mson.Unmarshal([]byte(`{}`), &v); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeprecatedOption(t *testing.T) {
+	var v struct {
+		Name   string `json:"name"`
+		Handle string `json:"handle,deprecated"`
+		Old    string `json:"old,deprecated,\"use-name\""`
+	}
+
+	var warnings []mson.Warning
+
+	onWarning := mson.WithWarnings(func(w mson.Warning) {
+		warnings = append(warnings, w)
+	})
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"name":"ada","handle":"@ada"}`), &v, onWarning); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.Handle != "@ada" || len(warnings) != 1 || warnings[0].Field != "handle" {
+		t.Fatalf("got %+v and warnings %v", v, warnings)
+	}
+
+	warnings = nil
+
+	if err := mson.UnmarshalWithOptions([]byte(`{"name":"ada","old":"x"}`), &v, onWarning); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(warnings) != 1 || warnings[0].Field != "old" || !strings.HasSuffix(warnings[0].Message, ": use-name") {
+		t.Fatalf("got warnings %v", warnings)
+	}
+}
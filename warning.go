@@ -0,0 +1,44 @@
+package mson
+
+import "strings"
+
+// A Warning describes a problem with the decoded data that did not prevent
+// the decode from succeeding.
+type Warning struct {
+	// Field is the dotted path of the field or key the warning is about,
+	// such as "user.address.zip".
+	Field   string
+	Message string
+}
+
+func (w Warning) String() string {
+	return "mson: " + w.Message
+}
+
+// UnmarshalWithWarnings is like UnmarshalWithOptions, but also returns the
+// warnings raised while decoding, such as deprecated or unknown keys, so that
+// data quality can be monitored without failing the decode.
+func UnmarshalWithWarnings(data []byte, v any, opts ...Option) ([]Warning, error) {
+	var warnings []Warning
+
+	opts = append(opts[:len(opts):len(opts)], WithWarnings(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+
+	err := UnmarshalWithOptions(data, v, opts...)
+
+	return warnings, err
+}
+
+func (s *decodeState) warn(w Warning) {
+	if s.onWarning != nil {
+		s.onWarning(w)
+	}
+}
+
+// currentPath returns the dotted path of the field being processed, which
+// processField pushes onto the path, so that warnings about fields of nested
+// structs can be told apart.
+func (s *decodeState) currentPath() string {
+	return strings.Join(s.path, ".")
+}